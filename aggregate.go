@@ -0,0 +1,108 @@
+package faststringmap
+
+import "cmp"
+
+// PrefixAggregate[T, A] is an index of the aggregates of the values of all
+// keys under every prefix of a map, such as the sum of the frequencies of
+// the words starting with a prefix. Each aggregate is the fold of the
+// values of the keys under a prefix in ascending key order, computed once
+// when the index is built, so a query costs a walk down the prefix.
+type PrefixAggregate[T, A any] struct {
+	m    *Map[T]
+	aggs []A // aggregate of the subtree of each node in m.store
+	zero A
+}
+
+// NewPrefixAggregate[T, A] builds the aggregates of m, folding the values
+// of the keys under each prefix with combine, starting from zero. Building
+// calls combine once per byte of every key. The map must not change while
+// the index is in use.
+func NewPrefixAggregate[T, A any](m *Map[T], zero A, combine func(A, T) A) PrefixAggregate[T, A] {
+	pa := PrefixAggregate[T, A]{m: m, zero: zero}
+	if m == nil || len(m.store) == 0 {
+		return pa
+	}
+
+	pa.aggs = make([]A, len(m.store))
+	for i := range m.store {
+		node := &m.store[i]
+		pa.aggs[i] = zero
+		if node.valueOffset == 0 && node.nextLen == 0 {
+			continue // not a valid byte
+		}
+
+		first, last := m.subtreeRange(node)
+		for index := first; index <= last; index++ {
+			t, _ := m.AtIndex(index)
+			pa.aggs[i] = combine(pa.aggs[i], t)
+		}
+	}
+	return pa
+}
+
+// UnderPrefix returns the aggregate of the values of all keys starting with
+// p, including p itself, or the zero aggregate if there are none. Like
+// IndicesUnderPrefix, p is folded or transformed like a probe.
+func (pa *PrefixAggregate[T, A]) UnderPrefix(p string) A {
+	i, ok := pa.nodeIndex(p)
+	if !ok {
+		return pa.zero
+	}
+	return pa.aggs[i]
+}
+
+// nodeIndex returns the index in the node store of the node reached by p,
+// canonicalized like a probe, if some key starts with p.
+func (pa *PrefixAggregate[T, A]) nodeIndex(p string) (Uint, bool) {
+	if len(pa.aggs) == 0 {
+		return 0, false
+	}
+	return pa.m.prefixIndex(p)
+}
+
+// PrefixExtrema[T] is an index of the smallest and largest values of the
+// keys under every prefix of a map, such as the cheapest and dearest prices
+// of the products whose codes start with a category code. Like
+// PrefixAggregate, it is computed once when the index is built, so a query
+// costs a walk down the prefix. Values are ordered as by cmp.Compare, so
+// NaNs are smaller than all other values.
+type PrefixExtrema[T cmp.Ordered] struct {
+	pa PrefixAggregate[T, extrema[T]]
+}
+
+type extrema[T cmp.Ordered] struct {
+	min, max T
+	ok       bool // whether there are any values
+}
+
+// NewPrefixExtrema[T] builds the extrema of the values of m under each
+// prefix. The map must not change while the index is in use.
+func NewPrefixExtrema[T cmp.Ordered](m *Map[T]) PrefixExtrema[T] {
+	return PrefixExtrema[T]{NewPrefixAggregate(m, extrema[T]{}, func(e extrema[T], v T) extrema[T] {
+		if !e.ok {
+			return extrema[T]{v, v, true}
+		}
+		if cmp.Less(v, e.min) {
+			e.min = v
+		}
+		if cmp.Less(e.max, v) {
+			e.max = v
+		}
+		return e
+	})}
+}
+
+// MinUnderPrefix returns the smallest value of the keys starting with p,
+// including p itself. ok is false if there are none. Like
+// IndicesUnderPrefix, p is folded or transformed like a probe.
+func (pe *PrefixExtrema[T]) MinUnderPrefix(p string) (t T, ok bool) {
+	e := pe.pa.UnderPrefix(p)
+	return e.min, e.ok
+}
+
+// MaxUnderPrefix returns the largest value of the keys starting with p,
+// like MinUnderPrefix.
+func (pe *PrefixExtrema[T]) MaxUnderPrefix(p string) (t T, ok bool) {
+	e := pe.pa.UnderPrefix(p)
+	return e.max, e.ok
+}
@@ -0,0 +1,97 @@
+package faststringmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestPrefixAggregate(t *testing.T) {
+	m := faststringmap.FromMap(map[string]int{
+		"car": 10, "card": 3, "care": 5, "cat": 7, "dog": 2, "": 1,
+	})
+	sum := faststringmap.NewPrefixAggregate(&m, 0, func(a, v int) int { return a + v })
+
+	tests := []struct {
+		p    string
+		want int
+	}{
+		{"", 28},
+		{"c", 25},
+		{"car", 18},
+		{"card", 3},
+		{"cb", 0},
+		{"d", 2},
+		{"e", 0},
+		{"cards", 0},
+	}
+	for _, tt := range tests {
+		if got := sum.UnderPrefix(tt.p); got != tt.want {
+			t.Errorf("UnderPrefix(%q) = %d want %d", tt.p, got, tt.want)
+		}
+	}
+
+	// aggregates fold values in ascending key order
+	words := faststringmap.FromMap(map[string]string{"b": "b", "a": "a", "ab": "ab", "c": "c"})
+	joined := faststringmap.NewPrefixAggregate(&words, "", func(a, v string) string {
+		return strings.TrimPrefix(a+","+v, ",")
+	})
+	if got := joined.UnderPrefix(""); got != "a,ab,b,c" {
+		t.Errorf("UnderPrefix(\"\") = %q want %q", got, "a,ab,b,c")
+	}
+
+	var empty faststringmap.Map[int]
+	none := faststringmap.NewPrefixAggregate(&empty, -1, func(a, v int) int { return a + v })
+	if got := none.UnderPrefix(""); got != -1 {
+		t.Errorf("UnderPrefix(\"\") of an empty map = %d want -1", got)
+	}
+}
+
+func TestPrefixExtrema(t *testing.T) {
+	prices := faststringmap.FromMap(map[string]float64{
+		"A1": 9.5, "A10": 3.25, "A11": 12, "A2": 7, "B1": 1.5, "B2": -4,
+	})
+	pe := faststringmap.NewPrefixExtrema(&prices)
+
+	tests := []struct {
+		p        string
+		min, max float64
+		ok       bool
+	}{
+		{"", -4, 12, true},
+		{"A", 3.25, 12, true},
+		{"A1", 3.25, 12, true},
+		{"A11", 12, 12, true},
+		{"B", -4, 1.5, true},
+		{"C", 0, 0, false},
+		{"A3", 0, 0, false},
+		{"A111", 0, 0, false},
+	}
+	for _, tt := range tests {
+		if v, ok := pe.MinUnderPrefix(tt.p); v != tt.min || ok != tt.ok {
+			t.Errorf("MinUnderPrefix(%q) = %v, %v want %v, %v", tt.p, v, ok, tt.min, tt.ok)
+		}
+		if v, ok := pe.MaxUnderPrefix(tt.p); v != tt.max || ok != tt.ok {
+			t.Errorf("MaxUnderPrefix(%q) = %v, %v want %v, %v", tt.p, v, ok, tt.max, tt.ok)
+		}
+	}
+
+	folded, err := faststringmap.New([]faststringmap.MapEntry[float64]{{"a1", 9.5}, {"A2", 7}, {"b1", 1.5}}, faststringmap.WithFold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fe := faststringmap.NewPrefixExtrema(&folded)
+	if v, ok := fe.MinUnderPrefix("A"); v != 7 || !ok {
+		t.Errorf("MinUnderPrefix(A) of a folded map = %v, %v want 7, true", v, ok)
+	}
+	if v, ok := fe.MaxUnderPrefix("a"); v != 9.5 || !ok {
+		t.Errorf("MaxUnderPrefix(a) of a folded map = %v, %v want 9.5, true", v, ok)
+	}
+
+	var empty faststringmap.Map[int]
+	none := faststringmap.NewPrefixExtrema(&empty)
+	if v, ok := none.MinUnderPrefix(""); ok {
+		t.Errorf("MinUnderPrefix(\"\") of an empty map = %v, true", v)
+	}
+}
@@ -0,0 +1,93 @@
+package faststringmap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AliasResolver maps the many spellings of names such as flags or
+// environment variables to their canonical names. Spellings match if they
+// are equal ignoring ASCII case and the separators '_', '-' and '.', so
+// "HTTP_PROXY", "http_proxy", "HttpProxy" and "http-proxy" all resolve to
+// the same name. Resolving never allocates, so it can be used on hot
+// configuration paths. An AliasResolver is read only, and safe for
+// concurrent use.
+type AliasResolver struct {
+	m     Map[int] // normalized spelling -> index in names
+	names []string
+}
+
+// NewAliasResolver constructs an AliasResolver for the supplied canonical
+// names, and for additional aliases, which map spellings that do not match
+// their canonical name, such as abbreviations, to it. It returns an error if
+// two canonical names or aliases have matching spellings, or an alias refers
+// to a name that is not one of names.
+func NewAliasResolver(names []string, aliases map[string]string) (*AliasResolver, error) {
+	r := &AliasResolver{names: append([]string(nil), names...)}
+	entries := make([]MapEntry[int], 0, len(names)+len(aliases))
+	canonical := make(map[string]int, len(names))
+	spellings := make(map[string]string, len(names)+len(aliases))
+
+	add := func(spelling string, i int) error {
+		key := normalizeAlias(spelling)
+		if other, dup := spellings[key]; dup {
+			return fmt.Errorf("faststringmap: aliases %q and %q are spelled alike", other, spelling)
+		}
+		spellings[key] = spelling
+		entries = append(entries, MapEntry[int]{key, i})
+		return nil
+	}
+
+	for i, name := range names {
+		canonical[name] = i
+		if err := add(name, i); err != nil {
+			return nil, err
+		}
+	}
+	for alias, name := range aliases {
+		i, ok := canonical[name]
+		if !ok {
+			return nil, fmt.Errorf("faststringmap: alias %q of unknown name %q", alias, name)
+		}
+		if err := add(alias, i); err != nil {
+			return nil, err
+		}
+	}
+
+	r.m = NewMap(entries)
+	return r, nil
+}
+
+// Resolve returns the canonical name spelled s.
+func (r *AliasResolver) Resolve(s string) (name string, ok bool) {
+	i, ok := r.m.AtIndex(indexFold(&r.m, s, true))
+	if !ok {
+		return "", false
+	}
+	return r.names[i], true
+}
+
+// ResolveBytes returns the canonical name spelled s like Resolve.
+func (r *AliasResolver) ResolveBytes(s []byte) (name string, ok bool) {
+	i, ok := r.m.AtIndex(indexFold(&r.m, s, true))
+	if !ok {
+		return "", false
+	}
+	return r.names[i], true
+}
+
+// normalizeAlias returns s in lower case without separators, which is the
+// form indexFold matches when skipping separators.
+func normalizeAlias(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		} else if isFoldSeparator(b) {
+			continue
+		}
+		sb.WriteByte(b)
+	}
+	return sb.String()
+}
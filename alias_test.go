@@ -0,0 +1,75 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestAliasResolver(t *testing.T) {
+	r, err := faststringmap.NewAliasResolver(
+		[]string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "log-level"},
+		map[string]string{"proxy": "HTTP_PROXY", "verbosity": "log-level"},
+	)
+	if err != nil {
+		t.Fatalf("NewAliasResolver() error = %v", err)
+	}
+
+	for s, want := range map[string]string{
+		"HTTP_PROXY":  "HTTP_PROXY",
+		"http_proxy":  "HTTP_PROXY",
+		"HttpProxy":   "HTTP_PROXY",
+		"http-proxy":  "HTTP_PROXY",
+		"Proxy":       "HTTP_PROXY",
+		"httpsProxy":  "HTTPS_PROXY",
+		"no.proxy":    "NO_PROXY",
+		"LOG_LEVEL":   "log-level",
+		"--verbosity": "log-level",
+	} {
+		if got, ok := r.Resolve(s); !ok || got != want {
+			t.Errorf("Resolve(%q) = %q, %v want %q, true", s, got, ok, want)
+		}
+		if got, ok := r.ResolveBytes([]byte(s)); !ok || got != want {
+			t.Errorf("ResolveBytes(%q) = %q, %v want %q, true", s, got, ok, want)
+		}
+	}
+	for _, s := range []string{"", "http", "FTP_PROXY", "httpproxyx"} {
+		if got, ok := r.Resolve(s); ok {
+			t.Errorf("Resolve(%q) = %q, expected not to be present", s, got)
+		}
+	}
+
+	b := []byte("HttpProxy")
+	if allocs := testing.AllocsPerRun(100, func() { r.ResolveBytes(b) }); allocs != 0 {
+		t.Errorf("ResolveBytes() allocates %v times per run want 0", allocs)
+	}
+}
+
+func TestAliasResolverErrors(t *testing.T) {
+	if _, err := faststringmap.NewAliasResolver([]string{"HTTP_PROXY", "httpProxy"}, nil); err == nil {
+		t.Errorf("NewAliasResolver() of names spelled alike succeeded")
+	}
+	if _, err := faststringmap.NewAliasResolver([]string{"A"}, map[string]string{"b": "B"}); err == nil {
+		t.Errorf("NewAliasResolver() of an alias of an unknown name succeeded")
+	}
+}
+
+func TestLookupFold(t *testing.T) {
+	var b faststringmap.Builder[int]
+	b.SetFingerprints(true)
+	b.Add("content-type", 1)
+	b.Add("x-request-id", 2)
+	m := b.Build()
+
+	for s, want := range map[string]int{"Content-Type": 1, "CONTENT-TYPE": 1, "x-Request-ID": 2} {
+		if v, ok := m.LookupStringFold(s); !ok || v != want {
+			t.Errorf("LookupStringFold(%q) = %v, %v want %v, true", s, v, ok, want)
+		}
+		if v, ok := m.LookupBytesFold([]byte(s)); !ok || v != want {
+			t.Errorf("LookupBytesFold(%q) = %v, %v want %v, true", s, v, ok, want)
+		}
+	}
+	if v, ok := m.LookupStringFold("content_type"); ok {
+		t.Errorf("LookupStringFold(content_type) = %v, expected not to be present", v)
+	}
+}
@@ -0,0 +1,147 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+// Lookups never allocating is one of the main promises of this package, so
+// every way of building or loading a map is checked with every option that
+// affects lookups. Key transforms are checked with one that does not
+// allocate itself.
+func TestLookupAllocationsAllBackends(t *testing.T) {
+	entries := []faststringmap.MapEntry[uint32]{{"key1", 1}, {"key2", 2}, {"key22", 3}, {"other", 4}}
+	codec := faststringmap.IntCodec[uint32]{}
+
+	optionSets := map[string][]faststringmap.Option{
+		"default":      nil,
+		"fingerprints": {faststringmap.WithFingerprints()},
+		"retainKeys":   {faststringmap.WithRetainKeys()},
+		"fold":         {faststringmap.WithFold(), faststringmap.WithFingerprints()},
+		"keyTransform": {faststringmap.WithKeyTransform(bytes.TrimSpace)},
+	}
+	if minimalProfile {
+		delete(optionSets, "keyTransform") // string probes are copied to be transformed
+	}
+	for optName, opts := range optionSets {
+		m, err := faststringmap.New(entries, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var b faststringmap.Builder[uint32]
+		b.SetOptions(opts...)
+		for _, e := range entries {
+			b.Add(e.Key, e.Value)
+		}
+		into, _, err := b.BuildInto(make([]byte, 1<<10))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := m.AppendBinary(nil, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unmarshaled, err := faststringmap.UnmarshalMap(data, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lazy, err := faststringmap.UnmarshalMapLazy(data, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readerAt, err := faststringmap.OpenReaderAt(bytes.NewReader(data), int64(len(data)), codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "map.fstm")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mapped, err := faststringmap.OpenMapped(path, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer mapped.Close()
+
+		for backend, bm := range map[string]*faststringmap.Map[uint32]{
+			"New":              &m,
+			"BuildInto":        &into,
+			"UnmarshalMap":     &unmarshaled,
+			"UnmarshalMapLazy": &lazy,
+			"OpenReaderAt":     &readerAt,
+			"OpenMapped":       &mapped.Map,
+		} {
+			checkMapAllocations(t, optName+"/"+backend, bm)
+		}
+
+		inline, err := faststringmap.NewInlineMap(entries, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		boxed, err := faststringmap.NewBoxedMap(entries, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		atomicMap := faststringmap.NewAtomicMap(m)
+		s, bs := "key2", []byte("key2")
+		checkAllocations(t, optName, map[string]func(){
+			"InlineMap.LookupString": func() { inline.LookupString(s) },
+			"InlineMap.LookupBytes":  func() { inline.LookupBytes(bs) },
+			"BoxedMap.LookupString":  func() { boxed.LookupString(s) },
+			"BoxedMap.LookupBytes":   func() { boxed.LookupBytes(bs) },
+			"AtomicMap.LookupString": func() { atomicMap.LookupString(s) },
+			"AtomicMap.LookupBytes":  func() { atomicMap.LookupBytes(bs) },
+			"Index.Value":            func() { m.Find(s).Value(&m) },
+			"AppendHierarchy":        func() { m.AppendHierarchy(nil, "x", '/') },
+		})
+	}
+
+	suffix := faststringmap.NewSuffixMap(entries)
+	segment, err := faststringmap.NewSegmentMap(entries, '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, bs := "key2", []byte("key2")
+	checkAllocations(t, "default", map[string]func(){
+		"SuffixMap.LookupString":            func() { suffix.LookupString(s) },
+		"SuffixMap.LongestSuffixMatch":      func() { suffix.LongestSuffixMatch(s) },
+		"SuffixMap.LongestSuffixMatchBytes": func() { suffix.LongestSuffixMatchBytes(bs) },
+		"SegmentMap.LookupString":           func() { segment.LookupString(s) },
+		"SegmentMap.LookupBytes":            func() { segment.LookupBytes(bs) },
+	})
+}
+
+// checkMapAllocations checks that the lookups of m do not allocate, after
+// a first lookup, which may decode lazily loaded values.
+func checkMapAllocations(t *testing.T, name string, m *faststringmap.Map[uint32]) {
+	t.Helper()
+	s, bs := "key2", []byte("key2")
+	if v, ok := m.LookupString(s); !ok || v != 2 {
+		t.Errorf("%s: LookupString(%q) = %v, %v want 2, true", name, s, v, ok)
+	}
+	checkAllocations(t, name, map[string]func(){
+		"IndexString":         func() { m.IndexString(s) },
+		"IndexBytes":          func() { m.IndexBytes(bs) },
+		"LookupString":        func() { m.LookupString(s) },
+		"LookupBytes":         func() { m.LookupBytes(bs) },
+		"LookupStringFold":    func() { m.LookupStringFold(s) },
+		"LongestPrefixString": func() { m.LongestPrefixString(s) },
+		"LongestPrefixBytes":  func() { m.LongestPrefixBytes(bs) },
+		"LookupStringBounded": func() { m.LookupStringBounded(s) },
+	})
+}
+
+func checkAllocations(t *testing.T, name string, fns map[string]func()) {
+	t.Helper()
+	for fnName, fn := range fns {
+		if allocs := testing.AllocsPerRun(100, fn); allocs != 0 {
+			t.Errorf("%s: %s allocates %v times per run want 0", name, fnName, allocs)
+		}
+	}
+}
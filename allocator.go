@@ -0,0 +1,38 @@
+package faststringmap
+
+// WithAllocator makes builds allocate the node store and the values of the
+// built map with alloc, instead of from the Go heap, so that deployments can
+// control where large read-only maps are placed, for example in huge pages
+// or on a particular NUMA node. alloc returns memory of at least size bytes
+// aligned to align bytes, or nil to use the Go heap. Memory it returns that
+// is too short or misaligned is not used either.
+//
+// The map never frees the memory; it is up to the caller to release it
+// once the map is no longer used. Values of types holding pointers, which
+// the garbage collector would not find in such memory, are always
+// allocated from the Go heap, as are the node stores built in place by
+// BuildInto, and all memory in the minimal profile. See
+// Builder.SetAllocator.
+func WithAllocator(alloc func(size, align int) []byte) Option {
+	return func(o *buildOptions) { o.alloc = alloc }
+}
+
+// SetAllocator sets the function allocating the node store and the values
+// of maps built by the builder, like WithAllocator. A nil alloc restores
+// allocation from the Go heap.
+func (b *Builder[T]) SetAllocator(alloc func(size, align int) []byte) {
+	b.alloc = alloc
+}
+
+// allocate returns size bytes aligned to align from alloc, or false if
+// alloc did not supply suitable memory.
+func allocate(alloc func(size, align int) []byte, size, align int) ([]byte, bool) {
+	if alloc == nil || size == 0 {
+		return nil, false
+	}
+	mem := alloc(size, align)
+	if len(mem) < size || !aligned(mem, align) {
+		return nil, false
+	}
+	return mem[:size:size], true
+}
@@ -0,0 +1,18 @@
+//go:build faststringmap_minimal || tinygo
+
+package faststringmap
+
+// allocNodes returns a node store of length 0 and capacity n from the Go
+// heap, as memory from alloc can not be used as nodes without unsafe.
+func allocNodes(alloc func(size, align int) []byte, n int) []mapInternalNode {
+	return make([]mapInternalNode, 0, n)
+}
+
+// allocValues returns a values slice of length n from the Go heap.
+func allocValues[T any](alloc func(size, align int) []byte, n int) []T {
+	return make([]T, n)
+}
+
+func aligned(mem []byte, align int) bool {
+	return false
+}
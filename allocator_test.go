@@ -0,0 +1,94 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+// slab hands out memory from a single byte slice, like an allocator backed
+// by huge pages would.
+type slab struct {
+	mem   []byte
+	used  int
+	sizes []int
+}
+
+func (s *slab) alloc(size, align int) []byte {
+	s.used = (s.used + align - 1) / align * align
+	if s.used+size > len(s.mem) {
+		return nil
+	}
+	s.sizes = append(s.sizes, size)
+	mem := s.mem[s.used : s.used+size]
+	s.used += size
+	return mem
+}
+
+func TestWithAllocator(t *testing.T) {
+	entries := randomSmallStrings(1000, 8)
+
+	s := &slab{mem: make([]byte, 1<<20)}
+	var b faststringmap.Builder[uint32]
+	b.SetAllocator(s.alloc)
+	for _, e := range entries {
+		b.Add(e.Key, e.Value)
+	}
+	m := b.Build()
+	for _, e := range entries {
+		if v, ok := m.LookupString(e.Key); !ok || v != e.Value {
+			t.Fatalf("LookupString(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+	}
+
+	want := []int{b.Report().Nodes * 12, len(entries) * 4}
+	if minimalProfile {
+		want = nil
+	}
+	if len(s.sizes) != len(want) || len(want) == 2 && (s.sizes[0] != want[0] || s.sizes[1] != want[1]) {
+		t.Errorf("allocated sizes %v want %v", s.sizes, want)
+	}
+
+	// the next build must not share memory with the first map
+	b.Add("another", 1<<20)
+	b.Build()
+	for _, e := range entries {
+		if v, ok := m.LookupString(e.Key); !ok || v != e.Value {
+			t.Fatalf("after rebuilding, LookupString(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+	}
+}
+
+func TestWithAllocatorFallback(t *testing.T) {
+	entries := []faststringmap.MapEntry[string]{{"a", "x"}, {"ab", "y"}, {"b", "z"}}
+
+	allocators := map[string]func(size, align int) []byte{
+		"nil":        func(size, align int) []byte { return nil },
+		"short":      func(size, align int) []byte { return make([]byte, size-1) },
+		"misaligned": func(size, align int) []byte { return make([]byte, size+1)[1:] },
+	}
+	for name, alloc := range allocators {
+		m, err := faststringmap.New(entries, faststringmap.WithAllocator(alloc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range entries {
+			if v, ok := m.LookupString(e.Key); !ok || v != e.Value {
+				t.Errorf("%s: LookupString(%q) = %q, %v want %q, true", name, e.Key, v, ok, e.Value)
+			}
+		}
+	}
+
+	// string values hold pointers, so only the node store is allocated
+	s := &slab{mem: make([]byte, 1<<10)}
+	if _, err := faststringmap.New(entries, faststringmap.WithAllocator(s.alloc)); err != nil {
+		t.Fatal(err)
+	}
+	want := 1
+	if minimalProfile {
+		want = 0
+	}
+	if len(s.sizes) != want {
+		t.Errorf("allocated sizes %v want %d allocations", s.sizes, want)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build !faststringmap_minimal && !tinygo
+
+package faststringmap
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// allocNodes returns a node store of length 0 and capacity n, allocated
+// with alloc if possible.
+func allocNodes(alloc func(size, align int) []byte, n int) []mapInternalNode {
+	mem, ok := allocate(alloc, n*int(unsafe.Sizeof(mapInternalNode{})), int(unsafe.Alignof(mapInternalNode{})))
+	if !ok {
+		return make([]mapInternalNode, 0, n)
+	}
+	clear(mem)
+	return unsafe.Slice((*mapInternalNode)(unsafe.Pointer(&mem[0])), n)[:0]
+}
+
+// allocValues returns a zeroed values slice of length n, allocated with
+// alloc if possible.
+func allocValues[T any](alloc func(size, align int) []byte, n int) []T {
+	var zero T
+	if alloc == nil || hasPointers(reflect.TypeOf(&zero).Elem()) {
+		return make([]T, n)
+	}
+	mem, ok := allocate(alloc, n*int(unsafe.Sizeof(zero)), int(unsafe.Alignof(zero)))
+	if !ok {
+		return make([]T, n)
+	}
+	clear(mem)
+	return unsafe.Slice((*T)(unsafe.Pointer(&mem[0])), n)
+}
+
+func aligned(mem []byte, align int) bool {
+	return uintptr(unsafe.Pointer(unsafe.SliceData(mem)))%uintptr(align) == 0
+}
@@ -0,0 +1,16 @@
+// Command indexcheck reports misuse of the raw value indices of
+// faststringmap, where 0 means that a key is not present. It runs on its
+// own, or as a vet tool:
+//
+//	go vet -vettool=$(which indexcheck) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"alon.kr/x/faststringmap/analysis/indexcheck"
+)
+
+func main() {
+	singlechecker.Main(indexcheck.Analyzer)
+}
@@ -0,0 +1,320 @@
+// Package indexcheck defines an Analyzer reporting misuse of the raw value
+// indices of faststringmap, where 0 means that a key is not present.
+//
+// The indices returned by Map.IndexString, Map.IndexBytes and their
+// relatives start at 1, and are unsigned. The analyzer reports
+//
+//   - comparisons of an index with 0 that are always or never true, such as
+//     i >= 0, which reads as a check that the key was found
+//   - indexing a slice, array or string with an index, which is off by one
+//   - calling Map.AtIndex with an index minus 1
+//   - discarding the ok result of Map.AtIndex called with an index that may
+//     be 0, which returns the zero value
+//
+// An index is the result of one of these methods, or a variable only ever
+// assigned such results.
+package indexcheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports misuse of the raw value indices of faststringmap.
+var Analyzer = &analysis.Analyzer{
+	Name:     "indexcheck",
+	Doc:      "report misuse of faststringmap value indices, where 0 means not present",
+	URL:      "https://pkg.go.dev/alon.kr/x/faststringmap/analysis/indexcheck",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+const pkgPath = "alon.kr/x/faststringmap"
+
+// indexMethods are the methods returning raw indices, by receiver type.
+var indexMethods = map[string]map[string]bool{
+	"Map":   {"IndexString": true, "IndexBytes": true, "IndexParts": true},
+	"Index": {"Raw": true},
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	c := checker{pass: pass, vars: indexVars(pass, inspect)}
+
+	nodes := []ast.Node{(*ast.BinaryExpr)(nil), (*ast.IndexExpr)(nil), (*ast.CallExpr)(nil), (*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}
+	inspect.WithStack(nodes, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			c.checkComparison(n)
+		case *ast.IndexExpr:
+			c.checkIndexExpr(n)
+		case *ast.CallExpr:
+			c.checkAtIndexArg(n)
+		case *ast.AssignStmt:
+			if len(n.Lhs) == 2 && len(n.Rhs) == 1 {
+				c.checkDiscardedOk(n.Lhs[1], n.Rhs[0], stack)
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) == 2 && len(n.Values) == 1 {
+				c.checkDiscardedOk(n.Names[1], n.Values[0], stack)
+			}
+		}
+		return true
+	})
+	return nil, nil
+}
+
+type checker struct {
+	pass *analysis.Pass
+	vars map[types.Object]bool // variables only ever assigned indices
+}
+
+// checkComparison reports comparisons of an index with 0 that are always or
+// never true.
+func (c *checker) checkComparison(e *ast.BinaryExpr) {
+	op, x, y := e.Op, e.X, e.Y
+	if c.isIndex(y) {
+		// 0 < i is i > 0
+		op, x, y = mirror(op), y, x
+	}
+	if !c.isIndex(x) || !isZero(c.pass, y) {
+		return
+	}
+	switch op {
+	case token.GEQ:
+		c.pass.ReportRangef(e, "comparison of faststringmap index >= 0 is always true; a missing key has index 0, so use != 0")
+	case token.LSS:
+		c.pass.ReportRangef(e, "comparison of faststringmap index < 0 is never true; a missing key has index 0, so use == 0")
+	}
+}
+
+// checkIndexExpr reports indexing a slice, array or string with an index.
+func (c *checker) checkIndexExpr(e *ast.IndexExpr) {
+	if !c.isIndex(e.Index) {
+		return
+	}
+	switch c.pass.TypesInfo.TypeOf(e.X).Underlying().(type) {
+	case *types.Slice, *types.Array, *types.Pointer, *types.Basic:
+		c.pass.ReportRangef(e.Index, "faststringmap index used as a slice index; indices start at 1, and 0 means not present")
+	}
+}
+
+// checkAtIndexArg reports calls of AtIndex with an index minus 1.
+func (c *checker) checkAtIndexArg(call *ast.CallExpr) {
+	if !c.isMethod(call, "Map", "AtIndex") || len(call.Args) != 1 {
+		return
+	}
+	arg, ok := ast.Unparen(call.Args[0]).(*ast.BinaryExpr)
+	if ok && arg.Op == token.SUB && c.isIndex(arg.X) && isOne(c.pass, arg.Y) {
+		c.pass.ReportRangef(arg, "AtIndex takes faststringmap indices as returned, starting at 1; do not subtract 1")
+	}
+}
+
+// checkDiscardedOk reports discarding the ok result of AtIndex called with
+// an index, which may be 0. stack holds the enclosing nodes of the call;
+// calls in the body of an if statement checking that the index is not 0
+// are not reported.
+func (c *checker) checkDiscardedOk(ok ast.Expr, value ast.Expr, stack []ast.Node) {
+	call, isCall := ast.Unparen(value).(*ast.CallExpr)
+	if !isCall || !c.isMethod(call, "Map", "AtIndex") || len(call.Args) != 1 || !c.isIndex(call.Args[0]) {
+		return
+	}
+	if id, isIdent := ast.Unparen(call.Args[0]).(*ast.Ident); isIdent && c.checkedNonZero(c.pass.TypesInfo.Uses[id], stack) {
+		return
+	}
+	if id, isIdent := ok.(*ast.Ident); isIdent && id.Name == "_" {
+		c.pass.ReportRangef(ok, "ok result of AtIndex discarded; a missing key has index 0, for which AtIndex returns the zero value")
+	}
+}
+
+// checkedNonZero reports whether a node of stack is a branch of an if
+// statement taken only if the variable obj is not 0.
+func (c *checker) checkedNonZero(obj types.Object, stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		ifStmt, isIf := stack[i].(*ast.IfStmt)
+		if !isIf {
+			continue
+		}
+		if stack[i+1] == ifStmt.Body && c.impliesNonZero(ifStmt.Cond, obj, true) ||
+			stack[i+1] == ifStmt.Else && c.impliesNonZero(ifStmt.Cond, obj, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// impliesNonZero reports whether cond evaluating to want implies that the
+// variable obj is not 0.
+func (c *checker) impliesNonZero(cond ast.Expr, obj types.Object, want bool) bool {
+	e, isBinary := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !isBinary {
+		return false
+	}
+	switch {
+	case e.Op == token.LAND && want, e.Op == token.LOR && !want:
+		return c.impliesNonZero(e.X, obj, want) || c.impliesNonZero(e.Y, obj, want)
+	}
+
+	op, x, y := e.Op, ast.Unparen(e.X), e.Y
+	if isZero(c.pass, x) {
+		op, x, y = mirror(op), ast.Unparen(e.Y), e.X
+	}
+	if id, isIdent := x.(*ast.Ident); !isIdent || c.pass.TypesInfo.Uses[id] != obj || !isZero(c.pass, y) {
+		return false
+	}
+	if want {
+		return op == token.NEQ || op == token.GTR
+	}
+	return op == token.EQL || op == token.LEQ
+}
+
+// isIndex reports whether e is an index: a call of a method returning one,
+// or a variable only ever assigned such calls.
+func (c *checker) isIndex(e ast.Expr) bool {
+	e = ast.Unparen(e)
+	if id, ok := e.(*ast.Ident); ok {
+		return c.vars[c.pass.TypesInfo.Uses[id]]
+	}
+	return isIndexCall(c.pass, e)
+}
+
+func (c *checker) isMethod(call *ast.CallExpr, typ, name string) bool {
+	recv, method, ok := methodOf(c.pass, call)
+	return ok && recv == typ && method == name
+}
+
+// isIndexCall reports whether e is a call of a method returning an index.
+func isIndexCall(pass *analysis.Pass, e ast.Expr) bool {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	recv, method, ok := methodOf(pass, call)
+	return ok && indexMethods[recv][method]
+}
+
+// methodOf returns the receiver type name and the name of the method of
+// faststringmap called by call, if it calls one.
+func methodOf(pass *analysis.Pass, call *ast.CallExpr) (recv, method string, ok bool) {
+	sel, isSel := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !isSel {
+		return "", "", false
+	}
+	fn, isFunc := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !isFunc || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath {
+		return "", "", false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() == nil {
+		return "", "", false
+	}
+	t := sig.Recv().Type()
+	if p, isPtr := t.(*types.Pointer); isPtr {
+		t = p.Elem()
+	}
+	named, isNamed := t.(*types.Named)
+	if !isNamed {
+		return "", "", false
+	}
+	return named.Origin().Obj().Name(), fn.Name(), true
+}
+
+// indexVars returns the variables that are only ever assigned indices.
+func indexVars(pass *analysis.Pass, inspect *inspector.Inspector) map[types.Object]bool {
+	assigned := map[types.Object]bool{} // whether all assignments are indices
+	assign := func(id *ast.Ident, value ast.Expr) {
+		obj := pass.TypesInfo.ObjectOf(id)
+		if obj == nil {
+			return
+		}
+		isIndex := value != nil && isIndexCall(pass, value)
+		if prev, seen := assigned[obj]; seen {
+			isIndex = isIndex && prev
+		}
+		assigned[obj] = isIndex
+	}
+
+	nodes := []ast.Node{(*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil), (*ast.IncDecStmt)(nil), (*ast.UnaryExpr)(nil), (*ast.RangeStmt)(nil)}
+	inspect.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok {
+					var value ast.Expr
+					if len(n.Lhs) == len(n.Rhs) && (n.Tok == token.ASSIGN || n.Tok == token.DEFINE) {
+						value = n.Rhs[i]
+					}
+					assign(id, value)
+				}
+			}
+		case *ast.ValueSpec:
+			for i, id := range n.Names {
+				var value ast.Expr
+				if len(n.Names) == len(n.Values) {
+					value = n.Values[i]
+				}
+				assign(id, value)
+			}
+		case *ast.IncDecStmt:
+			if id, ok := n.X.(*ast.Ident); ok {
+				assign(id, nil)
+			}
+		case *ast.UnaryExpr:
+			// a variable whose address is taken may be assigned anything
+			if id, ok := n.X.(*ast.Ident); ok && n.Op == token.AND {
+				assign(id, nil)
+			}
+		case *ast.RangeStmt:
+			for _, e := range []ast.Expr{n.Key, n.Value} {
+				if id, ok := e.(*ast.Ident); ok {
+					assign(id, nil)
+				}
+			}
+		}
+	})
+
+	vars := map[types.Object]bool{}
+	for obj, isIndex := range assigned {
+		if isIndex {
+			vars[obj] = true
+		}
+	}
+	return vars
+}
+
+func isZero(pass *analysis.Pass, e ast.Expr) bool {
+	return isConst(pass, e, 0)
+}
+
+func isOne(pass *analysis.Pass, e ast.Expr) bool {
+	return isConst(pass, e, 1)
+}
+
+func isConst(pass *analysis.Pass, e ast.Expr, v int64) bool {
+	tv, ok := pass.TypesInfo.Types[e]
+	return ok && tv.Value != nil && tv.Value.Kind() == constant.Int && constant.Compare(tv.Value, token.EQL, constant.MakeInt64(v))
+}
+
+// mirror returns the operator comparing y with x like op compares x with y.
+func mirror(op token.Token) token.Token {
+	switch op {
+	case token.LSS:
+		return token.GTR
+	case token.GTR:
+		return token.LSS
+	case token.LEQ:
+		return token.GEQ
+	case token.GEQ:
+		return token.LEQ
+	}
+	return op
+}
@@ -0,0 +1,13 @@
+package indexcheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"alon.kr/x/faststringmap/analysis/indexcheck"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), indexcheck.Analyzer, "a")
+}
@@ -0,0 +1,89 @@
+package a
+
+import "alon.kr/x/faststringmap"
+
+func comparisons(m *faststringmap.Map[int], s string) {
+	if m.IndexString(s) >= 0 { // want `index >= 0 is always true`
+	}
+	if m.IndexBytes([]byte(s)) < 0 { // want `index < 0 is never true`
+	}
+	if 0 <= m.IndexString(s) { // want `index >= 0 is always true`
+	}
+	i := m.IndexString(s)
+	if i >= 0 { // want `index >= 0 is always true`
+	}
+	if m.Find(s).Raw() >= 0 { // want `index >= 0 is always true`
+	}
+
+	// correct checks
+	if i != 0 || i > 0 || i == 0 || 0 < i {
+	}
+}
+
+func slices(m *faststringmap.Map[int], s string, names []string, table [8]int) {
+	_ = names[m.IndexString(s)] // want `used as a slice index`
+	i := m.IndexString(s)
+	_ = table[i] // want `used as a slice index`
+	_ = s[i]     // want `used as a slice index`
+
+	_ = names[i-1]
+	byIndex := map[faststringmap.Uint]string{}
+	_ = byIndex[i]
+}
+
+func atIndex(m *faststringmap.Map[int], s string) {
+	m.AtIndex(m.IndexString(s) - 1) // want `do not subtract 1`
+	i := m.IndexString(s)
+	m.AtIndex(i - 1) // want `do not subtract 1`
+
+	v, _ := m.AtIndex(i)                   // want `ok result of AtIndex discarded`
+	var w, _ = m.AtIndex(m.IndexString(s)) // want `ok result of AtIndex discarded`
+	_, _ = v, w
+
+	if v, ok := m.AtIndex(i); ok {
+		_ = v
+	}
+	v, _ = m.LookupString(s)
+}
+
+func notIndices(m *faststringmap.Map[int], s string, index faststringmap.Uint) {
+	// parameters, and variables also assigned other values, may hold
+	// indices known to be valid
+	v, _ := m.AtIndex(index)
+	_ = v
+
+	j := m.IndexString(s)
+	if j == 0 {
+		j = 1
+	}
+	names := []string{"", "a"}
+	_ = names[j]
+	if j >= 0 {
+	}
+
+	var k faststringmap.Uint
+	k = m.IndexString(s)
+	_ = names[k]
+}
+
+func checked(m *faststringmap.Map[int], s string) {
+	if i := m.IndexString(s); i != 0 {
+		v, _ := m.AtIndex(i)
+		_ = v
+	}
+	if i := m.IndexString(s); 0 < i && len(s) > 1 {
+		v, _ := m.AtIndex(i)
+		_ = v
+	}
+	if i := m.IndexString(s); i == 0 {
+		v, _ := m.AtIndex(i) // want `ok result of AtIndex discarded`
+		_ = v
+	} else {
+		v, _ := m.AtIndex(i)
+		_ = v
+	}
+	if i := m.IndexString(s); i != 0 || len(s) > 1 {
+		v, _ := m.AtIndex(i) // want `ok result of AtIndex discarded`
+		_ = v
+	}
+}
@@ -0,0 +1,17 @@
+// Package faststringmap is a stub of the methods of faststringmap checked
+// by the analyzer.
+package faststringmap
+
+type Uint = uint32
+
+type Map[T any] struct{}
+
+func (m *Map[T]) IndexString(s string) Uint            { return 0 }
+func (m *Map[T]) IndexBytes(s []byte) Uint             { return 0 }
+func (m *Map[T]) AtIndex(index Uint) (t T, ok bool)    { return t, false }
+func (m *Map[T]) Find(s string) Index[T]               { return Index[T]{} }
+func (m *Map[T]) LookupString(s string) (t T, ok bool) { return t, false }
+
+type Index[T any] struct{}
+
+func (i Index[T]) Raw() Uint { return 0 }
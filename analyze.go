@@ -0,0 +1,114 @@
+package faststringmap
+
+import "unsafe"
+
+// KeyProfile describes a set of keys, to help choose how to build a map from
+// them before committing to an expensive build.
+type KeyProfile struct {
+	Keys       int // number of distinct keys
+	Duplicates int // number of keys equal to a previous key
+	KeyBytes   int // total length of the distinct keys
+	MaxKeyLen  int // length of the longest key
+
+	// SharedPrefix[d] is the number of keys whose longest common prefix with
+	// the preceding key, in ascending order, is d bytes long.
+	SharedPrefix []int
+
+	// Alphabet[c] is the number of occurrences of byte c in the distinct keys.
+	Alphabet [256]int
+
+	// Projections holds the projected size of each available map layout:
+	// "trie" for Map, "inline" for InlineMap with inlined values, "boxed"
+	// for BoxedMap, "packed" for PackedMap, "sorted" for SortedMap, and
+	// "segment" for SegmentMap split at the most frequent of the bytes
+	// '/', '.' and ':' in the keys, if any of them occurs.
+	Projections []Projection
+}
+
+// Projection is the projected size of a map layout for a set of keys,
+// not including the values, or for BoxedMap, the arena holding them.
+type Projection struct {
+	Layout string // name of the map layout
+	Nodes  int    // number of nodes
+	Bytes  int    // bytes used by the nodes, and the keys or indices they refer to
+}
+
+// segmentSeparators are the separators SegmentMap is projected for.
+const segmentSeparators = "/.:"
+
+// AnalyzeKeys returns a profile of the supplied keys. It does not modify keys.
+func AnalyzeKeys(keys []string) KeyProfile {
+	var b Builder[struct{}]
+	for _, k := range keys {
+		b.Add(k, struct{}{})
+	}
+	b.sortEntries()
+
+	var p KeyProfile
+	distinct := b.order[:0]
+	prev := ""
+	for i, ei := range b.order {
+		key := b.key(ei)
+		if i > 0 && key == prev {
+			p.Duplicates++
+			continue
+		}
+		distinct = append(distinct, ei)
+
+		shared := 0
+		if i > 0 {
+			shared = commonPrefixLen(prev, key)
+		}
+		for len(p.SharedPrefix) <= shared {
+			p.SharedPrefix = append(p.SharedPrefix, 0)
+		}
+		p.SharedPrefix[shared]++
+
+		for j := 0; j < len(key); j++ {
+			p.Alphabet[key[j]]++
+		}
+		p.KeyBytes += len(key)
+		if len(key) > p.MaxKeyLen {
+			p.MaxKeyLen = len(key)
+		}
+		prev = key
+	}
+	b.order = distinct
+	p.Keys = len(distinct)
+
+	nodes := b.countNodes()
+	packedNodes, chainBytes := b.countPacked()
+	p.Projections = append(p.Projections,
+		Projection{Layout: "trie", Nodes: nodes, Bytes: nodes * nodeSize},
+		Projection{Layout: "inline", Nodes: nodes, Bytes: nodes * int(unsafe.Sizeof(inlineNode{}))},
+		Projection{Layout: "boxed", Nodes: nodes, Bytes: nodes*nodeSize + p.Keys*int(unsafe.Sizeof(Uint(0)))},
+		Projection{Layout: "packed", Nodes: packedNodes, Bytes: packedNodes*nodeSize + chainBytes},
+		Projection{Layout: "sorted", Bytes: p.KeyBytes + p.Keys*int(unsafe.Sizeof(Uint(0)))},
+	)
+
+	var sep byte
+	for i, most := 0, 0; i < len(segmentSeparators); i++ {
+		if c := segmentSeparators[i]; p.Alphabet[c] > most {
+			sep, most = c, p.Alphabet[c]
+		}
+	}
+	if sep != 0 {
+		segments, labelBytes := b.countSegments(sep)
+		p.Projections = append(p.Projections, Projection{
+			Layout: "segment",
+			Nodes:  segments,
+			Bytes: segments*int(unsafe.Sizeof(segmentNode{})) +
+				(segments-1)*int(unsafe.Sizeof(segmentEdge{})) + labelBytes,
+		})
+	}
+
+	return p
+}
+
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
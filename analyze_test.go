@@ -0,0 +1,80 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestAnalyzeKeys(t *testing.T) {
+	p := faststringmap.AnalyzeKeys([]string{"a1", "a3", "b", "a1"})
+
+	if p.Keys != 3 || p.Duplicates != 1 || p.KeyBytes != 5 || p.MaxKeyLen != 2 {
+		t.Errorf("AnalyzeKeys() = %+v want 3 keys, 1 duplicate, 5 key bytes, max length 2", p)
+	}
+
+	// sorted: "a1" (0 shared), "a3" (1 shared), "b" (0 shared)
+	if len(p.SharedPrefix) != 2 || p.SharedPrefix[0] != 2 || p.SharedPrefix[1] != 1 {
+		t.Errorf("SharedPrefix = %v want [2 1]", p.SharedPrefix)
+	}
+	if p.Alphabet['a'] != 2 || p.Alphabet['1'] != 1 || p.Alphabet['b'] != 1 {
+		t.Errorf("Alphabet counts of a, 1, b = %d, %d, %d want 2, 1, 1",
+			p.Alphabet['a'], p.Alphabet['1'], p.Alphabet['b'])
+	}
+
+	var b faststringmap.Builder[int]
+	b.Add("a1", 0)
+	b.Add("a3", 0)
+	b.Add("b", 0)
+	b.Build()
+	if len(p.Projections) == 0 || p.Projections[0].Nodes != b.Report().Nodes {
+		t.Errorf("Projections = %+v want trie with %d nodes", p.Projections, b.Report().Nodes)
+	}
+}
+
+func TestAnalyzeKeysProjections(t *testing.T) {
+	keys := []string{"api/v1/users", "api/v1/groups", "api/v2/users", "static/app.js", "static"}
+	p := faststringmap.AnalyzeKeys(keys)
+
+	layouts := map[string]faststringmap.Projection{}
+	for _, pr := range p.Projections {
+		layouts[pr.Layout] = pr
+	}
+	for _, name := range []string{"trie", "inline", "boxed", "packed", "sorted", "segment"} {
+		if _, ok := layouts[name]; !ok {
+			t.Errorf("Projections = %+v want a %s projection", p.Projections, name)
+		}
+	}
+
+	entries := make([]faststringmap.MapEntry[struct{}], len(keys))
+	for i, k := range keys {
+		entries[i].Key = k
+	}
+	m := faststringmap.NewMap(entries)
+	if got, want := layouts["trie"].Bytes, m.Stats().Bytes; got != want {
+		t.Errorf("trie projection = %d bytes want %d", got, want)
+	}
+	pm, err := faststringmap.NewPackedMap(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := layouts["packed"].Bytes, pm.Bytes(); got != want {
+		t.Errorf("packed projection = %d bytes want %d", got, want)
+	}
+	sm, err := faststringmap.NewSortedMap(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := layouts["sorted"].Bytes, sm.Bytes(); got != want {
+		t.Errorf("sorted projection = %d bytes want %d", got, want)
+	}
+
+	// the root, api, api/v1, its 2 keys, api/v2, its key, static and its key
+	if got := layouts["segment"].Nodes; got != 9 {
+		t.Errorf("segment projection = %d nodes want 9", got)
+	}
+
+	if p := faststringmap.AnalyzeKeys([]string{"a", "b"}); len(p.Projections) != 5 {
+		t.Errorf("Projections of keys without separators = %+v want no segment projection", p.Projections)
+	}
+}
@@ -0,0 +1,96 @@
+// Package arrowdict adapts faststringmap to Apache Arrow dictionary encoding.
+//
+// It works on the raw buffers of Arrow string arrays, so it does not depend
+// on an Arrow library: a string array of n values consists of n+1 offsets
+// into its data buffer, and an optional validity bitmap with a bit per value,
+// least significant bit first, which is clear for null values. With the Go
+// Arrow library, these are the ValueOffsets, ValueBytes and NullBitmapBytes
+// of a String or LargeString array.
+package arrowdict
+
+import (
+	"errors"
+	"fmt"
+
+	"alon.kr/x/faststringmap"
+)
+
+// Offset is the type of the offsets of Arrow string arrays: int32 for String,
+// and int64 for LargeString arrays.
+type Offset interface {
+	int32 | int64
+}
+
+// ErrInvalidArray is returned for offsets that do not describe a valid
+// string array.
+var ErrInvalidArray = errors.New("arrowdict: invalid string array")
+
+// BuildDictionary builds a Map from the values of a dictionary string array
+// to their index in the array, for encoding string columns using the
+// dictionary. The values of a dictionary must be unique and not null, and
+// errors from building the map, such as faststringmap.ErrByteRange, are
+// returned.
+func BuildDictionary[O Offset](offsets []O, data []byte) (faststringmap.Map[int32], error) {
+	if err := checkOffsets(offsets, data); err != nil {
+		return faststringmap.Map[int32]{}, err
+	}
+
+	n := len(offsets) - 1
+	entries := make([]faststringmap.MapEntry[int32], n)
+	seen := make(map[string]struct{}, n)
+	for i := range entries {
+		key := string(data[offsets[i]:offsets[i+1]])
+		if _, dup := seen[key]; dup {
+			return faststringmap.Map[int32]{}, fmt.Errorf("arrowdict: duplicate dictionary value %q", key)
+		}
+		seen[key] = struct{}{}
+		entries[i] = faststringmap.MapEntry[int32]{Key: key, Value: int32(i)}
+	}
+
+	return faststringmap.New(entries)
+}
+
+// Encode looks up every value of a string array in the dictionary m, and
+// stores its dictionary index in the corresponding element of indices, which
+// must have the same length as the array. Null values, according to validity,
+// which may be nil if the array has no nulls, are stored as 0, as Arrow
+// leaves them undefined. Values not present in the dictionary are stored as
+// -1, and counted in missing. Encode does not allocate.
+func Encode[O Offset](m *faststringmap.Map[int32], offsets []O, data []byte, validity []byte, indices []int32) (missing int, err error) {
+	if err := checkOffsets(offsets, data); err != nil {
+		return 0, err
+	}
+	n := len(offsets) - 1
+	if len(indices) != n || (validity != nil && len(validity) < (n+7)/8) {
+		return 0, ErrInvalidArray
+	}
+
+	for i := range indices {
+		if validity != nil && validity[i/8]&(1<<(i%8)) == 0 {
+			indices[i] = 0
+			continue
+		}
+
+		index, ok := m.LookupBytes(data[offsets[i]:offsets[i+1]])
+		if !ok {
+			index = -1
+			missing++
+		}
+		indices[i] = index
+	}
+
+	return missing, nil
+}
+
+// checkOffsets checks that offsets are non-decreasing, and within data.
+func checkOffsets[O Offset](offsets []O, data []byte) error {
+	if len(offsets) == 0 || offsets[0] < 0 || int64(offsets[len(offsets)-1]) > int64(len(data)) {
+		return ErrInvalidArray
+	}
+	for i := 1; i < len(offsets); i++ {
+		if offsets[i] < offsets[i-1] {
+			return ErrInvalidArray
+		}
+	}
+	return nil
+}
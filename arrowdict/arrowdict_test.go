@@ -0,0 +1,83 @@
+package arrowdict_test
+
+import (
+	"errors"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+	"alon.kr/x/faststringmap/arrowdict"
+)
+
+// stringArray returns the offsets and data buffers of a string array.
+func stringArray[O arrowdict.Offset](values ...string) ([]O, []byte) {
+	offsets := []O{0}
+	var data []byte
+	for _, v := range values {
+		data = append(data, v...)
+		offsets = append(offsets, O(len(data)))
+	}
+	return offsets, data
+}
+
+func TestEncode(t *testing.T) {
+	dictOffsets, dictData := stringArray[int32]("red", "green", "blue", "")
+	dict, err := arrowdict.BuildDictionary(dictOffsets, dictData)
+	if err != nil {
+		t.Fatalf("BuildDictionary() error = %v", err)
+	}
+
+	offsets, data := stringArray[int64]("blue", "", "null", "red", "purple", "green")
+	validity := []byte{0b111011} // the third value is null
+	indices := make([]int32, 6)
+	missing, err := arrowdict.Encode(&dict, offsets, data, validity, indices)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	want := []int32{2, 3, 0, 0, -1, 1}
+	for i := range want {
+		if indices[i] != want[i] {
+			t.Errorf("Encode() indices = %v want %v", indices, want)
+			break
+		}
+	}
+	if missing != 1 {
+		t.Errorf("Encode() missing = %d want 1", missing)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		arrowdict.Encode(&dict, offsets, data, nil, indices)
+	}); allocs != 0 {
+		t.Errorf("Encode() allocates %v times per run want 0", allocs)
+	}
+}
+
+func TestInvalidArrays(t *testing.T) {
+	if _, err := arrowdict.BuildDictionary([]int32{0, 1, 2}, []byte("aa")); err == nil {
+		t.Errorf("BuildDictionary() of duplicate values succeeded")
+	}
+	if _, err := arrowdict.BuildDictionary(stringArray[int32]("k\x00", "k\xff")); !errors.Is(err, faststringmap.ErrByteRange) {
+		t.Errorf("BuildDictionary() of values using all byte values error = %v want ErrByteRange", err)
+	}
+
+	dict, _ := arrowdict.BuildDictionary(stringArray[int32]("a"))
+	for name, offsets := range map[string][]int32{
+		"empty":      nil,
+		"decreasing": {0, 2, 1},
+		"overflow":   {0, 1, 5},
+	} {
+		var indices []int32
+		if len(offsets) > 0 {
+			indices = make([]int32, len(offsets)-1)
+		}
+		_, err := arrowdict.Encode(&dict, offsets, []byte("ab"), nil, indices)
+		if !errors.Is(err, arrowdict.ErrInvalidArray) {
+			t.Errorf("%s: Encode() error = %v want ErrInvalidArray", name, err)
+		}
+	}
+
+	offsets, data := stringArray[int32]("a", "b")
+	if _, err := arrowdict.Encode(&dict, offsets, data, nil, make([]int32, 1)); !errors.Is(err, arrowdict.ErrInvalidArray) {
+		t.Errorf("Encode() into short indices error = %v want ErrInvalidArray", err)
+	}
+}
@@ -0,0 +1,43 @@
+package faststringmap
+
+import "sync/atomic"
+
+// AtomicMap[T] holds a Map that can be replaced while it is being used, for
+// maps that are reloaded while a program runs. Lookups always see either the
+// old or the new map in full. The zero value holds an empty map. An
+// AtomicMap is safe for concurrent use, and must not be copied after first use.
+// A Group replaces several AtomicMaps together.
+type AtomicMap[T any] struct {
+	v atomic.Value // of *Map[T]
+}
+
+// NewAtomicMap[T] constructs an AtomicMap holding m.
+func NewAtomicMap[T any](m Map[T]) *AtomicMap[T] {
+	a := &AtomicMap[T]{}
+	a.Store(m)
+	return a
+}
+
+// Load returns the current map. The returned map is not affected by later
+// calls to Store, so a sequence of lookups that must be consistent with each
+// other should be made on the same loaded map.
+func (a *AtomicMap[T]) Load() *Map[T] {
+	m, _ := a.v.Load().(*Map[T])
+	return m
+}
+
+// Store replaces the current map with m.
+func (a *AtomicMap[T]) Store(m Map[T]) {
+	a.v.Store(&m)
+}
+
+// LookupString looks up the supplied string in the current map.
+func (a *AtomicMap[T]) LookupString(s string) (t T, ok bool) {
+	return a.Load().LookupString(s)
+}
+
+// LookupBytes looks up the supplied byte slice in the current map.
+// Like Map.LookupBytes, it does not allocate.
+func (a *AtomicMap[T]) LookupBytes(s []byte) (t T, ok bool) {
+	return a.Load().LookupBytes(s)
+}
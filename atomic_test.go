@@ -0,0 +1,43 @@
+package faststringmap_test
+
+import (
+	"sync"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestAtomicMapZero(t *testing.T) {
+	var a faststringmap.AtomicMap[uint32]
+	if _, ok := a.LookupString("GET"); ok {
+		t.Errorf("LookupString() in zero AtomicMap reported present")
+	}
+
+	a.Store(faststringmap.NewMap(goldenEntries))
+	checkEntries(t, a.Load(), goldenEntries)
+}
+
+func TestAtomicMapStore(t *testing.T) {
+	replacement := []faststringmap.MapEntry[uint32]{{"CONNECT", 8}, {"TRACE", 9}}
+	a := faststringmap.NewAtomicMap(faststringmap.NewMap(goldenEntries))
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				m := a.Load()
+				if _, ok := m.LookupString("GET"); ok {
+					checkEntries(t, m, goldenEntries)
+				} else {
+					checkEntries(t, m, replacement)
+				}
+			}
+		}()
+	}
+	a.Store(faststringmap.NewMap(replacement))
+	wg.Wait()
+
+	checkEntries(t, a.Load(), replacement)
+}
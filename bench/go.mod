@@ -0,0 +1,13 @@
+module alon.kr/x/faststringmap/bench
+
+go 1.23
+
+require (
+	alon.kr/x/faststringmap v0.0.0
+	github.com/dghubble/trie v0.1.0
+	github.com/hashicorp/go-immutable-radix/v2 v2.1.0
+)
+
+require github.com/hashicorp/golang-lru/v2 v2.0.0 // indirect
+
+replace alon.kr/x/faststringmap => ../
@@ -0,0 +1,197 @@
+// Package bench compares the lookup speed of faststringmap with other Go
+// string keyed maps on shared datasets. It is a separate module, so that
+// the main module does not depend on the compared packages.
+//
+// Run it with make bench-compare from the repository root, or with
+//
+//	go test -bench . -benchmem
+//
+// in this directory. Setting FSTM_BENCH_KEYS to the path of a file with
+// one key per line adds a dataset of those keys.
+package bench
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+	"github.com/dghubble/trie"
+	iradix "github.com/hashicorp/go-immutable-radix/v2"
+)
+
+type dataset struct {
+	name string
+	keys []string
+}
+
+// datasets returns the shared datasets, each with distinct keys.
+func datasets(tb testing.TB) []dataset {
+	sets := []dataset{
+		{"codes", codes(10000)},
+		{"countries", tsvColumn(tb, "../presets/internal/gen/data/countries.tsv", 2)},
+		{"paths", paths(10000)},
+		{"words", words(10000)},
+	}
+	if path := os.Getenv("FSTM_BENCH_KEYS"); path != "" {
+		sets = append(sets, dataset{"file", lines(tb, path)})
+	}
+	return sets
+}
+
+// codes returns the numeric category codes typical of survey data.
+func codes(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i + 1)
+	}
+	return keys
+}
+
+// paths returns hierarchical keys, like URL paths.
+func paths(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("/api/v%d/tenants/%d/resources/%d", i%3+1, i%97, i)
+	}
+	return keys
+}
+
+// words returns random distinct lower case words of 3 to 12 letters.
+func words(n int) []string {
+	r := rand.New(rand.NewSource(1))
+	seen := make(map[string]bool, n)
+	keys := make([]string, 0, n)
+	for len(keys) < n {
+		b := make([]byte, 3+r.Intn(10))
+		for i := range b {
+			b[i] = 'a' + byte(r.Intn(26))
+		}
+		if w := string(b); !seen[w] {
+			seen[w] = true
+			keys = append(keys, w)
+		}
+	}
+	return keys
+}
+
+// tsvColumn returns the distinct values of a column of a tab separated
+// file, skipping comment lines.
+func tsvColumn(tb testing.TB, path string, col int) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, line := range lines(tb, path) {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if fields := strings.Split(line, "\t"); col < len(fields) && !seen[fields[col]] {
+			seen[fields[col]] = true
+			keys = append(keys, fields[col])
+		}
+	}
+	return keys
+}
+
+// lines returns the distinct non-empty lines of a file.
+func lines(tb testing.TB, path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer f.Close()
+
+	var keys []string
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if line := sc.Text(); line != "" && !seen[line] {
+			seen[line] = true
+			keys = append(keys, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		tb.Fatal(err)
+	}
+	return keys
+}
+
+// lookupFunc looks up a key, returning its value.
+type lookupFunc func(key string) (int, bool)
+
+// implementations builds every compared map from keys, with the index of
+// each key as its value.
+func implementations(keys []string) map[string]lookupFunc {
+	entries := make([]faststringmap.MapEntry[int], len(keys))
+	goMap := make(map[string]int, len(keys))
+	radix := iradix.New[int]()
+	runeTrie := trie.NewRuneTrie()
+	for i, k := range keys {
+		entries[i] = faststringmap.MapEntry[int]{Key: k, Value: i}
+		goMap[k] = i
+		radix, _, _ = radix.Insert([]byte(k), i)
+		runeTrie.Put(k, i)
+	}
+	fsm := faststringmap.NewMap(entries)
+	inline, err := faststringmap.NewInlineMap(entries)
+	if err != nil {
+		panic(err)
+	}
+	packed, err := faststringmap.NewPackedMap(entries)
+	if err != nil {
+		panic(err)
+	}
+	sorted, err := faststringmap.NewSortedMap(entries)
+	if err != nil {
+		panic(err)
+	}
+
+	return map[string]lookupFunc{
+		"faststringmap": fsm.LookupString,
+		"InlineMap":     inline.LookupString,
+		"PackedMap":     packed.LookupString,
+		"SortedMap":     sorted.LookupString,
+		"builtin":       func(k string) (int, bool) { v, ok := goMap[k]; return v, ok },
+		"iradix":        func(k string) (int, bool) { return radix.Get([]byte(k)) },
+		"RuneTrie": func(k string) (int, bool) {
+			v, ok := runeTrie.Get(k).(int)
+			return v, ok
+		},
+	}
+}
+
+// TestImplementations checks that all compared maps agree, so that the
+// benchmarks compare like with like.
+func TestImplementations(t *testing.T) {
+	for _, ds := range datasets(t) {
+		for name, lookup := range implementations(ds.keys) {
+			for i, k := range ds.keys {
+				if v, ok := lookup(k); !ok || v != i {
+					t.Fatalf("%s/%s: lookup(%q) = %v, %v want %v, true", ds.name, name, k, v, ok, i)
+				}
+			}
+			if _, ok := lookup("\x00missing"); ok {
+				t.Errorf("%s/%s: found a missing key", ds.name, name)
+			}
+		}
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	for _, ds := range datasets(b) {
+		for name, lookup := range implementations(ds.keys) {
+			b.Run(ds.name+"/"+name, func(b *testing.B) {
+				b.ReportAllocs()
+				for bi := 0; bi < b.N; bi++ {
+					for _, k := range ds.keys {
+						lookup(k)
+					}
+				}
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(ds.keys)), "ns/key")
+			})
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package faststringmap
+
+// MarkMembers sets bit i of bits, for every keys[i] present in the map, and
+// returns the number of keys found. Bit i is bit i%64 of bits[i/64]. Bits of
+// keys not present are left unchanged, so the same bits can accumulate
+// several probe lists. It panics if bits is shorter than
+// (len(keys)+63)/64 words.
+func (m *Map[T]) MarkMembers(keys []string, bits []uint64) (found int) {
+	checkBitsetLen(len(keys), bits)
+	for i, k := range keys {
+		if m.IndexString(k) != 0 {
+			bits[i/64] |= 1 << (i % 64)
+			found++
+		}
+	}
+	return found
+}
+
+// MarkMembersBytes is like MarkMembers, for byte slice probes.
+func (m *Map[T]) MarkMembersBytes(keys [][]byte, bits []uint64) (found int) {
+	checkBitsetLen(len(keys), bits)
+	for i, k := range keys {
+		if m.IndexBytes(k) != 0 {
+			bits[i/64] |= 1 << (i % 64)
+			found++
+		}
+	}
+	return found
+}
+
+func checkBitsetLen(n int, bits []uint64) {
+	if len(bits) < (n+63)/64 {
+		panic("faststringmap: bitset too short for the probes")
+	}
+}
@@ -0,0 +1,47 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestMarkMembers(t *testing.T) {
+	entries := randomSmallStrings(200, 8)
+	m := faststringmap.NewMap(entries[:100])
+
+	keys := make([]string, len(entries))
+	byteKeys := make([][]byte, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+		byteKeys[i] = []byte(e.Key)
+	}
+
+	bits := make([]uint64, 4)
+	byteBits := make([]uint64, 4)
+	if found := m.MarkMembers(keys, bits); found != 100 {
+		t.Errorf("MarkMembers() = %d want 100", found)
+	}
+	if found := m.MarkMembersBytes(byteKeys, byteBits); found != 100 {
+		t.Errorf("MarkMembersBytes() = %d want 100", found)
+	}
+	for i := range keys {
+		want := i < 100
+		if got := bits[i/64]&(1<<(i%64)) != 0; got != want {
+			t.Errorf("bit %d = %v want %v", i, got, want)
+		}
+		if got := byteBits[i/64]&(1<<(i%64)) != 0; got != want {
+			t.Errorf("bytes bit %d = %v want %v", i, got, want)
+		}
+	}
+	if bits[3] != 0 {
+		t.Errorf("bits past the probes were set: %x", bits[3])
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MarkMembers did not panic on a short bitset")
+		}
+	}()
+	m.MarkMembers(keys, bits[:3])
+}
@@ -0,0 +1,57 @@
+package faststringmap
+
+// BoxedMap[T] is a fast read only map from string to generic type T, for
+// large value types. The map itself holds a compact index for every key,
+// into an arena holding the values, so the values touched by lookups stay
+// small, and lookups return pointers into the arena instead of copying
+// large values out.
+type BoxedMap[T any] struct {
+	m     Map[Uint] // key -> index in arena
+	arena []T       // values in ascending key order
+}
+
+// NewBoxedMap[T] constructs a new BoxedMap from the provided map entries,
+// configured by opts like New. The entries slice is not modified.
+func NewBoxedMap[T any](entries []MapEntry[T], opts ...Option) (BoxedMap[T], error) {
+	indices := make([]MapEntry[Uint], len(entries))
+	for i, e := range entries {
+		indices[i] = MapEntry[Uint]{e.Key, Uint(i)}
+	}
+	m, err := New(indices, opts...)
+	if err != nil {
+		return BoxedMap[T]{}, err
+	}
+
+	// lay out the arena in key order, like the values of a Map
+	arena := make([]T, len(m.values))
+	for i, ei := range m.values {
+		arena[i] = entries[ei].Value
+		m.values[i] = Uint(i)
+	}
+	return BoxedMap[T]{m: m, arena: arena}, nil
+}
+
+// LookupString looks up the supplied string in the map, and returns a
+// pointer to its value in the arena, which must not be modified.
+func (bm *BoxedMap[T]) LookupString(s string) (t *T, ok bool) {
+	return bm.at(bm.m.IndexString(s))
+}
+
+// LookupBytes looks up the supplied byte slice in the map, like
+// LookupString. It never allocates and does not retain s.
+func (bm *BoxedMap[T]) LookupBytes(s []byte) (t *T, ok bool) {
+	return bm.at(bm.m.IndexBytes(s))
+}
+
+// Len returns the number of keys in the map.
+func (bm *BoxedMap[T]) Len() int {
+	return len(bm.arena)
+}
+
+func (bm *BoxedMap[T]) at(index Uint) (t *T, ok bool) {
+	i, ok := bm.m.AtIndex(index)
+	if !ok {
+		return nil, false
+	}
+	return &bm.arena[i], true
+}
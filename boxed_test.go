@@ -0,0 +1,58 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+type largeValue struct {
+	ID      uint32
+	Payload [256]byte
+}
+
+func TestBoxedMap(t *testing.T) {
+	entries := randomSmallStrings(1024, 8)
+	boxed := make([]faststringmap.MapEntry[largeValue], len(entries))
+	for i, e := range entries {
+		boxed[i] = faststringmap.MapEntry[largeValue]{Key: e.Key, Value: largeValue{ID: e.Value}}
+		boxed[i].Value.Payload[0] = byte(e.Value)
+	}
+
+	bm, err := faststringmap.NewBoxedMap(boxed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bm.Len() != len(entries) {
+		t.Errorf("Len() = %d want %d", bm.Len(), len(entries))
+	}
+	for _, e := range entries {
+		v, ok := bm.LookupString(e.Key)
+		if !ok || v.ID != e.Value || v.Payload[0] != byte(e.Value) {
+			t.Fatalf("LookupString(%q) = %v, %v want ID %v", e.Key, v, ok, e.Value)
+		}
+		if vb, ok := bm.LookupBytes([]byte(e.Key)); !ok || vb != v {
+			t.Errorf("LookupBytes(%q) = %p, %v want %p, true", e.Key, vb, ok, v)
+		}
+	}
+	if v, ok := bm.LookupString("\x01missing"); ok || v != nil {
+		t.Errorf("LookupString(missing) = %v, %v want nil, false", v, ok)
+	}
+
+	dup := []faststringmap.MapEntry[largeValue]{{"a", largeValue{ID: 1}}, {"b", largeValue{ID: 2}}, {"a", largeValue{ID: 3}}}
+	if _, err := faststringmap.NewBoxedMap(dup); err == nil {
+		t.Error("NewBoxedMap() accepted duplicate keys")
+	}
+	bm, err = faststringmap.NewBoxedMap(dup, faststringmap.WithDuplicates(faststringmap.DuplicatesKeepLast))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := bm.LookupString("a"); v.ID != 3 || bm.Len() != 2 {
+		t.Errorf("LookupString(a).ID = %d with Len() %d want 3 with 2", v.ID, bm.Len())
+	}
+
+	var zero faststringmap.BoxedMap[largeValue]
+	if _, ok := zero.LookupString(""); ok {
+		t.Error("zero BoxedMap found the empty key")
+	}
+}
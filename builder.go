@@ -0,0 +1,538 @@
+package faststringmap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"unsafe"
+)
+
+const (
+	firstBufSize    = 1024    // number of nodes in the first block of a build
+	maxBuildBufSize = 1 << 20 // maximum number of nodes in a single block
+)
+
+// Builder[T] constructs Maps from entries added one at a time. A Builder can
+// be reused for many builds by calling Reset between them, in which case it
+// retains its internal buffers, so rebuilding a map of a similar size does not
+// allocate anything except the resulting Map. The zero value is ready to use.
+type Builder[T any] struct {
+	entries []MapEntry[T]
+	order   []Uint // indices of entries, sorted by key
+	sortBuf []Uint // scratch space used for sorting
+	values  []T
+
+	presorted bool // entries are known to be in ascending key order
+
+	buildOptions
+
+	seed         uint32   // fingerprint hash state derived from salt
+	keys         []string // keys in the same order as values, if retained
+	fingerprints []byte   // key fingerprints in the same order as values, if enabled
+
+	// nodes are allocated in blocks of geometrically growing size, which
+	// never move once allocated, so pointers into them stay valid during
+	// the build. Only blocks[:used] are part of the current build.
+	blocks [][]mapInternalNode
+	used   int
+	len    Uint // total number of nodes allocated in the current build
+
+	stack []buildFrame // work stack of countNodes and countPacked, reused across calls
+
+	maxKeyLen int   // length of the longest key in the current build
+	err       error // first error of the current build
+
+	report BuildReport
+
+	logging    bool      // whether the current build logs its phases
+	phaseStart time.Time // start of the current phase, if logging
+}
+
+// BuildReport describes a single build, for capacity planning and for
+// tracking the cost of building a map over time.
+type BuildReport struct {
+	Entries        int            // number of entries in the built map
+	Nodes          int            // number of nodes in the built node store
+	WastedNodes    int            // nodes for bytes that do not continue any key
+	Blocks         int            // number of node blocks used during the build
+	Warnings       []WasteWarning // nodes wasting more slots than the waste threshold, at most maxWasteWarnings
+	BytesAllocated int            // approximate bytes allocated by the build, including the built map
+	Duration       time.Duration  // wall time of the build
+
+	// Duplicates lists duplicate and near-duplicate keys, if enabled by
+	// WithDuplicateAnalysis, and is nil otherwise.
+	Duplicates *DuplicateReport
+}
+
+// Add adds an entry to the map being built. Keys must be unique, unless a
+// duplicate policy is set (see SetDuplicates).
+func (b *Builder[T]) Add(key string, value T) {
+	if b.canonicalizes() {
+		key = b.canonicalKey(key)
+	}
+	if b.entries == nil && b.entriesHint > 0 {
+		b.entries = make([]MapEntry[T], 0, b.entriesHint)
+	}
+	b.entries = append(b.entries, MapEntry[T]{key, value})
+}
+
+// Len returns the number of entries added to the builder since the last Reset.
+func (b *Builder[T]) Len() int {
+	return len(b.entries)
+}
+
+// Build constructs a new Map from the entries added to the builder.
+// The returned Map does not share any memory with the builder. Build panics
+// if keys are duplicated and no duplicate policy is set.
+func (b *Builder[T]) Build() Map[T] {
+	m, err := b.build()
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (b *Builder[T]) build() (Map[T], error) {
+	start := b.beginReport()
+	defer b.endReport(start)
+
+	b.sortEntries()
+	b.endPhase("sort")
+	if b.analyzeDuplicates {
+		r := b.duplicateReport()
+		b.report.Duplicates = &r
+		b.endPhase("duplicates")
+	}
+	if err := b.removeDuplicates(); err != nil {
+		return Map[T]{}, err
+	}
+	if b.buildNodes(); b.err != nil {
+		return Map[T]{}, b.err
+	}
+	b.endPhase("trie")
+	return b.toMap(), nil
+}
+
+// BuildInto constructs a new Map from the entries added to the builder,
+// laying out its node store directly in dst using the documented encoding
+// (see FromEncodedNodes). n is the number of bytes of dst used. If dst is
+// too small, BuildInto returns ErrBufferTooSmall, and n is the number of
+// bytes required. Where the platform allows it, the returned Map uses dst as
+// its node store without copying, in which case dst must not be modified
+// while the Map is in use. This allows building a map straight into a
+// file-backed memory region.
+func (b *Builder[T]) BuildInto(dst []byte) (m Map[T], n int, err error) {
+	start := b.beginReport()
+	defer b.endReport(start)
+
+	b.sortEntries()
+	b.endPhase("sort")
+	if b.analyzeDuplicates {
+		r := b.duplicateReport()
+		b.report.Duplicates = &r
+		b.endPhase("duplicates")
+	}
+	if err := b.removeDuplicates(); err != nil {
+		return Map[T]{}, 0, err
+	}
+
+	n = b.countNodes() * nodeSize
+	b.endPhase("count")
+	if len(dst) < n {
+		return Map[T]{}, n, ErrBufferTooSmall
+	}
+	dst = dst[:n]
+
+	if store, ok := nodesView(dst); ok {
+		// build using dst as the only block, which has exactly enough room
+		blocks := b.blocks
+		b.blocks = [][]mapInternalNode{store[:0]}
+		b.buildNodes()
+		built := b.blocks[0]
+		b.blocks = blocks
+		if b.err != nil {
+			return Map[T]{}, 0, b.err
+		}
+		b.endPhase("trie")
+		return b.newMap(built), n, nil
+	}
+
+	if b.buildNodes(); b.err != nil {
+		return Map[T]{}, 0, b.err
+	}
+	b.endPhase("trie")
+	m = b.toMap()
+	encodeNodes(dst, m.store)
+	return m, n, nil
+}
+
+// SetRetainKeys sets whether maps built by the builder retain the original
+// key strings, so that lookups can return them (see Map.LookupKey). This
+// costs a string header per key, and keeps the key strings alive. Retained
+// keys are not serialized.
+func (b *Builder[T]) SetRetainKeys(retain bool) {
+	b.retainKeys = retain
+}
+
+// SetFingerprints sets whether maps built by the builder store an 8-bit
+// fingerprint of every key, which lookups verify before reporting a key as
+// present. This costs a byte per key, and hashing the probe on successful
+// lookups. Fingerprints guard lookups in layouts that do not compare every
+// byte of the probe against the stored key. Fingerprints are salted with a
+// random salt chosen for every build, so that crafted sets of keys can not
+// be made to collide in a map shared across trust boundaries.
+func (b *Builder[T]) SetFingerprints(enabled bool) {
+	b.withFingerprints = enabled
+}
+
+// SetFingerprintSalt sets the salt of the fingerprints of maps built by the
+// builder, instead of a random salt chosen for every build, for builds that
+// must be reproducible. A zero salt disables salting.
+func (b *Builder[T]) SetFingerprintSalt(salt uint64) {
+	b.salt = salt
+	b.fixedSalt = true
+}
+
+// Report returns a report describing the most recent build.
+func (b *Builder[T]) Report() BuildReport {
+	return b.report
+}
+
+// Reset discards all entries added to the builder, while retaining the
+// capacity of its internal buffers for the next build.
+func (b *Builder[T]) Reset() {
+	var zero T
+	for i := range b.values {
+		b.values[i] = zero // drop references held by values
+	}
+	for i := range b.entries {
+		b.entries[i] = MapEntry[T]{}
+	}
+
+	for i := range b.keys {
+		b.keys[i] = ""
+	}
+
+	b.entries = b.entries[:0]
+	b.values = b.values[:0]
+	b.keys = b.keys[:0]
+	b.fingerprints = b.fingerprints[:0]
+	b.presorted = false
+	b.used = 0
+	b.len = 0
+}
+
+// sortEntries sorts the indices of the added entries into b.order.
+func (b *Builder[T]) sortEntries() {
+	if b.presorted {
+		orderCap := cap(b.order)
+		b.order = b.order[:0]
+		for i := range b.entries {
+			b.order = append(b.order, Uint(i))
+		}
+		if cap(b.order) != orderCap {
+			b.report.BytesAllocated += cap(b.order) * int(unsafe.Sizeof(Uint(0)))
+		}
+		return
+	}
+
+	if cap(b.sortBuf) < len(b.entries) {
+		b.sortBuf = make([]Uint, len(b.entries))
+		b.report.BytesAllocated += len(b.entries) * int(unsafe.Sizeof(Uint(0)))
+	}
+
+	orderCap := cap(b.order)
+	b.order = sortEntries(b.entries, b.order[:0], b.sortBuf)
+	if cap(b.order) != orderCap {
+		b.report.BytesAllocated += cap(b.order) * int(unsafe.Sizeof(Uint(0)))
+	}
+}
+
+// buildNodes constructs the node store and values from the sorted entries.
+func (b *Builder[T]) buildNodes() {
+	b.values = b.values[:0]
+	b.keys = b.keys[:0]
+	b.fingerprints = b.fingerprints[:0]
+	b.used = 0
+	b.len = 0
+	b.maxKeyLen = 0
+	b.err = nil
+	if b.withFingerprints && !b.fixedSalt {
+		b.salt = randomSalt()
+	}
+	b.seed = fingerprintSeed(b.salt)
+
+	// every entry left in b.order gets a value, so size the values, and the
+	// keys and fingerprints kept with them, once for all of them
+	valuesCap := cap(b.values)
+	if cap(b.values) < len(b.order) {
+		b.values = make([]T, 0, len(b.order))
+	}
+	if b.retainKeys && cap(b.keys) < len(b.order) {
+		b.keys = make([]string, 0, len(b.order))
+	}
+	if b.withFingerprints && cap(b.fingerprints) < len(b.order) {
+		b.fingerprints = make([]byte, 0, len(b.order))
+	}
+
+	root, _ := b.allocateNodes(1)
+	if len(b.order) > 0 {
+		b.makeEntries(&root[0], b.order)
+	}
+
+	b.report.Nodes = int(b.len)
+	if cap(b.values) != valuesCap {
+		var zero T
+		b.report.BytesAllocated += cap(b.values) * int(unsafe.Sizeof(zero))
+	}
+}
+
+// countNodes returns the number of nodes buildNodes will create.
+func (b *Builder[T]) countNodes() int {
+	if len(b.order) == 0 {
+		return 1
+	}
+
+	// mirror makeEntries, counting the nodes makeEntry allocates instead of
+	// initializing them
+	n := 1
+	stack := append(b.stack[:0], buildFrame{order: b.order})
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		order := f.order
+		if len(b.key(order[0])) == f.entryIndex {
+			order = order[1:]
+		}
+		if len(order) == 0 {
+			continue
+		}
+
+		n += int(b.key(order[len(order)-1])[f.entryIndex]-b.key(order[0])[f.entryIndex]) + 1
+		for i := 0; i < len(order); {
+			c := b.key(order[i])[f.entryIndex]
+			iSameByteHi := i + 1
+			for iSameByteHi < len(order) && b.key(order[iSameByteHi])[f.entryIndex] == c {
+				iSameByteHi++
+			}
+			stack = append(stack, buildFrame{order: order[i:iSameByteHi], entryIndex: f.entryIndex + 1})
+			i = iSameByteHi
+		}
+	}
+	clear(stack[:cap(stack)])
+	b.stack = stack[:0]
+	return n
+}
+
+// buildFrame is a node whose children are being initialized, for the
+// entries at the sorted indices in order, considering bytes at entryIndex
+// in the keys. It is the state a recursive build would keep on the
+// goroutine stack.
+type buildFrame struct {
+	next       []mapInternalNode // the children
+	nextOffset byte
+	order      []Uint
+	i          int // start in order of the entries of the next child
+	entryIndex int
+	children   int // number of children initialized so far
+}
+
+// makeEntries initializes root and all nodes below it for the entries at
+// the sorted indices in order. It visits nodes depth first, in ascending
+// key order, with an explicit stack of frames instead of recursing, so keys
+// many kilobytes long do not grow the goroutine stack.
+func (b *Builder[T]) makeEntries(root *mapInternalNode, order []Uint) {
+	// stack holds the frames of the current node, on top, and of its
+	// ancestors. Frames are kept on the goroutine stack until there are
+	// more than fit in local, as for recursive calls. They are updated in
+	// place, since copying whole frames stalls on their recent field stores.
+	var local [64]buildFrame
+	stack := local[:0]
+	stack = b.makeEntry(stack, root, order, 0)
+
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if f.i == len(f.order) {
+			if wasted := len(f.next) - f.children; wasted > b.wasteLimit() {
+				b.warnWaste(b.key(f.order[0])[:f.entryIndex], f.children, wasted)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		// find range of strings starting with the same byte
+		i := f.i
+		c := b.key(f.order[i])[f.entryIndex]
+		iSameByteHi := i + 1
+		for iSameByteHi < len(f.order) && b.key(f.order[iSameByteHi])[f.entryIndex] == c {
+			iSameByteHi++
+		}
+		f.i = iSameByteHi
+		f.children++
+		b.report.WastedNodes--
+
+		stack = b.makeEntry(stack, &f.next[c-f.nextOffset], f.order[i:iSameByteHi], f.entryIndex+1)
+	}
+}
+
+// makeEntry will initialize the supplied mapInternalNode for the entries
+// at the sorted indices in order, considering bytes at entryIndex in the
+// keys, allocate its children, and push a frame for initializing them onto
+// stack, if it has any.
+func (b *Builder[T]) makeEntry(stack []buildFrame, node *mapInternalNode, order []Uint, entryIndex int) []buildFrame {
+	// if there is a string with no more bytes then it is always first because they are sorted
+	if len(b.key(order[0])) == entryIndex {
+		b.values = append(b.values, b.entries[order[0]].Value)
+		if b.retainKeys {
+			b.keys = append(b.keys, b.key(order[0]))
+		}
+		if b.withFingerprints {
+			b.fingerprints = append(b.fingerprints, fingerprint(b.key(order[0]), b.seed))
+		}
+		node.valueOffset = uint32(len(b.values))
+		if entryIndex > b.maxKeyLen {
+			b.maxKeyLen = entryIndex
+		}
+		order = order[1:]
+	}
+
+	if len(order) == 0 {
+		return stack
+	}
+
+	lo, hi := b.key(order[0])[entryIndex], b.key(order[len(order)-1])[entryIndex]
+	if hi == 255 && lo == 0 {
+		// all 256 byte values can not be represented by nextLen
+		if b.err == nil {
+			b.err = fmt.Errorf("%w after %q", ErrByteRange, b.key(order[0])[:entryIndex])
+		}
+		return stack
+	}
+	b.report.WastedNodes += int(hi-lo) + 1
+
+	node.nextOffset = lo                          // lowest value for next byte
+	node.nextLen = hi - lo + 1                    // number of possible next bytes
+	next, nextLo := b.allocateNodes(node.nextLen) // new mapInternalNodes default to "not valid"
+	node.nextLo = nextLo                          // first mapEntry struct in eventual built slice
+
+	stack = append(stack, buildFrame{})
+	f := &stack[len(stack)-1]
+	f.next, f.nextOffset, f.order, f.entryIndex = next, lo, order, entryIndex
+	return stack
+}
+
+func (b *Builder[T]) key(i Uint) string {
+	return b.entries[i].Key
+}
+
+// allocateNodes allocates n zeroed nodes, and returns them together with
+// the index of the first one in the eventual built store.
+func (b *Builder[T]) allocateNodes(n byte) ([]mapInternalNode, Uint) {
+	if b.used == 0 || len(b.blocks[b.used-1])+int(n) > cap(b.blocks[b.used-1]) {
+		b.nextBlock(int(n))
+	}
+
+	block := &b.blocks[b.used-1]
+	lo := len(*block)
+	*block = append(*block, make([]mapInternalNode, n)...)
+
+	first := b.len
+	b.len += Uint(n)
+	return (*block)[lo:], first
+}
+
+// nextBlock starts a new block with room for at least n nodes, reusing a
+// block retained from a previous build where possible.
+func (b *Builder[T]) nextBlock(n int) {
+	first, max := b.blockSizes()
+	size := first
+	if b.used > 0 {
+		size = 2 * cap(b.blocks[b.used-1])
+		if size > max {
+			size = max
+		}
+	}
+	if size < n {
+		size = n
+	}
+
+	switch {
+	case b.used == len(b.blocks):
+		b.blocks = append(b.blocks, make([]mapInternalNode, 0, size))
+		b.report.BytesAllocated += size * nodeSize
+	case cap(b.blocks[b.used]) < n:
+		b.blocks[b.used] = make([]mapInternalNode, 0, size)
+		b.report.BytesAllocated += size * nodeSize
+	default:
+		b.blocks[b.used] = b.blocks[b.used][:0]
+	}
+	b.used++
+	b.report.Blocks++
+}
+
+// toMap moves the built nodes and values into a new Map. If all nodes are in
+// a single block that is at least half full, the block is handed over to the
+// Map instead of copied, and a new block is allocated by the next build.
+// With an allocator, nodes are always copied into memory it allocates.
+func (b *Builder[T]) toMap() Map[T] {
+	if b.alloc == nil && b.used == 1 && 2*len(b.blocks[0]) >= cap(b.blocks[0]) {
+		m := b.newMap(b.blocks[0][:b.len:b.len])
+		b.blocks = b.blocks[1:]
+		b.used = 0
+		return m
+	}
+
+	store := allocNodes(b.alloc, int(b.len))
+	b.report.BytesAllocated += int(b.len) * nodeSize
+	for _, block := range b.blocks[:b.used] {
+		store = append(store, block...)
+	}
+	b.endPhase("flatten")
+	return b.newMap(store)
+}
+
+// newMap returns a Map with the supplied node store, and copies of the
+// built values and retained keys.
+func (b *Builder[T]) newMap(store []mapInternalNode) Map[T] {
+	m := Map[T]{store: store, values: b.copyValues(), maxKeyLen: b.maxKeyLen, keyTransform: b.keyTransform, fold: b.fold, metadata: b.metadata}
+	if b.retainKeys {
+		m.keys = make([]string, len(b.keys))
+		copy(m.keys, b.keys)
+		b.report.BytesAllocated += len(m.keys) * int(unsafe.Sizeof(""))
+	}
+	if b.withFingerprints {
+		m.setFingerprints(append([]byte{}, b.fingerprints...), b.salt)
+		b.report.BytesAllocated += len(m.fingerprints)
+	}
+	b.endPhase("values")
+	return m
+}
+
+func (b *Builder[T]) copyValues() []T {
+	values := allocValues[T](b.alloc, len(b.values))
+	copy(values, b.values)
+
+	var zero T
+	b.report.BytesAllocated += len(values) * int(unsafe.Sizeof(zero))
+	return values
+}
+
+func (b *Builder[T]) beginReport() time.Time {
+	b.report = BuildReport{}
+	start := time.Now()
+	b.logging = b.logger != nil && b.logger.Enabled(context.Background(), slog.LevelDebug)
+	b.phaseStart = start
+	return start
+}
+
+func (b *Builder[T]) endReport(start time.Time) {
+	b.report.Entries = len(b.order)
+	b.report.Duration = time.Since(start)
+	if b.logging {
+		b.logger.LogAttrs(context.Background(), slog.LevelDebug, "faststringmap: build",
+			slog.Int("entries", b.report.Entries), slog.Int("nodes", b.report.Nodes),
+			slog.Int("wasted_nodes", b.report.WastedNodes), slog.Duration("duration", b.report.Duration))
+	}
+}
@@ -0,0 +1,142 @@
+package faststringmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestBuilderReuse(t *testing.T) {
+	var b faststringmap.Builder[uint32]
+
+	first := randomSmallStrings(1024, 8)
+	for _, e := range first {
+		b.Add(e.Key, e.Value)
+	}
+	m1 := b.Build()
+
+	b.Reset()
+	if b.Len() != 0 {
+		t.Errorf("Len() after Reset = %d want 0", b.Len())
+	}
+
+	second := randomSmallStrings(1024, 8)
+	for _, e := range second {
+		b.Add(e.Key, e.Value)
+	}
+	m2 := b.Build()
+
+	// the first map must not be affected by reusing the builder
+	for _, e := range first {
+		if v, ok := m1.LookupString(e.Key); !ok || v != e.Value {
+			t.Errorf("first LookupString(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+	}
+	for _, e := range second {
+		if v, ok := m2.LookupString(e.Key); !ok || v != e.Value {
+			t.Errorf("second LookupString(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+	}
+}
+
+func BenchmarkBuilderReuse(b *testing.B) {
+	entries := randomSmallStrings(1<<16, 16)
+	var builder faststringmap.Builder[uint32]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		builder.Reset()
+		for _, e := range entries {
+			builder.Add(e.Key, e.Value)
+		}
+		builder.Build()
+	}
+}
+
+func TestBuilderReport(t *testing.T) {
+	var b faststringmap.Builder[uint32]
+	b.Add("a1", 1)
+	b.Add("a3", 2)
+	b.Build()
+
+	// root -> 'a' -> '1'..'3', where '2' does not continue any key
+	r := b.Report()
+	if r.Entries != 2 || r.Nodes != 5 || r.WastedNodes != 1 || r.Blocks != 1 {
+		t.Errorf("Report() = %+v want 2 entries, 5 nodes, 1 wasted node, 1 block", r)
+	}
+	if r.BytesAllocated <= 0 {
+		t.Errorf("Report().BytesAllocated = %d want > 0", r.BytesAllocated)
+	}
+}
+
+func TestBuilderRetainKeys(t *testing.T) {
+	var b faststringmap.Builder[int]
+	b.SetRetainKeys(true)
+	b.Add("alpha", 1)
+	b.Add("beta", 2)
+	m := b.Build()
+
+	probe := []byte("beta")
+	key, v, ok := m.LookupKeyBytes(probe)
+	if !ok || key != "beta" || v != 2 {
+		t.Errorf("LookupKeyBytes(beta) = %q, %v, %v want beta, 2, true", key, v, ok)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { m.LookupKeyBytes(probe) }); allocs != 0 {
+		t.Errorf("LookupKeyBytes allocates %v times want 0", allocs)
+	}
+	if key, v, ok := m.LookupKey("gamma"); ok {
+		t.Errorf("LookupKey(gamma) = %q, %v, expected not to be present", key, v)
+	}
+
+	plain := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"alpha", 1}})
+	if key, v, ok := plain.LookupKey("alpha"); ok {
+		t.Errorf("LookupKey(alpha) without retained keys = %q, %v, expected not ok", key, v)
+	}
+}
+
+func TestBuilderFingerprints(t *testing.T) {
+	entries := randomSmallStrings(1024, 8)
+
+	var b faststringmap.Builder[uint32]
+	b.SetFingerprints(true)
+	for _, e := range entries {
+		b.Add(e.Key, e.Value)
+	}
+	m := b.Build()
+
+	checkEntries(t, &m, entries)
+	for _, e := range entries {
+		if _, v, ok := m.LongestPrefixString(e.Key + "\x00"); !ok || v != e.Value {
+			t.Errorf("LongestPrefixString(%q) = %v, %v want %v, true", e.Key+"\x00", v, ok, e.Value)
+		}
+	}
+	if v, ok := m.LookupString("\x00not present"); ok {
+		t.Errorf("LookupString() = %v, expected not to be present", v)
+	}
+}
+
+func TestBuildLongKeys(t *testing.T) {
+	long := strings.Repeat("/segment", 1<<13) // 64 KiB
+	entries := []faststringmap.MapEntry[int]{
+		{long, 1},
+		{long + "/a", 2},
+		{long[:len(long)/2] + "x", 3},
+		{"short", 4},
+	}
+
+	var b faststringmap.Builder[int]
+	for _, e := range entries {
+		b.Add(e.Key, e.Value)
+	}
+	m := b.Build()
+	for _, e := range entries {
+		if v, ok := m.LookupString(e.Key); !ok || v != e.Value {
+			t.Errorf("LookupString(%d bytes) = %v, %v want %v, true", len(e.Key), v, ok, e.Value)
+		}
+	}
+	if n, err := b.NodeCount(); err != nil || n != b.Report().Nodes {
+		t.Errorf("NodeCount() = %d, %v want %d", n, err, b.Report().Nodes)
+	}
+}
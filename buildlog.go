@@ -0,0 +1,35 @@
+package faststringmap
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger makes builds log the duration of each of their phases, and a
+// summary, to logger at debug level. See Builder.SetLogger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *buildOptions) { o.logger = logger }
+}
+
+// SetLogger sets a logger to which builds log the duration of each of their
+// phases at debug level, so that long builds are observable: sorting the
+// keys ("sort"), analyzing duplicates ("duplicates") if enabled, counting
+// nodes for BuildInto ("count"), building the trie ("trie"), copying its
+// blocks into a single node store ("flatten") and copying the values
+// ("values"). A summary follows. Nothing is timed unless debug logging is
+// enabled for logger. A nil logger disables logging.
+func (b *Builder[T]) SetLogger(logger *slog.Logger) {
+	b.logger = logger
+}
+
+// endPhase logs the end of a build phase, if logging is enabled.
+func (b *Builder[T]) endPhase(phase string) {
+	if !b.logging {
+		return
+	}
+	now := time.Now()
+	b.logger.LogAttrs(context.Background(), slog.LevelDebug, "faststringmap: build phase",
+		slog.String("phase", phase), slog.Duration("duration", now.Sub(b.phaseStart)))
+	b.phaseStart = now
+}
@@ -0,0 +1,48 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestBuildLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	if _, err := faststringmap.New(goldenEntries, faststringmap.WithLogger(logger), faststringmap.WithDuplicateAnalysis()); err != nil {
+		t.Fatal(err)
+	}
+	for _, phase := range []string{"sort", "duplicates", "trie", "values"} {
+		if !strings.Contains(buf.String(), "phase="+phase+" ") {
+			t.Errorf("log does not contain phase %s:\n%s", phase, buf.String())
+		}
+	}
+	if !strings.Contains(buf.String(), `msg="faststringmap: build" entries=9 `) {
+		t.Errorf("log does not contain the build summary:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	var b faststringmap.Builder[uint32]
+	b.SetLogger(logger)
+	for _, e := range goldenEntries {
+		b.Add(e.Key, e.Value)
+	}
+	if _, _, err := b.BuildInto(make([]byte, 1<<16)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "phase=count ") {
+		t.Errorf("BuildInto log does not contain phase count:\n%s", buf.String())
+	}
+
+	// nothing is logged above debug level
+	buf.Reset()
+	b.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	b.Build()
+	if buf.Len() != 0 {
+		t.Errorf("build logged at info level:\n%s", buf.String())
+	}
+}
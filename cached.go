@@ -0,0 +1,102 @@
+package faststringmap
+
+import (
+	"container/list"
+	"sync"
+)
+
+// CachedMap[T] combines a static Map with a fallback source, such as a
+// database, which is consulted for keys not present in the map. Results of
+// the fallback, including misses, can optionally be kept in an LRU cache.
+// A CachedMap is safe for concurrent use.
+type CachedMap[T any] struct {
+	m        *Map[T]
+	fallback func(key string) (T, bool)
+
+	mu    sync.Mutex
+	size  int                      // maximum number of cached fallback results
+	lru   *list.List               // of *cacheEntry[T], most recently used first
+	items map[string]*list.Element // key -> element in lru
+}
+
+type cacheEntry[T any] struct {
+	key   string
+	value T
+	ok    bool
+}
+
+// NewCachedMap[T] constructs a CachedMap that looks up keys in m first, and
+// calls fallback for keys not present in m. Up to cacheSize fallback results
+// are cached, with least recently used results evicted first. A cacheSize of
+// zero disables caching, so fallback is called on every miss.
+func NewCachedMap[T any](m *Map[T], fallback func(key string) (T, bool), cacheSize int) *CachedMap[T] {
+	c := &CachedMap[T]{m: m, fallback: fallback, size: cacheSize}
+	if cacheSize > 0 {
+		c.lru = list.New()
+		c.items = make(map[string]*list.Element, cacheSize)
+	}
+	return c
+}
+
+// LookupString looks up the supplied string in the map, then in the cache of
+// fallback results, and finally using the fallback.
+func (c *CachedMap[T]) LookupString(s string) (t T, ok bool) {
+	if t, ok = c.m.LookupString(s); ok {
+		return t, true
+	}
+	return c.lookupFallback(s)
+}
+
+// LookupBytes looks up the supplied byte slice like LookupString. It only
+// allocates when the key is not present in the static map.
+func (c *CachedMap[T]) LookupBytes(s []byte) (t T, ok bool) {
+	if t, ok = c.m.LookupBytes(s); ok {
+		return t, true
+	}
+	return c.lookupFallback(string(s))
+}
+
+func (c *CachedMap[T]) lookupFallback(key string) (T, bool) {
+	if c.size <= 0 {
+		return c.fallback(key)
+	}
+
+	c.mu.Lock()
+	if el, found := c.items[key]; found {
+		c.lru.MoveToFront(el)
+		e := el.Value.(*cacheEntry[T])
+		c.mu.Unlock()
+		return e.value, e.ok
+	}
+	c.mu.Unlock()
+
+	// the fallback is called without holding the lock, as it may be slow
+	t, ok := c.fallback(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, found := c.items[key]; found {
+		c.lru.MoveToFront(el) // cached concurrently while calling fallback
+		return t, ok
+	}
+	c.items[key] = c.lru.PushFront(&cacheEntry[T]{key, t, ok})
+	if c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry[T]).key)
+	}
+
+	return t, ok
+}
+
+// Purge removes all cached fallback results.
+func (c *CachedMap[T]) Purge() {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Init()
+	c.items = make(map[string]*list.Element, c.size)
+}
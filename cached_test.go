@@ -0,0 +1,62 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestCachedMap(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"a", 1}, {"b", 2}})
+
+	calls := map[string]int{}
+	fallback := func(key string) (int, bool) {
+		calls[key]++
+		if key == "c" {
+			return 3, true
+		}
+		return 0, false
+	}
+	c := faststringmap.NewCachedMap(&m, fallback, 2)
+
+	for i := 0; i < 3; i++ {
+		if v, ok := c.LookupString("a"); !ok || v != 1 {
+			t.Errorf("LookupString(a) = %v, %v want 1, true", v, ok)
+		}
+		if v, ok := c.LookupBytes([]byte("c")); !ok || v != 3 {
+			t.Errorf("LookupBytes(c) = %v, %v want 3, true", v, ok)
+		}
+		if v, ok := c.LookupString("x"); ok {
+			t.Errorf("LookupString(x) = %v, expected not to be present", v)
+		}
+	}
+
+	if calls["a"] != 0 || calls["c"] != 1 || calls["x"] != 1 {
+		t.Errorf("fallback calls = %v want c and x called once each", calls)
+	}
+
+	// "y" evicts "c", the least recently used result
+	c.LookupString("y")
+	c.LookupString("c")
+	if calls["c"] != 2 {
+		t.Errorf("fallback calls for c after eviction = %d want 2", calls["c"])
+	}
+
+	c.Purge()
+	c.LookupString("x")
+	if calls["x"] != 2 {
+		t.Errorf("fallback calls for x after Purge = %d want 2", calls["x"])
+	}
+}
+
+func TestCachedMapWithoutCache(t *testing.T) {
+	m := faststringmap.NewMap[int](nil)
+	calls := 0
+	c := faststringmap.NewCachedMap(&m, func(string) (int, bool) { calls++; return 0, false }, 0)
+
+	c.LookupString("x")
+	c.LookupString("x")
+	if calls != 2 {
+		t.Errorf("fallback calls = %d want 2", calls)
+	}
+}
@@ -0,0 +1,100 @@
+package faststringmap
+
+import (
+	"encoding/binary"
+	"encoding/json"
+)
+
+// ValueCodec[T] encodes and decodes map values when a map is serialized.
+// Encodings must be deterministic for serialized maps to be reproducible.
+type ValueCodec[T any] interface {
+	// AppendValue appends the encoding of v to dst and returns the extended slice.
+	AppendValue(dst []byte, v T) ([]byte, error)
+
+	// DecodeValue decodes a value from exactly the bytes appended by AppendValue.
+	// src must not be retained, as it may refer to memory owned by the caller.
+	DecodeValue(src []byte) (T, error)
+}
+
+// Integer is a constraint for all integer types.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type (
+	// IntCodec[T] encodes integer values as varints.
+	IntCodec[T Integer] struct{}
+
+	// StringCodec encodes string values as their raw bytes.
+	StringCodec struct{}
+
+	// BytesCodec encodes byte slice values as their raw bytes.
+	BytesCodec struct{}
+
+	// JSONCodec[T] encodes values of any type using encoding/json.
+	JSONCodec[T any] struct{}
+)
+
+func (IntCodec[T]) AppendValue(dst []byte, v T) ([]byte, error) {
+	var buf [binary.MaxVarintLen64]byte
+	var n int
+	if isSigned[T]() {
+		n = binary.PutVarint(buf[:], int64(v))
+	} else {
+		n = binary.PutUvarint(buf[:], uint64(v))
+	}
+	return append(dst, buf[:n]...), nil
+}
+
+func (IntCodec[T]) DecodeValue(src []byte) (T, error) {
+	var v T
+	var n int
+	if isSigned[T]() {
+		var x int64
+		x, n = binary.Varint(src)
+		v = T(x)
+	} else {
+		var x uint64
+		x, n = binary.Uvarint(src)
+		v = T(x)
+	}
+	if n != len(src) {
+		return 0, ErrInvalidEncoding
+	}
+	return v, nil
+}
+
+func isSigned[T Integer]() bool {
+	return ^T(0) < 0
+}
+
+func (StringCodec) AppendValue(dst []byte, v string) ([]byte, error) {
+	return append(dst, v...), nil
+}
+
+func (StringCodec) DecodeValue(src []byte) (string, error) {
+	return string(src), nil
+}
+
+func (BytesCodec) AppendValue(dst []byte, v []byte) ([]byte, error) {
+	return append(dst, v...), nil
+}
+
+func (BytesCodec) DecodeValue(src []byte) ([]byte, error) {
+	return append([]byte(nil), src...), nil
+}
+
+func (JSONCodec[T]) AppendValue(dst []byte, v T) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, b...), nil
+}
+
+func (JSONCodec[T]) DecodeValue(src []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(src, &v)
+	return v, err
+}
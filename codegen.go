@@ -0,0 +1,220 @@
+package faststringmap
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoSource[T] configures the Go source code generated by WriteGoSource.
+type GoSource[T any] struct {
+	Package string   // name of the generated package
+	Imports []string // import paths used by ValueType or FormatValue
+	Var     string   // name of the generated package level variable
+	Doc     string   // doc comment of the variable, without comment markers
+
+	// ValueType is the Go type of the values. It defaults to the name of T
+	// as reported by the reflect package.
+	ValueType string
+
+	// FormatValue formats a value as a Go expression. It defaults to
+	// formatting values using the %#v verb of the fmt package.
+	FormatValue func(T) string
+
+	// Constructor is the name of the function of this package constructing
+	// the variable from the entries, such as NewSuffixMap. It defaults to
+	// NewMap.
+	Constructor string
+}
+
+// WriteGoSource writes a gofmt-formatted Go source file to w, declaring a
+// package level variable holding the supplied entries, which is a Map unless
+// src.Constructor says otherwise. This allows
+// compiling static dictionaries into a program. Entries are written in
+// ascending key order, so the output does not depend on the order of
+// entries, and keys must be unique.
+func WriteGoSource[T any](w io.Writer, src GoSource[T], entries []MapEntry[T]) error {
+	sorted := append([]MapEntry[T](nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Key == sorted[i-1].Key {
+			return fmt.Errorf("faststringmap: duplicate key %q", sorted[i].Key)
+		}
+	}
+
+	valueType := src.ValueType
+	if valueType == "" {
+		valueType = reflect.TypeOf((*T)(nil)).Elem().String()
+	}
+	formatValue := src.FormatValue
+	if formatValue == nil {
+		formatValue = func(v T) string { return fmt.Sprintf("%#v", v) }
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by faststringmap.WriteGoSource; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", src.Package)
+
+	fmt.Fprintf(&buf, "import (\n")
+	for _, path := range append([]string{"alon.kr/x/faststringmap"}, src.Imports...) {
+		fmt.Fprintf(&buf, "\t%s\n", strconv.Quote(path))
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+
+	if src.Doc != "" {
+		for _, line := range bytes.Split([]byte(src.Doc), []byte("\n")) {
+			fmt.Fprintf(&buf, "// %s\n", line)
+		}
+	}
+	constructor := src.Constructor
+	if constructor == "" {
+		constructor = "NewMap"
+	}
+
+	fmt.Fprintf(&buf, "var %s = faststringmap.%s([]faststringmap.MapEntry[%s]{\n", src.Var, constructor, valueType)
+	for _, e := range sorted {
+		fmt.Fprintf(&buf, "\t{Key: %s, Value: %s},\n", strconv.Quote(e.Key), formatValue(e.Value))
+	}
+	fmt.Fprintf(&buf, "})\n")
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("faststringmap: formatting generated source: %w", err)
+	}
+
+	_, err = w.Write(formatted)
+	return err
+}
+
+// GoEnum configures the Go source code generated by WriteGoEnum.
+type GoEnum struct {
+	Package string // name of the generated package
+	Type    string // name of the generated enum type, such as Color
+	Doc     string // doc comment of the type, without comment markers
+
+	// ConstName returns the name of the constant of an enum value. It
+	// defaults to the name of the type followed by the words of the enum
+	// name, capitalized, so "dark-red" is ColorDarkRed.
+	ConstName func(name string) string
+}
+
+// WriteGoEnum writes a gofmt-formatted Go source file to w, declaring a
+// typed enum with a constant for each of the supplied names, a function
+// parsing names to enum values, backed by a Map, and a String method. For
+// an enum type Color, these are
+//
+//	type Color uint32
+//	func ParseColor(s []byte) (Color, bool)
+//	func ParseColorString(s string) (Color, bool)
+//	func (c Color) String() string
+//
+// Enum values are numbered from 1 in the order of names, so the zero value
+// is not a valid enum value, and appending names keeps the values of the
+// previous ones. Names must be unique, and so must the constant names.
+func WriteGoEnum(w io.Writer, enum GoEnum, names []string) error {
+	if !token.IsIdentifier(enum.Type) {
+		return fmt.Errorf("faststringmap: invalid enum type name %q", enum.Type)
+	}
+	constName := enum.ConstName
+	if constName == nil {
+		constName = func(name string) string { return enum.Type + exportedWords(name) }
+	}
+
+	consts := make([]string, len(names))
+	seenNames, seenConsts := map[string]bool{}, map[string]bool{}
+	for i, name := range names {
+		consts[i] = constName(name)
+		if !token.IsIdentifier(consts[i]) {
+			return fmt.Errorf("faststringmap: invalid constant name %q for %q", consts[i], name)
+		}
+		if seenNames[name] {
+			return fmt.Errorf("faststringmap: duplicate key %q", name)
+		}
+		if seenConsts[consts[i]] {
+			return fmt.Errorf("faststringmap: duplicate constant %s for %q", consts[i], name)
+		}
+		seenNames[name], seenConsts[consts[i]] = true, true
+	}
+
+	sorted := make([]int, len(names))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.Slice(sorted, func(i, j int) bool { return names[sorted[i]] < names[sorted[j]] })
+
+	typ := enum.Type
+	unexported := strings.ToLower(typ[:1]) + typ[1:]
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by faststringmap.WriteGoEnum; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", enum.Package)
+	fmt.Fprintf(&buf, "import (\n\t\"strconv\"\n\n\t\"alon.kr/x/faststringmap\"\n)\n\n")
+
+	if enum.Doc != "" {
+		for _, line := range bytes.Split([]byte(enum.Doc), []byte("\n")) {
+			fmt.Fprintf(&buf, "// %s\n", line)
+		}
+	}
+	fmt.Fprintf(&buf, "type %s uint32\n\n", typ)
+
+	fmt.Fprintf(&buf, "const (\n")
+	for i, name := range consts {
+		if i == 0 {
+			fmt.Fprintf(&buf, "\t%s %s = iota + 1 // %s\n", name, typ, strconv.Quote(names[i]))
+		} else {
+			fmt.Fprintf(&buf, "\t%s // %s\n", name, strconv.Quote(names[i]))
+		}
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintf(&buf, "var %sNames = [...]string{\"\"", unexported)
+	for _, name := range names {
+		fmt.Fprintf(&buf, ", %s", strconv.Quote(name))
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "var %sValues = faststringmap.NewMap([]faststringmap.MapEntry[%s]{\n", unexported, typ)
+	for _, i := range sorted {
+		fmt.Fprintf(&buf, "\t{Key: %s, Value: %s},\n", strconv.Quote(names[i]), consts[i])
+	}
+	fmt.Fprintf(&buf, "})\n\n")
+
+	fmt.Fprintf(&buf, "// Parse%[1]s returns the %[1]s named s.\n", typ)
+	fmt.Fprintf(&buf, "func Parse%[1]s(s []byte) (%[1]s, bool) {\n\treturn %[2]sValues.LookupBytes(s)\n}\n\n", typ, unexported)
+	fmt.Fprintf(&buf, "// Parse%[1]sString returns the %[1]s named s.\n", typ)
+	fmt.Fprintf(&buf, "func Parse%[1]sString(s string) (%[1]s, bool) {\n\treturn %[2]sValues.LookupString(s)\n}\n\n", typ, unexported)
+	fmt.Fprintf(&buf, "// String returns the name of c, or %[1]s(c) with the number of c if c\n// is not a valid %[1]s.\n", typ)
+	fmt.Fprintf(&buf, "func (c %[1]s) String() string {\n", typ)
+	fmt.Fprintf(&buf, "\tif c == 0 || int(c) >= len(%sNames) {\n", unexported)
+	fmt.Fprintf(&buf, "\t\treturn \"%s(\" + strconv.FormatUint(uint64(c), 10) + \")\"\n\t}\n", typ)
+	fmt.Fprintf(&buf, "\treturn %sNames[c]\n}\n", unexported)
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("faststringmap: formatting generated source: %w", err)
+	}
+
+	_, err = w.Write(formatted)
+	return err
+}
+
+// exportedWords returns the words of name, made of letters and digits,
+// each with its first letter in upper case, so "dark-red" is DarkRed.
+func exportedWords(name string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestWriteGoSource(t *testing.T) {
+	var buf bytes.Buffer
+	err := faststringmap.WriteGoSource(&buf, faststringmap.GoSource[int]{
+		Package: "colors",
+		Var:     "Colors",
+		Doc:     "Colors maps color names to RGB values.",
+	}, []faststringmap.MapEntry[int]{{"red", 0xff0000}, {"blue", 0x0000ff}})
+	if err != nil {
+		t.Fatalf("WriteGoSource() error = %v", err)
+	}
+
+	want := `// Code generated by faststringmap.WriteGoSource; DO NOT EDIT.
+
+package colors
+
+import (
+	"alon.kr/x/faststringmap"
+)
+
+// Colors maps color names to RGB values.
+var Colors = faststringmap.NewMap([]faststringmap.MapEntry[int]{
+	{Key: "blue", Value: 255},
+	{Key: "red", Value: 16711680},
+})
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteGoSource() wrote:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteGoSourceDuplicateKey(t *testing.T) {
+	err := faststringmap.WriteGoSource(&bytes.Buffer{}, faststringmap.GoSource[int]{Package: "p", Var: "V"},
+		[]faststringmap.MapEntry[int]{{"a", 1}, {"a", 2}})
+	if err == nil || !strings.Contains(err.Error(), `"a"`) {
+		t.Errorf("WriteGoSource() error = %v want duplicate key error", err)
+	}
+}
+
+func TestWriteGoSourceConstructor(t *testing.T) {
+	var buf bytes.Buffer
+	err := faststringmap.WriteGoSource(&buf, faststringmap.GoSource[string]{
+		Package:     "files",
+		Var:         "Extensions",
+		Constructor: "NewSuffixMap",
+	}, []faststringmap.MapEntry[string]{{".go", "Go"}})
+	if err != nil {
+		t.Fatalf("WriteGoSource() error = %v", err)
+	}
+	if want := "var Extensions = faststringmap.NewSuffixMap([]faststringmap.MapEntry[string]{"; !strings.Contains(buf.String(), want) {
+		t.Errorf("WriteGoSource() wrote:\n%s\nwant it to contain %s", buf.String(), want)
+	}
+}
+
+// enum_gen_test.go is generated by WriteGoEnum, and compiled with the tests.
+func TestWriteGoEnum(t *testing.T) {
+	var buf bytes.Buffer
+	err := faststringmap.WriteGoEnum(&buf, faststringmap.GoEnum{
+		Package: "faststringmap_test",
+		Type:    "Color",
+		Doc:     "Color is a color of the test enum.",
+	}, []string{"red", "green", "dark-blue"})
+	if err != nil {
+		t.Fatalf("WriteGoEnum() error = %v", err)
+	}
+	want, err := os.ReadFile("enum_gen_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("WriteGoEnum() wrote:\n%s\nwant the contents of enum_gen_test.go:\n%s", buf.Bytes(), want)
+	}
+
+	for _, tc := range []struct {
+		name string
+		want Color
+	}{{"red", ColorRed}, {"green", ColorGreen}, {"dark-blue", ColorDarkBlue}} {
+		if c, ok := ParseColor([]byte(tc.name)); !ok || c != tc.want {
+			t.Errorf("ParseColor(%q) = %v, %v want %v", tc.name, c, ok, tc.want)
+		}
+		if c, ok := ParseColorString(tc.name); !ok || c != tc.want {
+			t.Errorf("ParseColorString(%q) = %v, %v want %v", tc.name, c, ok, tc.want)
+		}
+		if got := tc.want.String(); got != tc.name {
+			t.Errorf("%d.String() = %q want %q", tc.want, got, tc.name)
+		}
+	}
+	if c, ok := ParseColorString("blue"); ok {
+		t.Errorf("ParseColorString(blue) = %v, true want false", c)
+	}
+	if ColorRed != 1 || ColorDarkBlue != 3 {
+		t.Errorf("ColorRed, ColorDarkBlue = %d, %d want 1, 3", ColorRed, ColorDarkBlue)
+	}
+	for c, want := range map[Color]string{0: "Color(0)", 4: "Color(4)"} {
+		if got := c.String(); got != want {
+			t.Errorf("Color(%d).String() = %q want %q", uint32(c), got, want)
+		}
+	}
+}
+
+func TestWriteGoEnumErrors(t *testing.T) {
+	for _, tc := range []struct {
+		enum  faststringmap.GoEnum
+		names []string
+	}{
+		{faststringmap.GoEnum{Package: "p", Type: "Color"}, []string{"red", "red"}},
+		{faststringmap.GoEnum{Package: "p", Type: "Color"}, []string{"dark-red", "dark red"}}, // both ColorDarkRed
+		{faststringmap.GoEnum{Package: "p", Type: "Color", ConstName: func(string) string { return "1x" }}, []string{"red"}},
+		{faststringmap.GoEnum{Package: "p", Type: ""}, []string{"red"}},
+	} {
+		if err := faststringmap.WriteGoEnum(&bytes.Buffer{}, tc.enum, tc.names); err == nil {
+			t.Errorf("WriteGoEnum(%q, %q) succeeded", tc.enum.Type, tc.names)
+		}
+	}
+}
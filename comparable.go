@@ -0,0 +1,97 @@
+package faststringmap
+
+import (
+	"slices"
+	"strings"
+)
+
+// The functions in this file provide extra functionality for maps with
+// comparable value types. They are free functions rather than methods,
+// because methods of Map[T] can not constrain T further than any.
+
+// Equal reports whether a and b hold the same keys with equal values.
+func Equal[T comparable](a, b *Map[T]) bool {
+	if a.lenOrZero() != b.lenOrZero() {
+		return false
+	}
+
+	equal := true
+	a.walk(func(key []byte, index Uint) bool {
+		av, _ := a.AtIndex(index)
+		bv, ok := b.LookupBytes(key)
+		equal = ok && av == bv
+		return equal
+	})
+	return equal
+}
+
+// DistinctValues returns the distinct values in the map, ordered by the
+// smallest key each value is associated with.
+func DistinctValues[T comparable](m *Map[T]) []T {
+	seen := map[T]struct{}{}
+	var values []T
+	m.walk(func(_ []byte, index Uint) bool {
+		v, _ := m.AtIndex(index)
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			values = append(values, v)
+		}
+		return true
+	})
+	return values
+}
+
+// BuildReverseIndex returns a builtin Go map from each value in the map to
+// the smallest key associated with it.
+func BuildReverseIndex[T comparable](m *Map[T]) map[T]string {
+	reverse := map[T]string{}
+	m.walk(func(key []byte, index Uint) bool {
+		v, _ := m.AtIndex(index)
+		if _, ok := reverse[v]; !ok {
+			reverse[v] = string(key)
+		}
+		return true
+	})
+	return reverse
+}
+
+// GroupByValue returns a builtin Go map from each value in the map to all the
+// keys associated with it, in ascending order.
+func GroupByValue[T comparable](m *Map[T]) map[T][]string {
+	groups := map[T][]string{}
+	m.walk(func(key []byte, index Uint) bool {
+		v, _ := m.AtIndex(index)
+		groups[v] = append(groups[v], string(key))
+		return true
+	})
+	return groups
+}
+
+// Diff returns the delta turning old into new, in ascending key order, for
+// AppendDelta. Keys are compared as stored, so both maps should be built
+// with the same key transform.
+func Diff[T comparable](old, new *Map[T]) []DeltaEntry[T] {
+	var delta []DeltaEntry[T]
+	old.walk(func(key []byte, index Uint) bool {
+		ov, _ := old.AtIndex(index)
+		nv, ok := new.AtIndex(storedIndex(new, key))
+		switch {
+		case !ok:
+			delta = append(delta, DeltaEntry[T]{Op: DeltaRemove, Key: string(key)})
+		case ov != nv:
+			delta = append(delta, DeltaEntry[T]{Op: DeltaChange, Key: string(key), Value: nv})
+		}
+		return true
+	})
+	new.walk(func(key []byte, index Uint) bool {
+		if storedIndex(old, key) == 0 {
+			nv, _ := new.AtIndex(index)
+			delta = append(delta, DeltaEntry[T]{Op: DeltaAdd, Key: string(key), Value: nv})
+		}
+		return true
+	})
+	slices.SortFunc(delta, func(a, b DeltaEntry[T]) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return delta
+}
@@ -0,0 +1,58 @@
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+var colorEntries = []faststringmap.MapEntry[string]{
+	{"blue", "cool"},
+	{"green", "cool"},
+	{"red", "warm"},
+	{"yellow", "warm"},
+	{"grey", "neutral"},
+}
+
+func TestEqual(t *testing.T) {
+	a := faststringmap.NewMap(colorEntries)
+	b := faststringmap.NewMap(colorEntries[:4])
+	c := faststringmap.NewMap(append(colorEntries[:4:4], faststringmap.MapEntry[string]{"grey", "warm"}))
+	a2 := faststringmap.NewMap(colorEntries)
+
+	if !faststringmap.Equal(&a, &a2) {
+		t.Errorf("Equal(a, a2) = false want true")
+	}
+	if faststringmap.Equal(&a, &b) || faststringmap.Equal(&b, &a) {
+		t.Errorf("Equal of maps with different keys = true want false")
+	}
+	if faststringmap.Equal(&a, &c) {
+		t.Errorf("Equal of maps with different values = true want false")
+	}
+	if !faststringmap.Equal[string](nil, &faststringmap.Map[string]{}) {
+		t.Errorf("Equal(nil, empty) = false want true")
+	}
+}
+
+func TestValueGrouping(t *testing.T) {
+	m := faststringmap.NewMap(colorEntries)
+
+	if got, want := faststringmap.DistinctValues(&m), []string{"cool", "neutral", "warm"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DistinctValues() = %v want %v", got, want)
+	}
+
+	wantReverse := map[string]string{"cool": "blue", "neutral": "grey", "warm": "red"}
+	if got := faststringmap.BuildReverseIndex(&m); !reflect.DeepEqual(got, wantReverse) {
+		t.Errorf("BuildReverseIndex() = %v want %v", got, wantReverse)
+	}
+
+	wantGroups := map[string][]string{
+		"cool":    {"blue", "green"},
+		"neutral": {"grey"},
+		"warm":    {"red", "yellow"},
+	}
+	if got := faststringmap.GroupByValue(&m); !reflect.DeepEqual(got, wantGroups) {
+		t.Errorf("GroupByValue() = %v want %v", got, wantGroups)
+	}
+}
@@ -0,0 +1,64 @@
+package faststringmap
+
+import "crypto/subtle"
+
+// LookupStringConstantTime looks up the supplied string in the map like
+// LookupString, but without exiting early on a mismatch: it always consumes
+// every byte of s, and does the same work per byte wherever the mismatch
+// occurs, so the time taken does not reveal how long a prefix of s matches a
+// key. This is meant for tables of secrets such as API keys or tokens.
+//
+// Probes are canonicalized like LookupString: a key transform is applied
+// first, and the time it takes is up to the transform, while folding (see
+// WithFold) costs the same for every byte.
+//
+// The time taken still depends on the length of s, and the memory accessed
+// depends on the matched prefix, so it can leak through cache timing. Whether
+// s is present is not hidden either.
+func (m *Map[T]) LookupStringConstantTime(s string) (t T, ok bool) {
+	if m != nil && m.keyTransform != nil {
+		return m.LookupBytesConstantTime(stringBytes(s))
+	}
+	return m.AtIndex(indexConstantTime(m, s))
+}
+
+// LookupBytesConstantTime looks up the supplied byte slice like
+// LookupStringConstantTime.
+func (m *Map[T]) LookupBytesConstantTime(s []byte) (t T, ok bool) {
+	if m != nil && m.keyTransform != nil {
+		s = m.keyTransform(s)
+	}
+	return m.AtIndex(indexConstantTime(m, s))
+}
+
+func indexConstantTime[T any, S string | []byte](m *Map[T], s S) Uint {
+	if m == nil || len(m.store) == 0 {
+		return 0
+	}
+
+	fold := 0
+	if m.fold {
+		fold = 1
+	}
+
+	h := m.fingerprintSeed // fingerprint of the folded probe, computed on the way
+	cur, alive := 0, 1     // once a byte does not match, cur stays on the root
+	for i := 0; i < len(s); i++ {
+		b := int(s[i])
+		upper := subtle.ConstantTimeLessOrEq('A', b) & subtle.ConstantTimeLessOrEq(b, 'Z')
+		b += (upper & fold) * ('a' - 'A')
+		h = (h ^ uint32(b)) * 16777619
+
+		bv := &m.store[cur]
+		lo := int(bv.nextOffset)
+		alive &= subtle.ConstantTimeLessOrEq(lo, b) &
+			subtle.ConstantTimeLessOrEq(b+1, lo+int(bv.nextLen))
+		cur = subtle.ConstantTimeSelect(alive, int(bv.nextLo)+b-lo, 0)
+	}
+
+	index := Uint(subtle.ConstantTimeSelect(alive, int(m.store[cur].valueOffset), 0))
+	if index != 0 && m.fingerprints != nil {
+		return m.verifyFingerprint(index, byte(h^h>>8^h>>16^h>>24))
+	}
+	return index
+}
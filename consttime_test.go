@@ -0,0 +1,71 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestLookupConstantTime(t *testing.T) {
+	entries := randomSmallStrings(1000, 8)
+	m := faststringmap.NewMap(entries)
+
+	probes := []string{"", "\x00", "\xff\xff\xff\xff\xff\xff\xff\xff\xff"}
+	for _, e := range entries[:200] {
+		probes = append(probes, e.Key, e.Key+"\x00", e.Key+"z")
+		if len(e.Key) > 0 {
+			probes = append(probes, e.Key[:len(e.Key)-1], e.Key[1:])
+		}
+	}
+
+	for _, p := range probes {
+		want, wantOk := m.LookupString(p)
+		if got, ok := m.LookupStringConstantTime(p); got != want || ok != wantOk {
+			t.Errorf("LookupStringConstantTime(%q) = %v, %v want %v, %v", p, got, ok, want, wantOk)
+		}
+		if got, ok := m.LookupBytesConstantTime([]byte(p)); got != want || ok != wantOk {
+			t.Errorf("LookupBytesConstantTime(%q) = %v, %v want %v, %v", p, got, ok, want, wantOk)
+		}
+	}
+
+	var nilMap *faststringmap.Map[uint32]
+	if _, ok := nilMap.LookupStringConstantTime(""); ok {
+		t.Errorf("LookupStringConstantTime() in nil map reported present")
+	}
+}
+
+func TestLookupConstantTimeCanonical(t *testing.T) {
+	entries := []faststringmap.MapEntry[uint32]{
+		{Key: "Alpha", Value: 1},
+		{Key: "beta", Value: 2},
+		{Key: "gamma-Z", Value: 3},
+	}
+
+	for _, tc := range []struct {
+		name string
+		opts []faststringmap.Option
+	}{
+		{"fold", []faststringmap.Option{faststringmap.WithFold()}},
+		{"fold fingerprints", []faststringmap.Option{faststringmap.WithFold(), faststringmap.WithFingerprints()}},
+		{"transform", []faststringmap.Option{faststringmap.WithKeyTransform(bytes.TrimSpace)}},
+		{"transform fold", []faststringmap.Option{faststringmap.WithKeyTransform(bytes.TrimSpace), faststringmap.WithFold()}},
+	} {
+		m, err := faststringmap.New(entries, tc.opts...)
+		if err != nil {
+			t.Fatalf("%s: New() error: %v", tc.name, err)
+		}
+		for _, p := range []string{"alpha", "ALPHA", " Beta ", "GAMMA-z", "gamma-z ", "gamma", "delta", "@", "[", ""} {
+			want, wantOk := m.LookupString(p)
+			if got, ok := m.LookupStringConstantTime(p); got != want || ok != wantOk {
+				t.Errorf("%s: LookupStringConstantTime(%q) = %v, %v want %v, %v", tc.name, p, got, ok, want, wantOk)
+			}
+			if got, ok := m.LookupBytesConstantTime([]byte(p)); got != want || ok != wantOk {
+				t.Errorf("%s: LookupBytesConstantTime(%q) = %v, %v want %v, %v", tc.name, p, got, ok, want, wantOk)
+			}
+		}
+		if _, ok := m.LookupStringConstantTime("ALPHA"); ok != (tc.name != "transform") {
+			t.Errorf("%s: LookupStringConstantTime(%q) ok = %v", tc.name, "ALPHA", ok)
+		}
+	}
+}
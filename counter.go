@@ -0,0 +1,66 @@
+package faststringmap
+
+import (
+	"sync/atomic"
+)
+
+// CounterMap is a fixed set of string keys, each associated with a uint64
+// counter that can be updated concurrently using atomic operations.
+// Keys are resolved using the same trie as Map, so updates cost a single
+// lookup plus an atomic add, and no locking is required.
+type CounterMap struct {
+	keys   Map[struct{}]
+	counts []uint64
+}
+
+// NewCounterMap constructs a new CounterMap for the provided keys, with all
+// counters initialized to zero. Keys must be unique.
+func NewCounterMap(keys []string) *CounterMap {
+	entries := make([]MapEntry[struct{}], len(keys))
+	for i, k := range keys {
+		entries[i].Key = k
+	}
+
+	keyMap := NewMap(entries)
+	return &CounterMap{
+		keys:   keyMap,
+		counts: make([]uint64, len(keyMap.values)),
+	}
+}
+
+// Add atomically adds delta to the counter of the supplied key, and returns
+// the new value. ok is false if the key is not present in the map.
+func (c *CounterMap) Add(key string, delta uint64) (v uint64, ok bool) {
+	return c.addIndex(c.keys.IndexString(key), delta)
+}
+
+// AddBytes atomically adds delta to the counter of the supplied byte slice
+// key, and returns the new value. ok is false if the key is not present in
+// the map.
+func (c *CounterMap) AddBytes(key []byte, delta uint64) (v uint64, ok bool) {
+	return c.addIndex(c.keys.IndexBytes(key), delta)
+}
+
+// Load atomically loads the current counter value of the supplied key.
+func (c *CounterMap) Load(key string) (v uint64, ok bool) {
+	index := c.keys.IndexString(key)
+	if index == 0 {
+		return 0, false
+	}
+	return atomic.LoadUint64(&c.counts[index-1]), true
+}
+
+// Reset atomically sets all counters to zero, one at a time. Updates that
+// happen concurrently with Reset may or may not be preserved.
+func (c *CounterMap) Reset() {
+	for i := range c.counts {
+		atomic.StoreUint64(&c.counts[i], 0)
+	}
+}
+
+func (c *CounterMap) addIndex(index Uint, delta uint64) (uint64, bool) {
+	if index == 0 {
+		return 0, false
+	}
+	return atomic.AddUint64(&c.counts[index-1], delta), true
+}
@@ -0,0 +1,57 @@
+package faststringmap_test
+
+import (
+	"sync"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestCounterMapAdd(t *testing.T) {
+	c := faststringmap.NewCounterMap([]string{"GET", "POST", "PUT"})
+
+	if v, ok := c.Add("GET", 2); !ok || v != 2 {
+		t.Errorf("Add(GET, 2) = %v, %v want 2, true", v, ok)
+	}
+	if v, ok := c.AddBytes([]byte("GET"), 3); !ok || v != 5 {
+		t.Errorf("AddBytes(GET, 3) = %v, %v want 5, true", v, ok)
+	}
+	if v, ok := c.Add("DELETE", 1); ok {
+		t.Errorf("Add(DELETE, 1) = %v, expected not to be present", v)
+	}
+	if v, ok := c.Load("POST"); !ok || v != 0 {
+		t.Errorf("Load(POST) = %v, %v want 0, true", v, ok)
+	}
+
+	c.Reset()
+	if v, ok := c.Load("GET"); !ok || v != 0 {
+		t.Errorf("Load(GET) after Reset = %v, %v want 0, true", v, ok)
+	}
+}
+
+func TestCounterMapConcurrentAdd(t *testing.T) {
+	const nWorkers, nAdds = 8, 1000
+	keys := []string{"a", "b", "c"}
+	c := faststringmap.NewCounterMap(keys)
+
+	var wg sync.WaitGroup
+	for w := 0; w < nWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < nAdds; i++ {
+				c.Add(keys[i%len(keys)], 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var total uint64
+	for _, k := range keys {
+		v, _ := c.Load(k)
+		total += v
+	}
+	if total != nWorkers*nAdds {
+		t.Errorf("total = %d want %d", total, nWorkers*nAdds)
+	}
+}
@@ -0,0 +1,77 @@
+package faststringmap
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// String returns a concise summary of the map, for example
+// "faststringmap.Map[int]: 12,405 keys, 38,112 nodes, 1.2 MiB". It takes
+// constant time, so it is cheap enough for logging.
+func (m *Map[T]) String() string {
+	nodes, size := 0, 0
+	if m != nil {
+		nodes, size = len(m.store), m.size()
+	}
+	return fmt.Sprintf("faststringmap.Map[%s]: %s keys, %s nodes, %s",
+		reflect.TypeOf((*T)(nil)).Elem(), groupThousands(m.lenOrZero()), groupThousands(nodes), formatBytes(size))
+}
+
+// DebugDump writes the summary returned by String to w, followed by up to
+// maxKeys entries of the map in ascending key order, one per line.
+func (m *Map[T]) DebugDump(w io.Writer, maxKeys int) error {
+	summary := m.String()
+	if _, err := fmt.Fprintln(w, summary); err != nil {
+		return err
+	}
+
+	var err error
+	dumped := 0
+	m.walk(func(key []byte, index Uint) bool {
+		if dumped == maxKeys {
+			return false
+		}
+		v, _ := m.AtIndex(index)
+		_, err = fmt.Fprintf(w, "\t%q: %v\n", key, v)
+		dumped++
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if more := m.lenOrZero() - dumped; more > 0 {
+		_, err = fmt.Fprintf(w, "\t... %s more\n", groupThousands(more))
+	}
+	return err
+}
+
+func groupThousands(n int) string {
+	s := strconv.Itoa(n)
+	start := len(s) % 3
+	if start == 0 {
+		start = 3
+	}
+
+	out := s[:start]
+	for i := start; i < len(s); i += 3 {
+		out += "," + s[i:i+3]
+	}
+	return out
+}
+
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.Itoa(n) + " B"
+	}
+
+	div, exp := unit, 0
+	for n/div >= unit && exp < 3 {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
@@ -0,0 +1,46 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestString(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"a", 1}, {"b", 2}})
+
+	want := "faststringmap.Map[int]: 2 keys, 3 nodes, 52 B"
+	if got := fmt.Sprint(&m); got != want {
+		t.Errorf("String() = %q want %q", got, want)
+	}
+
+	large := faststringmap.NewMap(randomSmallStrings(4096, 8))
+	if got := large.String(); !strings.Contains(got, ": 4,096 keys, ") || !strings.HasSuffix(got, " KiB") {
+		t.Errorf("String() = %q want 4,096 keys and a size in KiB", got)
+	}
+
+	var nilMap *faststringmap.Map[int]
+	if got, want := nilMap.String(), "faststringmap.Map[int]: 0 keys, 0 nodes, 0 B"; got != want {
+		t.Errorf("nil.String() = %q want %q", got, want)
+	}
+}
+
+func TestDebugDump(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"c", 3}, {"a", 1}, {"b", 2}})
+
+	var buf bytes.Buffer
+	if err := m.DebugDump(&buf, 2); err != nil {
+		t.Fatalf("DebugDump() error = %v", err)
+	}
+
+	want := m.String() + "\n" +
+		"\t\"a\": 1\n" +
+		"\t\"b\": 2\n" +
+		"\t... 1 more\n"
+	if got := buf.String(); got != want {
+		t.Errorf("DebugDump() wrote %q want %q", got, want)
+	}
+}
@@ -0,0 +1,591 @@
+package faststringmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+	"strings"
+)
+
+// A serialized delta lists entries to add to, remove from or change in a
+// map, so that a large map can be updated by shipping the changed entries
+// only. All integers are little-endian:
+//
+//	offset  size  field
+//	0       4     magic "FSTD"
+//	4       2     format version, 1
+//	6       2     reserved, always zero
+//	8       4     number of records
+//	12            records, in ascending key order, each an operation byte,
+//	              the uvarint length and the bytes of the key, and, unless
+//	              the operation is DeltaRemove, the uvarint length and the
+//	              bytes of the value, encoded by a ValueCodec
+//	...     4     CRC-32 (Castagnoli) of everything preceding it
+
+const (
+	deltaMagic      = "FSTD"
+	deltaVersion    = 1
+	deltaHeaderSize = 12
+)
+
+// ErrDeltaConflict is returned when applying a delta that does not match
+// the map, such as one adding a key the map already holds, or removing a
+// key it does not.
+var ErrDeltaConflict = errors.New("faststringmap: delta does not apply to the map")
+
+var errDeltaLayout = errors.New("faststringmap: delta can not be applied to a map not laid out by a build")
+
+// DeltaOp is the operation of an entry of a delta.
+type DeltaOp uint8
+
+const (
+	// DeltaAdd adds a key that is not in the map.
+	DeltaAdd DeltaOp = iota + 1
+	// DeltaRemove removes a key of the map.
+	DeltaRemove
+	// DeltaChange changes the value of a key of the map.
+	DeltaChange
+)
+
+func (op DeltaOp) String() string {
+	switch op {
+	case DeltaAdd:
+		return "add"
+	case DeltaRemove:
+		return "remove"
+	case DeltaChange:
+		return "change"
+	default:
+		return fmt.Sprintf("DeltaOp(%d)", uint8(op))
+	}
+}
+
+// DeltaEntry[T] is an entry of a delta. Value is ignored for DeltaRemove.
+type DeltaEntry[T any] struct {
+	Op    DeltaOp
+	Key   string
+	Value T
+}
+
+// AppendDelta[T] appends the serialized form of a delta holding entries to
+// dst, using codec to encode values, and returns the extended slice. The
+// entries may be in any order; they are written in ascending key order, so
+// the same entries always produce the same output. AppendDelta returns an
+// error wrapping ErrDuplicateKey if a key appears more than once. The
+// entries slice is not modified.
+func AppendDelta[T any](dst []byte, entries []DeltaEntry[T], codec ValueCodec[T]) ([]byte, error) {
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(i, j int) int {
+		return strings.Compare(entries[i].Key, entries[j].Key)
+	})
+
+	start := len(dst)
+	dst = append(dst, deltaMagic...)
+	dst = binary.LittleEndian.AppendUint16(dst, deltaVersion)
+	dst = binary.LittleEndian.AppendUint16(dst, 0)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(entries)))
+	for k, i := range order {
+		e := &entries[i]
+		if k > 0 && entries[order[k-1]].Key == e.Key {
+			return dst[:start], fmt.Errorf("%w %q", ErrDuplicateKey, e.Key)
+		}
+		if e.Op < DeltaAdd || e.Op > DeltaChange {
+			return dst[:start], fmt.Errorf("faststringmap: invalid delta operation %v", e.Op)
+		}
+
+		dst = append(dst, byte(e.Op))
+		dst = binary.AppendUvarint(dst, uint64(len(e.Key)))
+		dst = append(dst, e.Key...)
+		if e.Op == DeltaRemove {
+			continue
+		}
+		var err error
+		if dst, err = appendSizedValue(dst, e.Value, codec); err != nil {
+			return dst[:start], err
+		}
+	}
+	return binary.LittleEndian.AppendUint32(dst, crc32.Checksum(dst[start:], serialChecksumTable)), nil
+}
+
+// appendSizedValue appends the uvarint length and the encoding of v to dst.
+func appendSizedValue[T any](dst []byte, v T, codec ValueCodec[T]) ([]byte, error) {
+	// reserve a byte for the length, which is enough for most values
+	at := len(dst)
+	dst, err := codec.AppendValue(append(dst, 0), v)
+	if err != nil {
+		return dst, err
+	}
+	n := len(dst) - at - 1
+	if n < 0x80 {
+		dst[at] = byte(n)
+		return dst, nil
+	}
+	value := append([]byte(nil), dst[at+1:]...)
+	return append(binary.AppendUvarint(dst[:at], uint64(n)), value...), nil
+}
+
+// ApplyDelta[T] returns a new map holding the entries of base updated by
+// the delta read from r, which was written by AppendDelta, using codec to
+// decode values. Keys of the delta are canonicalized like probes if base
+// was built with WithKeyTransform or WithFold. The new map keeps the
+// options of base: its retained keys, its fingerprints and their salt, and
+// its metadata. Its node store and value indices are the same as those of
+// a map built from its entries.
+//
+// Only the nodes on the paths of the changed keys are rebuilt. Subtrees of
+// base not holding any of them are copied to the new map, relocated as a
+// whole, so applying a delta touching a small fraction of the keys is much
+// cheaper than building the map again. Values of a map loaded lazily are
+// all decoded. ApplyDelta returns an error wrapping ErrDeltaConflict if an
+// entry adds a key base holds, or removes or changes a key it does not.
+// base is not modified.
+func ApplyDelta[T any](base Map[T], r io.Reader, codec ValueCodec[T]) (Map[T], error) {
+	entries, err := readDelta(r, codec)
+	if err != nil {
+		return Map[T]{}, err
+	}
+
+	if base.keyTransform != nil || base.fold {
+		o := buildOptions{keyTransform: base.keyTransform, fold: base.fold}
+		for i := range entries {
+			entries[i].Key = o.canonicalKey(entries[i].Key)
+		}
+		slices.SortStableFunc(entries, func(a, b DeltaEntry[T]) int {
+			return strings.Compare(a.Key, b.Key)
+		})
+	}
+	for i := range entries {
+		if i > 0 && entries[i-1].Key == entries[i].Key {
+			return Map[T]{}, fmt.Errorf("%w %q", ErrDuplicateKey, entries[i].Key)
+		}
+	}
+	return applyDelta(&base, entries)
+}
+
+// readDelta reads a serialized delta from r, returning its entries in
+// ascending key order.
+func readDelta[T any](r io.Reader, codec ValueCodec[T]) ([]DeltaEntry[T], error) {
+	dr := deltaReader{r: bufio.NewReader(r)}
+
+	var header [deltaHeaderSize]byte
+	if err := dr.readFull(header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != deltaMagic {
+		return nil, ErrInvalidEncoding
+	}
+	if binary.LittleEndian.Uint16(header[4:]) != deltaVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	n := binary.LittleEndian.Uint32(header[8:])
+
+	var entries []DeltaEntry[T]
+	var buf bytes.Buffer
+	for i := uint32(0); i < n; i++ {
+		op, err := dr.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		e := DeltaEntry[T]{Op: DeltaOp(op)}
+		if e.Op < DeltaAdd || e.Op > DeltaChange {
+			return nil, ErrInvalidEncoding
+		}
+		if err := dr.readSized(&buf); err != nil {
+			return nil, err
+		}
+		e.Key = buf.String()
+		if len(entries) > 0 && entries[len(entries)-1].Key >= e.Key {
+			return nil, ErrInvalidEncoding
+		}
+		if e.Op != DeltaRemove {
+			if err := dr.readSized(&buf); err != nil {
+				return nil, err
+			}
+			if e.Value, err = codec.DecodeValue(buf.Bytes()); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	sum := dr.crc
+	var trailer [4]byte
+	if err := dr.readFull(trailer[:]); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(trailer[:]) != sum {
+		return nil, ErrInvalidEncoding
+	}
+	return entries, nil
+}
+
+// deltaReader reads a serialized delta, computing the checksum of the bytes
+// read so far.
+type deltaReader struct {
+	r   *bufio.Reader
+	crc uint32
+	err error   // error reading the underlying reader, if any
+	one [1]byte // the byte read by ReadByte
+}
+
+func (dr *deltaReader) ReadByte() (byte, error) {
+	c, err := dr.r.ReadByte()
+	if err != nil {
+		dr.err = deltaReadError(err)
+		return 0, dr.err
+	}
+	dr.one[0] = c
+	dr.crc = crc32.Update(dr.crc, serialChecksumTable, dr.one[:])
+	return c, nil
+}
+
+func (dr *deltaReader) readFull(p []byte) error {
+	if _, err := io.ReadFull(dr.r, p); err != nil {
+		return deltaReadError(err)
+	}
+	dr.crc = crc32.Update(dr.crc, serialChecksumTable, p)
+	return nil
+}
+
+// readSized reads a uvarint length and as many bytes into buf. buf grows
+// as bytes arrive, so a corrupt length does not allocate a huge buffer.
+func (dr *deltaReader) readSized(buf *bytes.Buffer) error {
+	n, err := binary.ReadUvarint(dr)
+	if err != nil {
+		if dr.err != nil {
+			return dr.err
+		}
+		return ErrInvalidEncoding // the length overflows
+	}
+	buf.Reset()
+	if _, err := io.CopyN(buf, dr.r, int64(min(n, 1<<62))); err != nil {
+		return deltaReadError(err)
+	}
+	dr.crc = crc32.Update(dr.crc, serialChecksumTable, buf.Bytes())
+	return nil
+}
+
+// deltaReadError returns the error to report for err, from reading a delta.
+// Truncated deltas are invalid encodings.
+func deltaReadError(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: %w", ErrInvalidEncoding, io.ErrUnexpectedEOF)
+	}
+	return err
+}
+
+// applyDelta returns a new map holding the entries of base updated by
+// entries, which are canonical and in ascending key order.
+func applyDelta[T any](base *Map[T], entries []DeltaEntry[T]) (Map[T], error) {
+	for _, e := range entries {
+		if present := storedIndex(base, e.Key) != 0; present != (e.Op != DeltaAdd) {
+			return Map[T]{}, fmt.Errorf("%w: %v %q", ErrDeltaConflict, e.Op, e.Key)
+		}
+	}
+
+	n := base.lenOrZero()
+	a := deltaApplier[T]{
+		base:   base,
+		store:  make([]mapInternalNode, 1, len(base.store)+len(entries)),
+		values: make([]T, 0, n+len(entries)),
+	}
+	if base.keys != nil {
+		a.keys = make([]string, 0, n+len(entries))
+	}
+	if base.fingerprints != nil {
+		a.fingerprints = make([]byte, 0, n+len(entries))
+	}
+	var root *mapInternalNode
+	if len(base.store) > 0 {
+		root = &base.store[0]
+	}
+	if err := a.rebuild(0, root, entries, 0); err != nil {
+		return Map[T]{}, err
+	}
+
+	m := Map[T]{
+		store:        a.store,
+		values:       a.values,
+		keys:         a.keys,
+		metadata:     base.metadata,
+		keyTransform: base.keyTransform,
+		fold:         base.fold,
+	}
+	m.setFingerprints(a.fingerprints, base.fingerprintSalt)
+	m.maxKeyLen = deltaMaxKeyLen(&m, base, entries)
+	return m, nil
+}
+
+// deltaMaxKeyLen returns the length of the longest key of m, the map
+// resulting from applying entries to base. Unless a longest key of base is
+// removed, it follows from the lengths of keys of base and of entries;
+// otherwise m is walked until a key as long as any other is found.
+func deltaMaxKeyLen[T any](m, base *Map[T], entries []DeltaEntry[T]) int {
+	if base.MaxKeyLen() == noKeyLenLimit {
+		return maxKeyLen(m.store)
+	}
+
+	bound, removed := base.MaxKeyLen(), false
+	for _, e := range entries {
+		switch {
+		case e.Op == DeltaAdd:
+			bound = max(bound, len(e.Key))
+		case e.Op == DeltaRemove && len(e.Key) == base.MaxKeyLen():
+			removed = true
+		}
+	}
+	if !removed {
+		return bound
+	}
+
+	n := 0
+	m.walk(func(key []byte, _ Uint) bool {
+		n = max(n, len(key))
+		return n < bound
+	})
+	return n
+}
+
+// deltaApplier builds the map resulting from applying a delta to base.
+type deltaApplier[T any] struct {
+	base *Map[T]
+
+	store        []mapInternalNode
+	values       []T
+	keys         []string // if base retains keys
+	fingerprints []byte   // if base has fingerprints
+
+	children []deltaChild[T] // children of the nodes being rebuilt, as a stack
+}
+
+// deltaChild is a child of a node being rebuilt: its byte, its node in the
+// base store, if any, and the delta entries of keys continuing it.
+type deltaChild[T any] struct {
+	c       byte
+	base    *mapInternalNode
+	entries []DeltaEntry[T]
+}
+
+// rebuild initializes store[i], the node of the keys of length depth with
+// the prefix of the keys of entries, from node, the same node in the base
+// store, or nil if base has no key with the prefix, and entries. Subtrees
+// of node without entries are copied. rebuild recurses along the keys of
+// entries only, so its depth is bounded by the longest key of the delta.
+func (a *deltaApplier[T]) rebuild(i int, node *mapInternalNode, entries []DeltaEntry[T], depth int) error {
+	if len(entries) == 0 {
+		if node == nil || !valid(node) {
+			return nil // the root of an empty map
+		}
+		return a.copySubtree(i, node)
+	}
+
+	prefix := entries[0].Key[:depth]
+	if len(entries[0].Key) == depth {
+		if e := &entries[0]; e.Op != DeltaRemove {
+			a.appendValue(e.Key, e.Value)
+			a.store[i].valueOffset = Uint(len(a.values))
+		}
+		entries = entries[1:]
+	} else if node != nil && node.valueOffset != 0 {
+		if err := a.copyValues(node.valueOffset, node.valueOffset); err != nil {
+			return err
+		}
+		a.store[i].valueOffset = Uint(len(a.values))
+	}
+
+	// merge the valid next nodes of node with the next bytes of entries,
+	// keeping the children with keys left
+	mark := len(a.children)
+	var next []mapInternalNode
+	if node != nil {
+		next = a.base.store[node.nextLo : node.nextLo+Uint(node.nextLen)]
+	}
+	for k := 0; ; {
+		for k < len(next) && !valid(&next[k]) {
+			k++
+		}
+		var child deltaChild[T]
+		switch {
+		case k < len(next) && (len(entries) == 0 || node.nextOffset+byte(k) <= entries[0].Key[depth]):
+			child.c, child.base = node.nextOffset+byte(k), &next[k]
+			k++
+		case len(entries) > 0:
+			child.c = entries[0].Key[depth]
+		}
+		if child.base == nil && len(entries) == 0 {
+			break
+		}
+
+		hi := 0
+		for hi < len(entries) && entries[hi].Key[depth] == child.c {
+			hi++
+		}
+		child.entries, entries = entries[:hi], entries[hi:]
+		if a.keysUnder(child.base, child.entries) > 0 {
+			a.children = append(a.children, child)
+		}
+	}
+	children := len(a.children) - mark
+	if children == 0 {
+		return nil
+	}
+
+	lo, hi := a.children[mark].c, a.children[len(a.children)-1].c
+	if lo == 0 && hi == 255 {
+		return fmt.Errorf("%w after %q", ErrByteRange, prefix)
+	}
+	nextLo := len(a.store)
+	a.store[i].nextLo, a.store[i].nextLen, a.store[i].nextOffset = Uint(nextLo), hi-lo+1, lo
+	a.store = append(a.store, make([]mapInternalNode, int(hi-lo)+1)...)
+
+	// the stack of children may move while children are rebuilt, so they
+	// are indexed instead of sliced
+	for k := mark; k < mark+children; k++ {
+		child := a.children[k]
+		if err := a.rebuild(nextLo+int(child.c-lo), child.base, child.entries, depth+1); err != nil {
+			return err
+		}
+	}
+	clear(a.children[mark:])
+	a.children = a.children[:mark]
+	return nil
+}
+
+// keysUnder returns the number of keys under node, a node of the base
+// store or nil, after applying entries, which are all under node.
+func (a *deltaApplier[T]) keysUnder(node *mapInternalNode, entries []DeltaEntry[T]) int {
+	n := 0
+	if node != nil {
+		first, last := a.base.subtreeRange(node)
+		n = int(last) - int(first) + 1
+	}
+	for _, e := range entries {
+		switch e.Op {
+		case DeltaAdd:
+			n++
+		case DeltaRemove:
+			n--
+		}
+	}
+	return n
+}
+
+// copySubtree initializes store[i] as a copy of node of the base store, and
+// appends copies of all nodes below it, and of their values, relocated.
+// The nodes below a node built by a Builder are a contiguous range of the
+// store, starting with its next nodes, and its values are a contiguous
+// range of values, so the copies are relocated by a fixed shift each.
+func (a *deltaApplier[T]) copySubtree(i int, node *mapInternalNode) error {
+	first, last := a.base.subtreeRange(node)
+	valueShift := Uint(len(a.values)) + 1 - first
+	if err := a.copyValues(first, last); err != nil {
+		return err
+	}
+
+	copied := *node
+	if copied.valueOffset != 0 {
+		copied.valueOffset += valueShift
+	}
+	if copied.nextLen == 0 {
+		a.store[i] = copied
+		return nil
+	}
+
+	lo, hi := node.nextLo, a.base.subtreeEnd(node)
+	nodeShift := Uint(len(a.store)) - lo
+	copied.nextLo += nodeShift
+	a.store[i] = copied
+	for _, n := range a.base.store[lo:hi] {
+		if n.nextLen != 0 {
+			if n.nextLo < lo || n.nextLo+Uint(n.nextLen) > hi {
+				return errDeltaLayout
+			}
+			n.nextLo += nodeShift
+		}
+		if n.valueOffset != 0 {
+			if n.valueOffset < first || n.valueOffset > last {
+				return errDeltaLayout
+			}
+			n.valueOffset += valueShift
+		}
+		a.store = append(a.store, n)
+	}
+	return nil
+}
+
+// subtreeEnd returns the end of the range of nodes below node, which has
+// next nodes, in the store of a map built by a Builder. Nodes are
+// allocated depth first, so the range ends with the nodes below the last
+// next node that has next nodes itself.
+func (m *Map[T]) subtreeEnd(node *mapInternalNode) Uint {
+	for {
+		k := int(node.nextLen) - 1
+		for k >= 0 && m.store[node.nextLo+Uint(k)].nextLen == 0 {
+			k--
+		}
+		if k < 0 {
+			return node.nextLo + Uint(node.nextLen)
+		}
+		node = &m.store[node.nextLo+Uint(k)]
+	}
+}
+
+// appendValue appends the value of a key of the delta.
+func (a *deltaApplier[T]) appendValue(key string, v T) {
+	a.values = append(a.values, v)
+	if a.keys != nil {
+		a.keys = append(a.keys, key)
+	}
+	if a.fingerprints != nil {
+		a.fingerprints = append(a.fingerprints, fingerprint(key, a.base.fingerprintSeed))
+	}
+}
+
+// copyValues appends the values at indices first to last of base.
+func (a *deltaApplier[T]) copyValues(first, last Uint) error {
+	base := a.base
+	if base.values != nil {
+		a.values = append(a.values, base.values[first-1:last]...)
+	} else {
+		for index := first; index <= last; index++ {
+			v, ok := base.AtIndex(index)
+			if !ok {
+				return fmt.Errorf("faststringmap: decoding value %d: %w", index, ErrInvalidEncoding)
+			}
+			a.values = append(a.values, v)
+		}
+	}
+	if a.keys != nil {
+		a.keys = append(a.keys, base.keys[first-1:last]...)
+	}
+	if a.fingerprints != nil {
+		a.fingerprints = append(a.fingerprints, base.fingerprints[first-1:last]...)
+	}
+	return nil
+}
+
+// storedIndex returns the index of the value of key, as stored in the map,
+// or 0 if there is none. Unlike IndexString, it does not canonicalize key.
+func storedIndex[T any, S string | []byte](m *Map[T], key S) Uint {
+	if m == nil || len(m.store) == 0 {
+		return 0
+	}
+	node := descend(m, &m.store[0], key)
+	if node == nil {
+		return 0
+	}
+	return node.valueOffset
+}
+
+// valid reports whether node is reached by a byte continuing a key.
+func valid(node *mapInternalNode) bool {
+	return node.valueOffset != 0 || node.nextLen != 0
+}
@@ -0,0 +1,248 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+// randomDelta returns a delta changing about a fraction of the keys of
+// entries, and the entries resulting from applying it.
+func randomDelta(entries []faststringmap.MapEntry[uint32], fraction float64) ([]faststringmap.DeltaEntry[uint32], []faststringmap.MapEntry[uint32]) {
+	m := map[string]uint32{}
+	for _, e := range entries {
+		m[e.Key] = e.Value
+	}
+
+	var delta []faststringmap.DeltaEntry[uint32]
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		switch r := rand.Float64(); {
+		case r < fraction/3:
+			delta = append(delta, faststringmap.DeltaEntry[uint32]{Op: faststringmap.DeltaRemove, Key: k})
+			delete(m, k)
+		case r < 2*fraction/3:
+			v := rand.Uint32()
+			delta = append(delta, faststringmap.DeltaEntry[uint32]{Op: faststringmap.DeltaChange, Key: k, Value: v})
+			m[k] = v
+		}
+	}
+	for added := 0; added < int(fraction/3*float64(len(entries)))+1; {
+		k := randomSmallString(10)
+		if _, ok := m[k]; ok || slices.ContainsFunc(delta, func(e faststringmap.DeltaEntry[uint32]) bool { return e.Key == k }) {
+			continue
+		}
+		v := rand.Uint32()
+		delta = append(delta, faststringmap.DeltaEntry[uint32]{Op: faststringmap.DeltaAdd, Key: k, Value: v})
+		m[k] = v
+		added++
+	}
+
+	updated := make([]faststringmap.MapEntry[uint32], 0, len(m))
+	for k, v := range m {
+		updated = append(updated, faststringmap.MapEntry[uint32]{Key: k, Value: v})
+	}
+	return delta, updated
+}
+
+func applyDelta(t *testing.T, base faststringmap.Map[uint32], delta []faststringmap.DeltaEntry[uint32]) (faststringmap.Map[uint32], error) {
+	t.Helper()
+	codec := faststringmap.IntCodec[uint32]{}
+	data, err := faststringmap.AppendDelta(nil, delta, codec)
+	if err != nil {
+		t.Fatalf("AppendDelta() error = %v", err)
+	}
+	return faststringmap.ApplyDelta(base, bytes.NewReader(data), codec)
+}
+
+func TestApplyDelta(t *testing.T) {
+	for _, n := range []int{1, 10, 100, 5000} {
+		for _, fraction := range []float64{0.01, 0.3, 1} {
+			entries := randomSmallStrings(n, 10)
+			base := faststringmap.NewMap(entries)
+			delta, updated := randomDelta(entries, fraction)
+
+			got, err := applyDelta(t, base, delta)
+			if err != nil {
+				t.Fatalf("n=%d fraction=%v: ApplyDelta() error = %v", n, fraction, err)
+			}
+			// the node store and the value indices are those of a build
+			want := faststringmap.NewMap(updated)
+			if !bytes.Equal(serialize(t, got), serialize(t, want)) {
+				t.Errorf("n=%d fraction=%v: ApplyDelta() differs from building the updated entries", n, fraction)
+			}
+			checkEntries(t, &got, updated)
+			if got.MaxKeyLen() != want.MaxKeyLen() {
+				t.Errorf("n=%d fraction=%v: MaxKeyLen() = %d want %d", n, fraction, got.MaxKeyLen(), want.MaxKeyLen())
+			}
+		}
+	}
+}
+
+func TestApplyDeltaEmpty(t *testing.T) {
+	var empty faststringmap.Map[uint32]
+	added := []faststringmap.DeltaEntry[uint32]{{Op: faststringmap.DeltaAdd, Key: "a", Value: 1}, {Op: faststringmap.DeltaAdd, Key: "ab", Value: 2}}
+	m, err := applyDelta(t, empty, added)
+	if err != nil {
+		t.Fatalf("ApplyDelta(empty) error = %v", err)
+	}
+	checkEntries(t, &m, []faststringmap.MapEntry[uint32]{{Key: "a", Value: 1}, {Key: "ab", Value: 2}})
+
+	removed := []faststringmap.DeltaEntry[uint32]{{Op: faststringmap.DeltaRemove, Key: "a"}, {Op: faststringmap.DeltaRemove, Key: "ab"}}
+	m, err = applyDelta(t, m, removed)
+	if err != nil {
+		t.Fatalf("ApplyDelta(remove all) error = %v", err)
+	}
+	if !bytes.Equal(serialize(t, m), serialize(t, faststringmap.NewMap[uint32](nil))) {
+		t.Errorf("ApplyDelta(remove all) differs from an empty map")
+	}
+
+	m, err = applyDelta(t, m, nil)
+	if err != nil {
+		t.Fatalf("ApplyDelta(no entries) error = %v", err)
+	}
+	if _, ok := m.LookupString(""); ok {
+		t.Errorf("LookupString(\"\") found in an empty map")
+	}
+}
+
+func TestApplyDeltaOptions(t *testing.T) {
+	entries := randomSmallStrings(1000, 8)
+	opts := []faststringmap.Option{
+		faststringmap.WithRetainKeys(),
+		faststringmap.WithFingerprints(),
+		faststringmap.WithFingerprintSalt(42),
+		faststringmap.WithMetadata(map[string]string{"version": "1"}),
+	}
+	base, err := faststringmap.New(entries, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delta, updated := randomDelta(entries, 0.05)
+
+	got, err := applyDelta(t, base, delta)
+	if err != nil {
+		t.Fatalf("ApplyDelta() error = %v", err)
+	}
+	want, err := faststringmap.New(updated, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(serialize(t, got), serialize(t, want)) {
+		t.Errorf("ApplyDelta() differs from building the updated entries")
+	}
+	for k := range want.Keys() {
+		if key, _, ok := got.LookupKey(k); !ok || key != k {
+			t.Errorf("LookupKey(%q) = %q, %v", k, key, ok)
+		}
+	}
+}
+
+func TestApplyDeltaFold(t *testing.T) {
+	base, err := faststringmap.New([]faststringmap.MapEntry[uint32]{{Key: "Apple", Value: 1}, {Key: "banana", Value: 2}}, faststringmap.WithFold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := applyDelta(t, base, []faststringmap.DeltaEntry[uint32]{
+		{Op: faststringmap.DeltaChange, Key: "APPLE", Value: 3},
+		{Op: faststringmap.DeltaAdd, Key: "Cherry", Value: 4},
+	})
+	if err != nil {
+		t.Fatalf("ApplyDelta() error = %v", err)
+	}
+	for k, want := range map[string]uint32{"apple": 3, "BANANA": 2, "cherry": 4} {
+		if v, ok := m.LookupString(k); !ok || v != want {
+			t.Errorf("LookupString(%q) = %v, %v want %v", k, v, ok, want)
+		}
+	}
+}
+
+func TestApplyDeltaConflicts(t *testing.T) {
+	base := faststringmap.NewMap(goldenEntries)
+	for _, e := range []faststringmap.DeltaEntry[uint32]{
+		{Op: faststringmap.DeltaAdd, Key: goldenEntries[0].Key},
+		{Op: faststringmap.DeltaRemove, Key: "missing"},
+		{Op: faststringmap.DeltaChange, Key: "missing"},
+	} {
+		if _, err := applyDelta(t, base, []faststringmap.DeltaEntry[uint32]{e}); !errors.Is(err, faststringmap.ErrDeltaConflict) {
+			t.Errorf("ApplyDelta(%v %q) error = %v want ErrDeltaConflict", e.Op, e.Key, err)
+		}
+	}
+
+	duplicated := []faststringmap.DeltaEntry[uint32]{{Op: faststringmap.DeltaAdd, Key: "x"}, {Op: faststringmap.DeltaRemove, Key: "x"}}
+	if _, err := faststringmap.AppendDelta(nil, duplicated, faststringmap.IntCodec[uint32]{}); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("AppendDelta(duplicate keys) error = %v want ErrDuplicateKey", err)
+	}
+}
+
+func TestApplyDeltaCorrupt(t *testing.T) {
+	codec := faststringmap.IntCodec[uint32]{}
+	base := faststringmap.NewMap(goldenEntries)
+	data, err := faststringmap.AppendDelta(nil, []faststringmap.DeltaEntry[uint32]{
+		{Op: faststringmap.DeltaAdd, Key: "new", Value: 1},
+		{Op: faststringmap.DeltaRemove, Key: goldenEntries[0].Key},
+	}, codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range data {
+		corrupt := slices.Clone(data)
+		corrupt[i] ^= 0x40
+		if _, err := faststringmap.ApplyDelta(base, bytes.NewReader(corrupt), codec); err == nil {
+			t.Errorf("ApplyDelta(byte %d flipped) succeeded", i)
+		}
+	}
+	for n := range data {
+		if _, err := faststringmap.ApplyDelta(base, bytes.NewReader(data[:n]), codec); !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+			t.Errorf("ApplyDelta(truncated to %d bytes) error = %v want ErrInvalidEncoding", n, err)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	entries := randomSmallStrings(2000, 8)
+	old := faststringmap.NewMap(entries)
+	delta, updated := randomDelta(entries, 0.1)
+	new := faststringmap.NewMap(updated)
+
+	diff := faststringmap.Diff(&old, &new)
+	if len(diff) != len(delta) {
+		t.Errorf("len(Diff()) = %d want %d", len(diff), len(delta))
+	}
+	got, err := applyDelta(t, old, diff)
+	if err != nil {
+		t.Fatalf("ApplyDelta(Diff()) error = %v", err)
+	}
+	if !faststringmap.Equal(&got, &new) {
+		t.Errorf("ApplyDelta(Diff(old, new)) differs from new")
+	}
+}
+
+func BenchmarkApplyDelta(b *testing.B) {
+	entries := randomSmallStrings(200_000, 12)
+	base := faststringmap.NewMap(entries)
+	delta, updated := randomDelta(entries, 0.01)
+	codec := faststringmap.IntCodec[uint32]{}
+	data, err := faststringmap.AppendDelta(nil, delta, codec)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("ApplyDelta", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := faststringmap.ApplyDelta(base, bytes.NewReader(data), codec); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Rebuild", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			faststringmap.NewMap(updated)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+// Package dispatch uses faststringmap for dispatch on the hot path of
+// servers: from method names or request paths to handlers, and from header
+// values to enums.
+//
+// Adapters for gRPC servers are in the separate module
+// alon.kr/x/faststringmap/dispatch/grpcdispatch, so that this package does
+// not depend on gRPC.
+package dispatch
+
+import (
+	"alon.kr/x/faststringmap"
+)
+
+// Table[H] maps names, such as method names, to handlers of type H. It is
+// read only, and safe for concurrent use.
+type Table[H any] struct {
+	index    faststringmap.Map[int]
+	names    []string
+	handlers []H
+}
+
+// NewTable[H] constructs a Table holding the supplied handlers.
+func NewTable[H any](handlers map[string]H) *Table[H] {
+	t := &Table[H]{
+		names:    make([]string, 0, len(handlers)),
+		handlers: make([]H, 0, len(handlers)),
+	}
+	entries := make([]faststringmap.MapEntry[int], 0, len(handlers))
+	for name, h := range handlers {
+		entries = append(entries, faststringmap.MapEntry[int]{Key: name, Value: len(t.handlers)})
+		t.names = append(t.names, name)
+		t.handlers = append(t.handlers, h)
+	}
+	t.index = faststringmap.NewMap(entries)
+	return t
+}
+
+// Index returns the index of the handler for name, which is stable for the
+// lifetime of the table, so it can be used to index per-handler state such
+// as metrics. ok is false if there is no handler for name.
+func (t *Table[H]) Index(name string) (i int, ok bool) {
+	return t.index.LookupString(name)
+}
+
+// IndexBytes returns the index of the handler for name like Index.
+func (t *Table[H]) IndexBytes(name []byte) (i int, ok bool) {
+	return t.index.LookupBytes(name)
+}
+
+// Lookup returns the handler for name.
+func (t *Table[H]) Lookup(name string) (h H, ok bool) {
+	i, ok := t.index.LookupString(name)
+	if !ok {
+		return h, false
+	}
+	return t.handlers[i], true
+}
+
+// LookupBytes returns the handler for name like Lookup.
+func (t *Table[H]) LookupBytes(name []byte) (h H, ok bool) {
+	i, ok := t.index.LookupBytes(name)
+	if !ok {
+		return h, false
+	}
+	return t.handlers[i], true
+}
+
+// Len returns the number of handlers in the table.
+func (t *Table[H]) Len() int {
+	return len(t.handlers)
+}
+
+// At returns the name and handler at index i, for 0 <= i < Len().
+func (t *Table[H]) At(i int) (name string, h H) {
+	return t.names[i], t.handlers[i]
+}
@@ -0,0 +1,31 @@
+package dispatch_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap/dispatch"
+)
+
+func TestTable(t *testing.T) {
+	table := dispatch.NewTable(map[string]func() string{
+		"/pkg.Service/Get": func() string { return "get" },
+		"/pkg.Service/Put": func() string { return "put" },
+	})
+
+	if table.Len() != 2 {
+		t.Errorf("Len() = %d want 2", table.Len())
+	}
+	for _, name := range []string{"/pkg.Service/Get", "/pkg.Service/Put"} {
+		h, ok := table.Lookup(name)
+		if !ok {
+			t.Fatalf("Lookup(%s) not present", name)
+		}
+		i, ok := table.IndexBytes([]byte(name))
+		if gotName, gotHandler := table.At(i); !ok || gotName != name || gotHandler() != h() {
+			t.Errorf("At(IndexBytes(%s)) = %s, %s", name, gotName, gotHandler())
+		}
+	}
+	if _, ok := table.LookupBytes([]byte("/pkg.Service/Delete")); ok {
+		t.Errorf("LookupBytes() of unknown method reported present")
+	}
+}
@@ -0,0 +1,79 @@
+// Package grpcdispatch adapts the dispatch package to gRPC servers.
+//
+// It is a separate module, so that only programs using it depend on gRPC.
+package grpcdispatch
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"alon.kr/x/faststringmap"
+	"alon.kr/x/faststringmap/dispatch"
+)
+
+// methodValueKey is the context key of the value of the called method.
+type methodValueKey struct{}
+
+// UnaryServerInterceptor[V] returns an interceptor that looks up the full
+// name of the called method, such as "/pkg.Service/Method", in values, and
+// makes the resulting value available to handlers through MethodValue. This
+// allows per-method settings, such as authorization policies or rate limit
+// classes, to be resolved once per call. Calls of methods not present in
+// values are passed on without a value.
+func UnaryServerInterceptor[V any](values *faststringmap.Map[V]) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if v, ok := values.LookupString(info.FullMethod); ok {
+			ctx = context.WithValue(ctx, methodValueKey{}, v)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor[V] returns a stream interceptor that looks up the
+// called method like UnaryServerInterceptor.
+func StreamServerInterceptor[V any](values *faststringmap.Map[V]) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if v, ok := values.LookupString(info.FullMethod); ok {
+			ss = &valueStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), methodValueKey{}, v)}
+		}
+		return handler(srv, ss)
+	}
+}
+
+// MethodValue[V] returns the value of the called method stored in ctx by
+// UnaryServerInterceptor or StreamServerInterceptor.
+func MethodValue[V any](ctx context.Context) (v V, ok bool) {
+	v, ok = ctx.Value(methodValueKey{}).(V)
+	return v, ok
+}
+
+type valueStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *valueStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnknownServiceHandler returns a stream handler dispatching calls by their
+// full method name to the handlers in table, for use with
+// grpc.UnknownServiceHandler, which lets a server handle methods without
+// registering generated service descriptions, as proxies and gateways do.
+// Calls of methods not in table fail with codes.Unimplemented.
+func UnknownServiceHandler(table *dispatch.Table[grpc.StreamHandler]) grpc.StreamHandler {
+	return func(srv any, stream grpc.ServerStream) error {
+		method, ok := grpc.MethodFromServerStream(stream)
+		if !ok {
+			return status.Error(codes.Internal, "grpcdispatch: no method in stream context")
+		}
+		h, ok := table.Lookup(method)
+		if !ok {
+			return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+		}
+		return h(srv, stream)
+	}
+}
@@ -0,0 +1,94 @@
+package grpcdispatch_test
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"alon.kr/x/faststringmap"
+	"alon.kr/x/faststringmap/dispatch"
+	"alon.kr/x/faststringmap/dispatch/grpcdispatch"
+)
+
+type policy int
+
+const (
+	public policy = iota + 1
+	admin
+)
+
+var policies = faststringmap.NewMap([]faststringmap.MapEntry[policy]{
+	{Key: "/pkg.Service/Get", Value: public},
+	{Key: "/pkg.Service/Delete", Value: admin},
+})
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	intercept := grpcdispatch.UnaryServerInterceptor(&policies)
+	for method, want := range map[string]policy{"/pkg.Service/Get": public, "/pkg.Service/Delete": admin, "/pkg.Service/Put": 0} {
+		got, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) {
+				p, _ := grpcdispatch.MethodValue[policy](ctx)
+				return p, nil
+			})
+		if err != nil || got != want {
+			t.Errorf("%s: policy = %v, %v want %v", method, got, err, want)
+		}
+	}
+}
+
+// stream is a server stream for the method in its context.
+type stream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *stream) Context() context.Context { return s.ctx }
+
+// transportStream provides the method name of a stream.
+type transportStream struct {
+	grpc.ServerTransportStream
+	method string
+}
+
+func (s *transportStream) Method() string { return s.method }
+
+func newStream(method string) *stream {
+	return &stream{ctx: grpc.NewContextWithServerTransportStream(context.Background(), &transportStream{method: method})}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	intercept := grpcdispatch.StreamServerInterceptor(&policies)
+	var got policy
+	err := intercept(nil, newStream("/pkg.Service/Delete"), &grpc.StreamServerInfo{FullMethod: "/pkg.Service/Delete"},
+		func(srv any, ss grpc.ServerStream) error {
+			got, _ = grpcdispatch.MethodValue[policy](ss.Context())
+			return nil
+		})
+	if err != nil || got != admin {
+		t.Errorf("policy = %v, %v want %v", got, err, admin)
+	}
+}
+
+func TestUnknownServiceHandler(t *testing.T) {
+	var called string
+	handler := func(name string) grpc.StreamHandler {
+		return func(srv any, ss grpc.ServerStream) error {
+			called = name
+			return nil
+		}
+	}
+	h := grpcdispatch.UnknownServiceHandler(dispatch.NewTable(map[string]grpc.StreamHandler{
+		"/pkg.Service/Get": handler("get"),
+		"/pkg.Service/Put": handler("put"),
+	}))
+
+	if err := h(nil, newStream("/pkg.Service/Put")); err != nil || called != "put" {
+		t.Errorf("Put called %q, %v want put", called, err)
+	}
+	if err := h(nil, newStream("/pkg.Service/Delete")); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Delete error = %v want Unimplemented", err)
+	}
+}
@@ -0,0 +1,72 @@
+package dispatch
+
+import (
+	"context"
+	"net/http"
+
+	"alon.kr/x/faststringmap"
+)
+
+// PathMux is an http.Handler dispatching requests to handlers by their exact
+// URL path. Unlike http.ServeMux, it does not match path prefixes or
+// patterns, which makes it suited to RPC style APIs with many fixed paths.
+type PathMux struct {
+	table    *Table[http.Handler]
+	fallback http.Handler
+}
+
+// NewPathMux constructs a PathMux serving requests for the paths in routes
+// with their handlers, and all other requests with fallback. A nil fallback
+// responds with 404 Not Found.
+func NewPathMux(routes map[string]http.Handler, fallback http.Handler) *PathMux {
+	if fallback == nil {
+		fallback = http.NotFoundHandler()
+	}
+	return &PathMux{table: NewTable(routes), fallback: fallback}
+}
+
+// ServeHTTP implements http.Handler.
+func (mux *PathMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h, ok := mux.table.Lookup(r.URL.Path); ok {
+		h.ServeHTTP(w, r)
+		return
+	}
+	mux.fallback.ServeHTTP(w, r)
+}
+
+// headerEnumKey is the context key of the enum value of a header.
+type headerEnumKey struct {
+	header string
+}
+
+// HeaderEnum[E] returns middleware that looks up the value of the named
+// request header in values, and makes the resulting enum value available to
+// next through HeaderEnumFrom. Requests whose header value is not present in
+// values are passed on without an enum value.
+func HeaderEnum[E any](header string, values *faststringmap.Map[E], next http.Handler) http.Handler {
+	header = http.CanonicalHeaderKey(header)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if e, ok := LookupHeader(r, header, values); ok {
+			r = r.WithContext(context.WithValue(r.Context(), headerEnumKey{header}, e))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// HeaderEnumFrom[E] returns the enum value of the named header stored in ctx
+// by HeaderEnum.
+func HeaderEnumFrom[E any](ctx context.Context, header string) (e E, ok bool) {
+	e, ok = ctx.Value(headerEnumKey{http.CanonicalHeaderKey(header)}).(E)
+	return e, ok
+}
+
+// LookupHeader[E] looks up the first value of the named request header in
+// values. It does not allocate if header is in canonical form, as returned by
+// http.CanonicalHeaderKey.
+func LookupHeader[E any](r *http.Request, header string, values *faststringmap.Map[E]) (e E, ok bool) {
+	v := r.Header.Values(header)
+	if len(v) == 0 {
+		return e, false
+	}
+	return values.LookupString(v[0])
+}
@@ -0,0 +1,72 @@
+package dispatch_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+	"alon.kr/x/faststringmap/dispatch"
+)
+
+func respond(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	})
+}
+
+func TestPathMux(t *testing.T) {
+	mux := dispatch.NewPathMux(map[string]http.Handler{
+		"/v1/get": respond("get"),
+		"/v1/put": respond("put"),
+	}, nil)
+
+	for path, want := range map[string]string{
+		"/v1/get":  "get",
+		"/v1/put":  "put",
+		"/v1/get/": "404 page not found\n",
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if got := w.Body.String(); got != want {
+			t.Errorf("GET %s = %q want %q", path, got, want)
+		}
+	}
+}
+
+type encoding int
+
+const (
+	identity encoding = iota + 1
+	gzip
+)
+
+func TestHeaderEnum(t *testing.T) {
+	encodings := faststringmap.NewMap([]faststringmap.MapEntry[encoding]{{Key: "identity", Value: identity}, {Key: "gzip", Value: gzip}})
+
+	var got encoding
+	var gotOK bool
+	h := dispatch.HeaderEnum("content-encoding", &encodings, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, gotOK = dispatch.HeaderEnumFrom[encoding](r.Context(), "Content-Encoding")
+	}))
+
+	for value, want := range map[string]encoding{"gzip": gzip, "identity": identity, "br": 0, "": 0} {
+		r := httptest.NewRequest("POST", "/", nil)
+		if value != "" {
+			r.Header.Set("Content-Encoding", value)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		if got != want || gotOK != (want != 0) {
+			t.Errorf("Content-Encoding: %s = %v, %v want %v", value, got, gotOK, want)
+		}
+	}
+
+	r := httptest.NewRequest("POST", "/", nil)
+	r.Header.Set("Content-Encoding", "gzip")
+	if allocs := testing.AllocsPerRun(100, func() {
+		dispatch.LookupHeader(r, "Content-Encoding", &encodings)
+	}); allocs != 0 {
+		t.Errorf("LookupHeader() allocates %v times per run want 0", allocs)
+	}
+}
@@ -0,0 +1,114 @@
+package faststringmap
+
+import (
+	"fmt"
+)
+
+// UnmarshalFunc decodes a document into the value pointed to by v, like
+// json.Unmarshal, and the Unmarshal functions of the common YAML and TOML
+// packages.
+type UnmarshalFunc func(data []byte, v any) error
+
+// EntriesFromDocument[T] decodes map entries from a configuration document
+// using unmarshal, which lets dictionaries be kept in YAML, TOML or JSON
+// files without this package depending on a parser for each of them. The
+// document is either a mapping from keys to scalar values:
+//
+//	GET: 1
+//	POST: 3
+//
+// or a list of mappings with a key and a value field, which allows keys that
+// the document format can not express as mapping keys. The list is either the
+// whole document, or the value of its only field, "entries", as TOML
+// documents can not be lists:
+//
+//	entries:
+//	  - key: GET
+//	    value: 1
+//
+// Each value is converted to T using convert, which receives it as decoded
+// by unmarshal, typically as a string, bool, int, int64, uint64 or float64.
+func EntriesFromDocument[T any](data []byte, unmarshal UnmarshalFunc, convert func(any) (T, error)) ([]MapEntry[T], error) {
+	var doc any
+	if err := unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("faststringmap: decoding document: %w", err)
+	}
+
+	fields, isMapping := documentMapping(doc)
+	if list, ok := fields["entries"].([]any); isMapping && len(fields) == 1 && ok {
+		doc, isMapping = list, false
+	}
+
+	var entries []MapEntry[T]
+	add := func(key, value any) error {
+		k, ok := key.(string)
+		if !ok {
+			return fmt.Errorf("faststringmap: document key %v is not a string", key)
+		}
+		v, err := convert(value)
+		if err != nil {
+			return fmt.Errorf("faststringmap: document value of %q: %w", k, err)
+		}
+		entries = append(entries, MapEntry[T]{k, v})
+		return nil
+	}
+
+	if isMapping {
+		for k, v := range fields {
+			if err := add(k, v); err != nil {
+				return nil, err
+			}
+		}
+		return entries, nil
+	}
+
+	list, ok := doc.([]any)
+	if !ok {
+		return nil, fmt.Errorf("faststringmap: document is neither a mapping nor a list")
+	}
+	seen := make(map[string]bool, len(list))
+	for i, item := range list {
+		entry, ok := documentMapping(item)
+		key, hasKey := entry["key"]
+		if !ok || !hasKey {
+			return nil, fmt.Errorf("faststringmap: document entry %d has no key", i)
+		}
+		if err := add(key, entry["value"]); err != nil {
+			return nil, err
+		}
+		k := entries[len(entries)-1].Key
+		if seen[k] {
+			return nil, fmt.Errorf("faststringmap: duplicate key %q", k)
+		}
+		seen[k] = true
+	}
+	return entries, nil
+}
+
+// NewMapFromDocument[T] constructs a Map from the entries of a configuration
+// document, as decoded by EntriesFromDocument. Errors from building the map,
+// such as ErrByteRange, are returned like decoding errors.
+func NewMapFromDocument[T any](data []byte, unmarshal UnmarshalFunc, convert func(any) (T, error)) (Map[T], error) {
+	entries, err := EntriesFromDocument(data, unmarshal, convert)
+	if err != nil {
+		return Map[T]{}, err
+	}
+	return New(entries)
+}
+
+// documentMapping returns v as a mapping, if it is one. Most packages decode
+// mappings as map[string]any, but some YAML packages decode them as
+// map[any]any, so both are accepted.
+func documentMapping(v any) (map[any]any, bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[any]any, len(v))
+		for k, e := range v {
+			m[k] = e
+		}
+		return m, true
+	case map[any]any:
+		return v, true
+	}
+	return nil, false
+}
@@ -0,0 +1,83 @@
+package faststringmap_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+// toUint32 converts numbers decoded by encoding/json.
+func toUint32(v any) (uint32, error) {
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("%v is not a number", v)
+	}
+	return uint32(f), nil
+}
+
+func TestNewMapFromDocument(t *testing.T) {
+	for name, doc := range map[string]string{
+		"mapping": `{"": 0, "GET": 1, "HEAD": 2, "POST": 3, "PUT": 4, "PATCH": 5, "DELETE": 6, "OPTIONS": 7, "ß": 300}`,
+		"list": `[{"key": "", "value": 0}, {"key": "GET", "value": 1}, {"key": "HEAD", "value": 2},
+			{"key": "POST", "value": 3}, {"key": "PUT", "value": 4}, {"key": "PATCH", "value": 5},
+			{"key": "DELETE", "value": 6}, {"key": "OPTIONS", "value": 7}, {"key": "ß", "value": 300}]`,
+		"entries": `{"entries": [{"key": "", "value": 0}, {"key": "GET", "value": 1}, {"key": "HEAD", "value": 2},
+			{"key": "POST", "value": 3}, {"key": "PUT", "value": 4}, {"key": "PATCH", "value": 5},
+			{"key": "DELETE", "value": 6}, {"key": "OPTIONS", "value": 7}, {"key": "ß", "value": 300}]}`,
+	} {
+		m, err := faststringmap.NewMapFromDocument([]byte(doc), json.Unmarshal, toUint32)
+		if err != nil {
+			t.Fatalf("%s: NewMapFromDocument() error = %v", name, err)
+		}
+		checkEntries(t, &m, goldenEntries)
+	}
+}
+
+func TestEntriesFromDocumentAnyKeys(t *testing.T) {
+	// some YAML packages decode mappings with interface keys
+	unmarshal := func(data []byte, v any) error {
+		*v.(*any) = map[any]any{"GET": 1, 2: 3}
+		return nil
+	}
+	toInt := func(v any) (int, error) { return v.(int), nil }
+	if _, err := faststringmap.EntriesFromDocument(nil, unmarshal, toInt); err == nil {
+		t.Errorf("EntriesFromDocument() with a non-string key succeeded")
+	}
+
+	unmarshal = func(data []byte, v any) error {
+		*v.(*any) = map[any]any{"GET": 1, "PUT": 4}
+		return nil
+	}
+	entries, err := faststringmap.EntriesFromDocument(nil, unmarshal, toInt)
+	if err != nil || len(entries) != 2 {
+		t.Errorf("EntriesFromDocument() = %v, %v want 2 entries", entries, err)
+	}
+}
+
+func TestNewMapFromDocumentByteRange(t *testing.T) {
+	unmarshal := func(data []byte, v any) error {
+		*v.(*any) = map[string]any{"k\x00": 1, "k\xff": 2}
+		return nil
+	}
+	toInt := func(v any) (int, error) { return v.(int), nil }
+	if _, err := faststringmap.NewMapFromDocument(nil, unmarshal, toInt); !errors.Is(err, faststringmap.ErrByteRange) {
+		t.Errorf("NewMapFromDocument() of keys using all byte values error = %v want ErrByteRange", err)
+	}
+}
+
+func TestEntriesFromDocumentErrors(t *testing.T) {
+	for _, doc := range []string{
+		`not a document`,
+		`"a scalar"`,
+		`{"GET": "one"}`,
+		`[{"value": 1}]`,
+		`[{"key": "GET", "value": 1}, {"key": "GET", "value": 2}]`,
+	} {
+		if _, err := faststringmap.EntriesFromDocument([]byte(doc), json.Unmarshal, toUint32); err == nil {
+			t.Errorf("EntriesFromDocument(%s) succeeded", doc)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package faststringmap
+
+import (
+	"slices"
+	"strings"
+)
+
+// DuplicateReport lists the keys of the entries of a build that are equal,
+// or nearly equal, to each other, which usually means the data they came
+// from is dirty: a key with both "Apple" and "apple" or "apple " is rarely
+// intended, and lookups then miss depending on how the probe is spelled.
+type DuplicateReport struct {
+	Exact []DuplicateGroup // keys of more than one entry
+	Case  []DuplicateGroup // distinct keys equal up to Unicode letter case
+	Space []DuplicateGroup // distinct keys equal up to leading and trailing white space
+}
+
+// DuplicateGroup is a set of keys found equal to each other by a
+// DuplicateReport, in ascending order of the groups' first keys.
+type DuplicateGroup struct {
+	Keys    []string // the distinct keys of the group, in ascending order
+	Entries []int    // indices of the entries with any of the keys, in ascending order
+}
+
+// WithDuplicateAnalysis makes builds look for duplicate and near-duplicate
+// keys, and list them in BuildReport.Duplicates. It costs a pass over the
+// keys, and maps holding all of them, so it is meant for checking input
+// rather than for every build. Reports are read from Builder.Report; use
+// AnalyzeDuplicates for the entries of New. See Builder.SetDuplicateAnalysis.
+func WithDuplicateAnalysis() Option {
+	return func(o *buildOptions) { o.analyzeDuplicates = true }
+}
+
+// SetDuplicateAnalysis sets whether builds look for duplicate and
+// near-duplicate keys, and list them in BuildReport.Duplicates. The report
+// is made before duplicates are rejected or removed, so it is available
+// from Report after a build fails with ErrDuplicateKey. Keys are analyzed
+// as they are stored, after any key transform or case folding.
+func (b *Builder[T]) SetDuplicateAnalysis(enabled bool) {
+	b.analyzeDuplicates = enabled
+}
+
+// AnalyzeDuplicates returns the duplicate and near-duplicate keys among
+// keys, with Entries holding indices in keys. It does not modify keys.
+func AnalyzeDuplicates(keys []string) DuplicateReport {
+	var b Builder[struct{}]
+	for _, k := range keys {
+		b.Add(k, struct{}{})
+	}
+	b.sortEntries()
+	return b.duplicateReport()
+}
+
+// duplicateReport analyzes the keys of the entries in b.order, which must
+// be sorted.
+func (b *Builder[T]) duplicateReport() DuplicateReport {
+	var r DuplicateReport
+
+	// distinct keys in ascending order, with the entries of each
+	type distinctKey struct {
+		key     string
+		entries []int
+	}
+	var distinct []distinctKey
+	for _, i := range b.order {
+		key := b.key(i)
+		if len(distinct) > 0 && distinct[len(distinct)-1].key == key {
+			d := &distinct[len(distinct)-1]
+			d.entries = append(d.entries, int(i))
+			continue
+		}
+		distinct = append(distinct, distinctKey{key, []int{int(i)}})
+	}
+
+	for _, d := range distinct {
+		if len(d.entries) > 1 {
+			slices.Sort(d.entries)
+			r.Exact = append(r.Exact, DuplicateGroup{Keys: []string{d.key}, Entries: d.entries})
+		}
+	}
+
+	// groups distinct keys with equal normal forms, in order of first key
+	group := func(normalize func(string) string) []DuplicateGroup {
+		var groups []DuplicateGroup
+		byNorm := map[string]int{} // index in groups+1, or 0 for a single key so far
+		first := map[string]int{}  // index in distinct of the first key with a normal form
+		for di, d := range distinct {
+			norm := normalize(d.key)
+			fi, seen := first[norm]
+			if !seen {
+				first[norm] = di
+				continue
+			}
+			gi := byNorm[norm]
+			if gi == 0 {
+				f := distinct[fi]
+				groups = append(groups, DuplicateGroup{Keys: []string{f.key}, Entries: slices.Clone(f.entries)})
+				gi = len(groups)
+				byNorm[norm] = gi
+			}
+			g := &groups[gi-1]
+			g.Keys = append(g.Keys, d.key)
+			g.Entries = append(g.Entries, d.entries...)
+		}
+		for i := range groups {
+			slices.Sort(groups[i].Entries)
+		}
+		slices.SortFunc(groups, func(a, b DuplicateGroup) int { return strings.Compare(a.Keys[0], b.Keys[0]) })
+		return groups
+	}
+	r.Case = group(strings.ToLower)
+	r.Space = group(strings.TrimSpace)
+	return r
+}
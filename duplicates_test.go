@@ -0,0 +1,66 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestAnalyzeDuplicates(t *testing.T) {
+	keys := []string{"apple", "Apple", "pear", "apple ", "pear", "APPLE", "plum", " plum\t", "pear"}
+	got := faststringmap.AnalyzeDuplicates(keys)
+	want := faststringmap.DuplicateReport{
+		Exact: []faststringmap.DuplicateGroup{
+			{Keys: []string{"pear"}, Entries: []int{2, 4, 8}},
+		},
+		Case: []faststringmap.DuplicateGroup{
+			{Keys: []string{"APPLE", "Apple", "apple"}, Entries: []int{0, 1, 5}},
+		},
+		Space: []faststringmap.DuplicateGroup{
+			{Keys: []string{" plum\t", "plum"}, Entries: []int{6, 7}},
+			{Keys: []string{"apple", "apple "}, Entries: []int{0, 3}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AnalyzeDuplicates() = %+v\nwant %+v", got, want)
+	}
+
+	if r := faststringmap.AnalyzeDuplicates([]string{"a", "b"}); r.Exact != nil || r.Case != nil || r.Space != nil {
+		t.Errorf("AnalyzeDuplicates() of distinct keys = %+v", r)
+	}
+}
+
+func TestBuilderDuplicateAnalysis(t *testing.T) {
+	var b faststringmap.Builder[int]
+	b.Add("x", 1)
+	b.Add("X", 2)
+	b.Add("x", 3)
+
+	b.SetDuplicates(faststringmap.DuplicatesKeepLast)
+	b.Build()
+	if r := b.Report(); r.Duplicates != nil {
+		t.Errorf("Report().Duplicates = %+v without analysis", r.Duplicates)
+	}
+
+	b.SetDuplicates(faststringmap.DuplicatesError)
+	b.SetOptions(faststringmap.WithDuplicateAnalysis())
+	if _, _, err := b.BuildInto(make([]byte, 1024)); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Fatalf("BuildInto() error = %v want ErrDuplicateKey", err)
+	}
+	want := &faststringmap.DuplicateReport{
+		Exact: []faststringmap.DuplicateGroup{{Keys: []string{"x"}, Entries: []int{0, 2}}},
+		Case:  []faststringmap.DuplicateGroup{{Keys: []string{"X", "x"}, Entries: []int{0, 1, 2}}},
+	}
+	if r := b.Report(); !reflect.DeepEqual(r.Duplicates, want) {
+		t.Errorf("Report().Duplicates = %+v want %+v", r.Duplicates, want)
+	}
+
+	b.SetDuplicateAnalysis(false)
+	b.SetDuplicates(faststringmap.DuplicatesKeepLast)
+	b.Build()
+	if r := b.Report(); r.Duplicates != nil {
+		t.Errorf("Report().Duplicates = %+v after disabling analysis", r.Duplicates)
+	}
+}
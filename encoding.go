@@ -0,0 +1,206 @@
+package faststringmap
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// The node store of a Map has a fixed binary encoding, which is used when
+// building into a caller-provided buffer (see Builder.BuildInto) and when
+// constructing a map from previously encoded nodes (see FromEncodedNodes).
+//
+// The store is a sequence of nodes, each nodeSize (12) bytes long, with
+// multi-byte integers in little-endian byte order:
+//
+//	offset  size  field
+//	0       4     nextLo: index of the first child node
+//	4       1     nextLen: number of child nodes
+//	5       1     nextOffset: byte value of the first child node
+//	6       2     padding, always zero
+//	8       4     valueOffset: index+1 of the node's value, 0 if none
+//
+// Node 0 is the root. The children of a node are the nextLen consecutive
+// nodes starting at nextLo, where child i corresponds to the next key byte
+// nextOffset+i. A child with no value and no children of its own marks a
+// byte that does not continue any key.
+
+const nodeSize = 12
+
+var (
+	// ErrBufferTooSmall is returned when a caller-provided buffer cannot
+	// hold the encoded node store.
+	ErrBufferTooSmall = errors.New("faststringmap: buffer too small")
+
+	// ErrInvalidEncoding is returned when encoded data is malformed.
+	ErrInvalidEncoding = errors.New("faststringmap: invalid encoding")
+
+	// ErrUnsupportedVersion is returned when serialized data uses a format
+	// version this package can not read.
+	ErrUnsupportedVersion = errors.New("faststringmap: unsupported format version")
+)
+
+// FromEncodedNodes[T] constructs a Map from a node store in the documented
+// encoding, and the values referenced by it. The nodes must have the shape
+// of those produced by Builder.BuildInto: a tree whose nodes come after
+// their parents, with zero padding, and the values numbered in ascending
+// order of their keys, so values must be supplied in that order. Nodes of
+// any other shape are rejected with ErrInvalidEncoding, so no method of the
+// resulting Map panics or fails to return. Where the platform allows it,
+// the Map uses the nodes buffer directly instead of copying it, in which case
+// the buffer must not be modified while the Map is in use.
+func FromEncodedNodes[T any](nodes []byte, values []T) (Map[T], error) {
+	store, err := loadNodes(nodes, len(values))
+	if err != nil {
+		return Map[T]{}, err
+	}
+	return Map[T]{store: store, values: values, maxKeyLen: maxKeyLen(store)}, nil
+}
+
+// loadNodes returns the store encoded in nodes, referencing nValues values,
+// after validating it.
+func loadNodes(nodes []byte, nValues int) ([]mapInternalNode, error) {
+	if len(nodes) == 0 || len(nodes)%nodeSize != 0 {
+		return nil, ErrInvalidEncoding
+	}
+	for i := 0; i < len(nodes); i += nodeSize {
+		if nodes[i+6] != 0 || nodes[i+7] != 0 {
+			return nil, ErrInvalidEncoding
+		}
+	}
+
+	store, ok := nodesView(nodes)
+	if !ok {
+		store = decodeNodes(nodes)
+	}
+	if err := validateNodes(store, nValues); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// noKeyLenLimit is the maximum key length of maps whose longest key can not
+// be determined.
+const noKeyLenLimit = int(^uint(0) >> 1)
+
+// maxKeyLen returns the length of the longest key in a validated store, by
+// walking it level by level. Validated stores are trees, but as a safeguard
+// the walk is abandoned once it visits more nodes than there are, and
+// noKeyLenLimit is returned.
+func maxKeyLen(store []mapInternalNode) int {
+	level, next := []Uint{0}, []Uint(nil)
+	visited, maxLen := 1, 0
+	for depth := 0; len(level) > 0; depth++ {
+		next = next[:0]
+		for _, i := range level {
+			node := &store[i]
+			if node.valueOffset != 0 {
+				maxLen = depth
+			}
+			for c := Uint(0); c < Uint(node.nextLen); c++ {
+				next = append(next, node.nextLo+c)
+			}
+		}
+
+		visited += len(next)
+		if visited > len(store) {
+			return noKeyLenLimit
+		}
+		level, next = next, level
+	}
+	return maxLen
+}
+
+// validateNodes checks that store has the shape of the stores built by a
+// Builder, on which every method of Map relies: it is a tree, in which every
+// node but the root is the child of exactly one node, and comes after it;
+// the first and last children of every node continue some key; and the
+// values of keys are numbered 1 to nValues in ascending key order.
+func validateNodes(store []mapInternalNode, nValues int) error {
+	n := uint64(len(store))
+	if n == 0 {
+		return ErrInvalidEncoding
+	}
+
+	parented := make([]bool, n)
+	for i, node := range store {
+		if node.nextLen == 0 {
+			continue
+		}
+		if uint64(node.nextLo) <= uint64(i) || uint64(node.nextLo)+uint64(node.nextLen) > n {
+			return ErrInvalidEncoding
+		}
+		for c := node.nextLo; c < node.nextLo+Uint(node.nextLen); c++ {
+			if parented[c] {
+				return ErrInvalidEncoding
+			}
+			parented[c] = true
+		}
+	}
+	for _, p := range parented[1:] {
+		if !p {
+			return ErrInvalidEncoding
+		}
+	}
+
+	// children come after their parents, so a reverse scan sees the
+	// children of a node before the node
+	live := parented
+	clear(live)
+	for i := len(store) - 1; i >= 0; i-- {
+		node := &store[i]
+		if node.nextLen == 0 {
+			live[i] = node.valueOffset != 0
+			continue
+		}
+		if !live[node.nextLo] || !live[node.nextLo+Uint(node.nextLen)-1] {
+			return ErrInvalidEncoding
+		}
+		live[i] = true
+	}
+
+	// a depth first walk visits keys in ascending order
+	next := Uint(1)
+	stack := []Uint{0}
+	for len(stack) > 0 {
+		node := &store[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if node.valueOffset != 0 {
+			if node.valueOffset != next {
+				return ErrInvalidEncoding
+			}
+			next++
+		}
+		for c := Uint(node.nextLen); c > 0; c-- {
+			stack = append(stack, node.nextLo+c-1)
+		}
+	}
+	if uint64(next-1) != uint64(nValues) {
+		return ErrInvalidEncoding
+	}
+	return nil
+}
+
+func encodeNodes(dst []byte, store []mapInternalNode) {
+	for i, node := range store {
+		b := dst[i*nodeSize : (i+1)*nodeSize]
+		binary.LittleEndian.PutUint32(b[0:], node.nextLo)
+		b[4] = node.nextLen
+		b[5] = node.nextOffset
+		b[6], b[7] = 0, 0
+		binary.LittleEndian.PutUint32(b[8:], node.valueOffset)
+	}
+}
+
+func decodeNodes(src []byte) []mapInternalNode {
+	store := make([]mapInternalNode, len(src)/nodeSize)
+	for i := range store {
+		b := src[i*nodeSize : (i+1)*nodeSize]
+		store[i] = mapInternalNode{
+			nextLo:      binary.LittleEndian.Uint32(b[0:]),
+			nextLen:     b[4],
+			nextOffset:  b[5],
+			valueOffset: binary.LittleEndian.Uint32(b[8:]),
+		}
+	}
+	return store
+}
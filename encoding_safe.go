@@ -0,0 +1,30 @@
+//go:build faststringmap_minimal || tinygo
+
+package faststringmap
+
+// The minimal profile, selected by the faststringmap_minimal build tag and
+// by TinyGo, builds the package without unsafe, mmap and net/http, for
+// WASM and other constrained targets. Maps behave the same, but node
+// stores are always copied from encoded data instead of used in place,
+// LookupString and similar methods copy probes of maps with a key
+// transform, InlineMap is a plain Map, and built maps are always allocated
+// from the Go heap.
+
+// nodesView always reports that b can not be used as nodes in place.
+func nodesView(b []byte) (store []mapInternalNode, ok bool) {
+	return nil, false
+}
+
+// storeBytes returns nil, as the memory holding store can not be accessed
+// as bytes without unsafe, so Warm does not touch the node store.
+func storeBytes(store []mapInternalNode) []byte {
+	return nil
+}
+
+// stringBytes returns a copy of s as a byte slice.
+func stringBytes(s string) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	return []byte(s)
+}
@@ -0,0 +1,107 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestBuildIntoAndFromEncodedNodes(t *testing.T) {
+	entries := randomSmallStrings(1024, 8)
+
+	var b faststringmap.Builder[uint32]
+	for _, e := range entries {
+		b.Add(e.Key, e.Value)
+	}
+
+	_, n, err := b.BuildInto(nil)
+	if !errors.Is(err, faststringmap.ErrBufferTooSmall) {
+		t.Fatalf("BuildInto(nil) error = %v want ErrBufferTooSmall", err)
+	}
+
+	buf := make([]byte, n)
+	m, used, err := b.BuildInto(buf)
+	if err != nil || used != n {
+		t.Fatalf("BuildInto() = %d, %v want %d, nil", used, err, n)
+	}
+	checkEntries(t, &m, entries)
+
+	// a misaligned buffer can not be used in place, and is encoded instead
+	unaligned := make([]byte, n+1)[1:]
+	m, _, err = b.BuildInto(unaligned)
+	if err != nil {
+		t.Fatalf("BuildInto(unaligned) error = %v", err)
+	}
+	checkEntries(t, &m, entries)
+	if string(unaligned) != string(buf) {
+		t.Errorf("BuildInto(unaligned) encoding differs from aligned encoding")
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	values := make([]uint32, len(entries))
+	for i, e := range entries {
+		values[i] = e.Value
+	}
+
+	for _, nodes := range [][]byte{buf, unaligned} {
+		loaded, err := faststringmap.FromEncodedNodes(nodes, values)
+		if err != nil {
+			t.Fatalf("FromEncodedNodes() error = %v", err)
+		}
+		checkEntries(t, &loaded, entries)
+	}
+}
+
+func TestFromEncodedNodesInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		nodes   []byte
+		nValues int
+	}{
+		{"empty", nil, 0},
+		{"truncated", make([]byte, 11), 0},
+		{"child out of range", encodedNode(0, 2, 'a', 0), 0},
+		{"value out of range", encodedNode(0, 0, 0, 1), 0},
+		{"cycle", encodedNode(0, 1, 'a', 1), 1},
+		{"shared child", concat(encodedNode(1, 2, 'a', 0), encodedNode(2, 1, 'a', 0), encodedNode(0, 0, 0, 1)), 1},
+		{"orphan", concat(encodedNode(0, 0, 0, 1), encodedNode(0, 0, 0, 0)), 1},
+		{"padding", append(encodedNode(0, 0, 0, 1)[:6:6], 1, 0, 1, 0, 0, 0), 1},
+		{"dead last child", concat(encodedNode(1, 2, 'a', 0), encodedNode(0, 0, 0, 1), encodedNode(0, 0, 0, 0)), 1},
+		{"values out of order", concat(encodedNode(1, 2, 'a', 0), encodedNode(0, 0, 0, 2), encodedNode(0, 0, 0, 1)), 2},
+		{"unused value", encodedNode(0, 0, 0, 1), 2},
+	} {
+		_, err := faststringmap.FromEncodedNodes(tc.nodes, make([]uint32, tc.nValues))
+		if !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+			t.Errorf("%s: FromEncodedNodes() error = %v want ErrInvalidEncoding", tc.name, err)
+		}
+	}
+
+	valid := concat(encodedNode(1, 2, 'a', 0), encodedNode(0, 0, 0, 1), encodedNode(0, 0, 0, 2))
+	if _, err := faststringmap.FromEncodedNodes(valid, make([]uint32, 2)); err != nil {
+		t.Errorf("FromEncodedNodes(valid) error = %v", err)
+	}
+}
+
+// encodedNode returns a node in the documented encoding.
+func encodedNode(nextLo uint32, nextLen, nextOffset byte, valueOffset uint32) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, nextLo)
+	b = append(b, nextLen, nextOffset, 0, 0)
+	return binary.LittleEndian.AppendUint32(b, valueOffset)
+}
+
+func concat(nodes ...[]byte) []byte {
+	return bytes.Join(nodes, nil)
+}
+
+func checkEntries[T comparable](t *testing.T, m *faststringmap.Map[T], entries []faststringmap.MapEntry[T]) {
+	t.Helper()
+	for _, e := range entries {
+		if v, ok := m.LookupString(e.Key); !ok || v != e.Value {
+			t.Errorf("LookupString(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+//go:build !faststringmap_minimal && !tinygo
+
+package faststringmap
+
+import (
+	"unsafe"
+)
+
+// nodesView returns b reinterpreted as a slice of nodes without copying it.
+// ok is false if the in-memory layout of nodes on this platform does not
+// match the documented encoding, or b is not suitably aligned.
+func nodesView(b []byte) (store []mapInternalNode, ok bool) {
+	if !nativeLayoutMatchesEncoding || len(b) == 0 ||
+		uintptr(unsafe.Pointer(&b[0]))%unsafe.Alignof(mapInternalNode{}) != 0 {
+		return nil, false
+	}
+
+	return unsafe.Slice((*mapInternalNode)(unsafe.Pointer(&b[0])), len(b)/nodeSize), true
+}
+
+var nativeLayoutMatchesEncoding = func() bool {
+	var node mapInternalNode
+	one := uint16(1)
+	return unsafe.Sizeof(node) == nodeSize &&
+		unsafe.Offsetof(node.nextLen) == 4 &&
+		unsafe.Offsetof(node.nextOffset) == 5 &&
+		unsafe.Offsetof(node.valueOffset) == 8 &&
+		*(*byte)(unsafe.Pointer(&one)) == 1 // little-endian
+}()
+
+// storeBytes returns the memory holding store as a byte slice, without
+// copying it.
+func storeBytes(store []mapInternalNode) []byte {
+	if len(store) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&store[0])), uintptr(len(store))*unsafe.Sizeof(store[0]))
+}
+
+// stringBytes returns the memory holding s as a byte slice, without copying
+// it. The returned slice must not be modified.
+func stringBytes(s string) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	return unsafe.Slice(*(**byte)(unsafe.Pointer(&s)), len(s))
+}
@@ -0,0 +1,45 @@
+// Code generated by faststringmap.WriteGoEnum; DO NOT EDIT.
+
+package faststringmap_test
+
+import (
+	"strconv"
+
+	"alon.kr/x/faststringmap"
+)
+
+// Color is a color of the test enum.
+type Color uint32
+
+const (
+	ColorRed      Color = iota + 1 // "red"
+	ColorGreen                     // "green"
+	ColorDarkBlue                  // "dark-blue"
+)
+
+var colorNames = [...]string{"", "red", "green", "dark-blue"}
+
+var colorValues = faststringmap.NewMap([]faststringmap.MapEntry[Color]{
+	{Key: "dark-blue", Value: ColorDarkBlue},
+	{Key: "green", Value: ColorGreen},
+	{Key: "red", Value: ColorRed},
+})
+
+// ParseColor returns the Color named s.
+func ParseColor(s []byte) (Color, bool) {
+	return colorValues.LookupBytes(s)
+}
+
+// ParseColorString returns the Color named s.
+func ParseColorString(s string) (Color, bool) {
+	return colorValues.LookupString(s)
+}
+
+// String returns the name of c, or Color(c) with the number of c if c
+// is not a valid Color.
+func (c Color) String() string {
+	if c == 0 || int(c) >= len(colorNames) {
+		return "Color(" + strconv.FormatUint(uint64(c), 10) + ")"
+	}
+	return colorNames[c]
+}
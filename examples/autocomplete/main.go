@@ -0,0 +1,143 @@
+// Command autocomplete serves query completions over HTTP. It loads words
+// and their frequencies from a file of lines "word<TAB>count", and answers
+//
+//	GET /complete?q=prefix&n=10
+//
+// with the n most frequent words starting with prefix, as a JSON array.
+// Sending SIGHUP reloads the file, replacing the map atomically while
+// requests are being served.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"alon.kr/x/faststringmap"
+)
+
+// Completion is a word completing a query, and its frequency.
+type Completion struct {
+	Word  string `json:"word"`
+	Count uint64 `json:"count"`
+}
+
+// Server serves completions from a map from words to frequencies.
+type Server struct {
+	words *faststringmap.AtomicMap[uint64]
+}
+
+// NewServer returns a Server completing the words read from r.
+func NewServer(r io.Reader) (*Server, error) {
+	m, err := readWords(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{words: faststringmap.NewAtomicMap(m)}, nil
+}
+
+// Reload replaces the words of the server with those read from r.
+func (s *Server) Reload(r io.Reader) error {
+	m, err := readWords(r)
+	if err != nil {
+		return err
+	}
+	s.words.Store(m)
+	return nil
+}
+
+// Complete returns the n most frequent words starting with prefix.
+func (s *Server) Complete(prefix string, n int) []Completion {
+	top := s.words.Load().TopKUnderPrefix(prefix, n, func(count uint64) float64 { return float64(count) })
+	completions := make([]Completion, len(top))
+	for i, e := range top {
+		completions[i] = Completion{e.Key, e.Value}
+	}
+	return completions
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/complete" {
+		http.NotFound(w, r)
+		return
+	}
+	n := 10
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n < 1 || n > 100 {
+			http.Error(w, "n must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.Complete(strings.ToLower(r.URL.Query().Get("q")), n))
+}
+
+// readWords reads lines of words and counts, folding words to lower case.
+// Counts of words equal after folding are added up.
+func readWords(r io.Reader) (faststringmap.Map[uint64], error) {
+	counts := map[string]uint64{}
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		word, count, ok := strings.Cut(sc.Text(), "\t")
+		if !ok {
+			return faststringmap.Map[uint64]{}, fmt.Errorf("line %d: missing tab", line)
+		}
+		n, err := strconv.ParseUint(count, 10, 64)
+		if err != nil {
+			return faststringmap.Map[uint64]{}, fmt.Errorf("line %d: %w", line, err)
+		}
+		counts[strings.ToLower(word)] += n
+	}
+	if err := sc.Err(); err != nil {
+		return faststringmap.Map[uint64]{}, err
+	}
+	entries := make([]faststringmap.MapEntry[uint64], 0, len(counts))
+	for word, n := range counts {
+		entries = append(entries, faststringmap.MapEntry[uint64]{Key: word, Value: n})
+	}
+	return faststringmap.New(entries)
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	path := flag.String("words", "words.tsv", "file of lines word<TAB>count")
+	flag.Parse()
+
+	f, err := os.Open(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s, err := NewServer(f)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			f, err := os.Open(*path)
+			if err == nil {
+				err = s.Reload(f)
+				f.Close()
+			}
+			if err != nil {
+				log.Printf("reloading %s: %v", *path, err)
+			}
+		}
+	}()
+
+	log.Fatal(http.ListenAndServe(*addr, s))
+}
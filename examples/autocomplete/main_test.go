@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const words = "go\t900\ngolang\t500\ngopher\t700\nGopher\t50\ngoogle\t800\nrust\t600\n"
+
+func TestServer(t *testing.T) {
+	s, err := NewServer(strings.NewReader(words))
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	get := func(query string) []Completion {
+		t.Helper()
+		resp, err := http.Get(ts.URL + "/complete?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s status = %s", query, resp.Status)
+		}
+		var c []Completion
+		if err := json.NewDecoder(resp.Body).Decode(&c); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	want := []Completion{{"go", 900}, {"google", 800}, {"gopher", 750}}
+	if got := get("q=Go&n=3"); !slices.Equal(got, want) {
+		t.Errorf("complete Go = %v want %v", got, want)
+	}
+	if got := get("q=x"); len(got) != 0 {
+		t.Errorf("complete x = %v want none", got)
+	}
+
+	if err := s.Reload(strings.NewReader("gopher\t1\n")); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got, want := get("q=go"), []Completion{{"gopher", 1}}; !slices.Equal(got, want) {
+		t.Errorf("complete go after reload = %v want %v", got, want)
+	}
+
+	resp, err := http.Get(ts.URL + "/complete?q=go&n=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("n=0 status = %s want 400", resp.Status)
+	}
+	if _, err := NewServer(strings.NewReader("no tab\n")); err == nil {
+		t.Errorf("NewServer(no tab) succeeded")
+	}
+}
@@ -0,0 +1,150 @@
+// Command csvdict dictionary-encodes a column of a CSV file, replacing each
+// value by a small integer code. Encoding reads the CSV file from standard
+// input, writes the dictionary to a directory, and prints the codes, one
+// per line:
+//
+//	csvdict -column 2 -dir dict < data.csv > codes.txt
+//
+// Decoding reads codes from standard input and prints their values:
+//
+//	csvdict -decode -dir dict < codes.txt
+//
+// The dictionary is a serialized Map from value to number of occurrences,
+// dict.fstm, and the key list of the map, dict.fstk. Codes are the value
+// indices of the map, which the key list resolves back to values straight
+// from the memory mapped file.
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"alon.kr/x/faststringmap"
+)
+
+// Encode reads the CSV records of r, and returns a map from the distinct
+// values of the column to the number of their occurrences, and the code of
+// the value of every record.
+func Encode(r io.Reader, column int) (faststringmap.Map[uint32], []faststringmap.Uint, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	var values []string
+	counts := map[string]uint32{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return faststringmap.Map[uint32]{}, nil, err
+		}
+		if column >= len(record) {
+			line, _ := cr.FieldPos(0)
+			return faststringmap.Map[uint32]{}, nil, fmt.Errorf("line %d: no column %d", line, column)
+		}
+		v := record[column]
+		if _, ok := counts[v]; !ok {
+			v = string([]byte(v)) // do not retain the reused record
+		}
+		counts[v]++
+		values = append(values, v)
+	}
+
+	entries := make([]faststringmap.MapEntry[uint32], 0, len(counts))
+	for v, n := range counts {
+		entries = append(entries, faststringmap.MapEntry[uint32]{Key: v, Value: n})
+	}
+	dict, err := faststringmap.New(entries)
+	if err != nil {
+		return faststringmap.Map[uint32]{}, nil, err
+	}
+	codes := make([]faststringmap.Uint, len(values))
+	for i, v := range values {
+		codes[i] = dict.IndexString(v)
+	}
+	return dict, codes, nil
+}
+
+// WriteDict writes the dictionary and its key list to dir.
+func WriteDict(dir string, dict faststringmap.Map[uint32]) error {
+	data, err := dict.AppendBinary(nil, faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dict.fstm"), data, 0o644); err != nil {
+		return err
+	}
+
+	keys, err := dict.AppendKeys(nil)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "dict.fstk"), keys, 0o644)
+}
+
+// Decode writes the values of the codes read from r to w, one per line,
+// resolving them with the key list in dir.
+func Decode(w io.Writer, r io.Reader, dir string) error {
+	keys, err := faststringmap.OpenMappedKeyList(filepath.Join(dir, "dict.fstk"))
+	if err != nil {
+		return err
+	}
+	defer keys.Close()
+
+	sc := bufio.NewScanner(r)
+	bw := bufio.NewWriter(w)
+	var buf []byte
+	for sc.Scan() {
+		code, err := strconv.ParseUint(sc.Text(), 10, 32)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if buf, ok = keys.AppendKeyAtIndex(buf[:0], faststringmap.Uint(code)); !ok {
+			return fmt.Errorf("unknown code %d", code)
+		}
+		bw.Write(append(buf, '\n'))
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func main() {
+	column := flag.Int("column", 0, "index of the column to encode")
+	dir := flag.String("dir", ".", "directory of the dictionary")
+	decode := flag.Bool("decode", false, "decode codes instead of encoding a CSV file")
+	flag.Parse()
+
+	if *decode {
+		if err := Decode(os.Stdout, os.Stdin, *dir); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	dict, codes, err := Encode(os.Stdin, *column)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := WriteDict(*dir, dict); err != nil {
+		log.Fatal(err)
+	}
+	w := bufio.NewWriter(os.Stdout)
+	for _, c := range codes {
+		fmt.Fprintln(w, c)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	const data = "id,city\n1,Paris\n2,Oslo\n3,Paris\n4,\"Tel Aviv, Yafo\"\n5,Oslo\n"
+	dict, codes, err := Encode(strings.NewReader(data), 1)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if n, _ := dict.LookupString("Paris"); n != 2 {
+		t.Errorf("count of Paris = %d want 2", n)
+	}
+	if codes[1] != codes[3] || codes[2] != codes[5] || codes[1] == codes[2] {
+		t.Errorf("codes = %v want equal codes for equal values only", codes)
+	}
+
+	dir := t.TempDir()
+	if err := WriteDict(dir, dict); err != nil {
+		t.Fatalf("WriteDict() error = %v", err)
+	}
+	loaded, err := faststringmap.OpenMapped[uint32](filepath.Join(dir, "dict.fstm"), faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer loaded.Close()
+	if got := loaded.Map.IndexString("Oslo"); got != codes[2] {
+		t.Errorf("loaded IndexString(Oslo) = %d want %d", got, codes[2])
+	}
+
+	var in, out bytes.Buffer
+	for _, c := range codes {
+		fmt.Fprintln(&in, c)
+	}
+	if err := Decode(&out, &in, dir); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if want := "city\nParis\nOslo\nParis\nTel Aviv, Yafo\nOslo\n"; out.String() != want {
+		t.Errorf("Decode() wrote %q want %q", out.String(), want)
+	}
+
+	if err := Decode(&out, strings.NewReader("99\n"), dir); err == nil {
+		t.Errorf("Decode(unknown code) succeeded")
+	}
+	if _, _, err := Encode(strings.NewReader("a\n"), 1); err == nil {
+		t.Errorf("Encode(missing column) succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dict.fstk")); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,7 @@
+module alon.kr/x/faststringmap/examples
+
+go 1.23
+
+require alon.kr/x/faststringmap v0.0.0
+
+replace alon.kr/x/faststringmap => ../
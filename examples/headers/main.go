@@ -0,0 +1,68 @@
+// Command headers canonicalizes the names of HTTP header lines read from
+// standard input, such as "content-type: text/plain", writing them to
+// standard output. Names are looked up case-insensitively in a Map built
+// with WithFold, which holds the spellings that net/textproto does not
+// produce, such as WWW-Authenticate; other names are canonicalized by
+// net/textproto. Known names are returned without allocating.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"log"
+	"net/textproto"
+	"os"
+
+	"alon.kr/x/faststringmap"
+)
+
+var canonical = newCanonical(
+	"Accept", "Accept-Encoding", "Accept-Language", "Authorization",
+	"Cache-Control", "Connection", "Content-Encoding", "Content-Length",
+	"Content-MD5", "Content-Type", "Cookie", "DNT", "ETag", "Host",
+	"If-Modified-Since", "If-None-Match", "Last-Modified", "Location",
+	"Referer", "Set-Cookie", "TE", "User-Agent", "WWW-Authenticate",
+	"X-Forwarded-For", "X-Request-ID", "X-XSS-Protection",
+)
+
+func newCanonical(names ...string) faststringmap.Map[string] {
+	entries := make([]faststringmap.MapEntry[string], len(names))
+	for i, name := range names {
+		entries[i] = faststringmap.MapEntry[string]{Key: name, Value: name}
+	}
+	m, err := faststringmap.New(entries, faststringmap.WithFold())
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Canonical returns the canonical form of the header name.
+func Canonical(name []byte) string {
+	if c, ok := canonical.LookupBytes(name); ok {
+		return c
+	}
+	return textproto.CanonicalMIMEHeaderKey(string(name))
+}
+
+func main() {
+	sc := bufio.NewScanner(os.Stdin)
+	w := bufio.NewWriter(os.Stdout)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if name, value, ok := bytes.Cut(line, []byte(":")); ok {
+			w.WriteString(Canonical(bytes.TrimSpace(name)))
+			w.WriteString(":")
+			w.Write(value)
+		} else {
+			w.Write(line)
+		}
+		w.WriteString("\n")
+	}
+	if err := sc.Err(); err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
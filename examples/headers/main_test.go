@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestCanonical(t *testing.T) {
+	for name, want := range map[string]string{
+		"content-type":     "Content-Type",
+		"www-authenticate": "WWW-Authenticate",
+		"ETAG":             "ETag",
+		"dnt":              "DNT",
+		"x-custom-header":  "X-Custom-Header", // not in the map
+	} {
+		if got := Canonical([]byte(name)); got != want {
+			t.Errorf("Canonical(%q) = %q want %q", name, got, want)
+		}
+	}
+
+	name := []byte("x-request-id")
+	if allocs := testing.AllocsPerRun(100, func() { Canonical(name) }); allocs != 0 {
+		t.Errorf("Canonical(%q) allocs = %v want 0", name, allocs)
+	}
+}
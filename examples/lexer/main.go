@@ -0,0 +1,126 @@
+// Command lexer splits Go-like source read from standard input into tokens,
+// printing one token per line. Keywords are recognized with a Map from
+// identifier to token kind, and operators with the longest key of a Map of
+// operators that prefixes the input.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"unicode"
+	"unicode/utf8"
+
+	"alon.kr/x/faststringmap"
+)
+
+// Kind is the kind of a token.
+type Kind uint8
+
+const (
+	Ident Kind = iota + 1
+	Number
+	String
+	Keyword
+	Operator
+	Invalid
+)
+
+func (k Kind) String() string {
+	return [...]string{"", "ident", "number", "string", "keyword", "operator", "invalid"}[k]
+}
+
+// Token is a token of the source, at byte offset Pos.
+type Token struct {
+	Pos  int
+	Kind Kind
+	Text string
+}
+
+var keywords = faststringmap.FromMap(map[string]Kind{
+	"break": Keyword, "case": Keyword, "chan": Keyword, "const": Keyword,
+	"continue": Keyword, "default": Keyword, "defer": Keyword, "else": Keyword,
+	"fallthrough": Keyword, "for": Keyword, "func": Keyword, "go": Keyword,
+	"goto": Keyword, "if": Keyword, "import": Keyword, "interface": Keyword,
+	"map": Keyword, "package": Keyword, "range": Keyword, "return": Keyword,
+	"select": Keyword, "struct": Keyword, "switch": Keyword, "type": Keyword,
+	"var": Keyword,
+})
+
+var operators = faststringmap.FromMap(map[string]Kind{
+	"+": Operator, "-": Operator, "*": Operator, "/": Operator, "%": Operator,
+	"&": Operator, "|": Operator, "^": Operator, "<<": Operator, ">>": Operator,
+	"&^": Operator, "+=": Operator, "-=": Operator, "*=": Operator, "/=": Operator,
+	"%=": Operator, "&=": Operator, "|=": Operator, "^=": Operator, "<<=": Operator,
+	">>=": Operator, "&^=": Operator,
+	"&&": Operator, "||": Operator, "<-": Operator, "++": Operator, "--": Operator,
+	"==": Operator, "<": Operator, ">": Operator, "=": Operator, "!": Operator,
+	"!=": Operator, "<=": Operator, ">=": Operator, ":=": Operator, "...": Operator,
+	"(": Operator, ")": Operator, "[": Operator, "]": Operator, "{": Operator,
+	"}": Operator, ",": Operator, ";": Operator, ".": Operator, ":": Operator,
+})
+
+// Lex returns the tokens of src.
+func Lex(src string) []Token {
+	var tokens []Token
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+					break
+				}
+				i += size
+			}
+			kind, ok := keywords.LookupString(src[start:i])
+			if !ok {
+				kind = Ident
+			}
+			tokens = append(tokens, Token{start, kind, src[start:i]})
+		case '0' <= r && r <= '9':
+			for i < len(src) && ('0' <= src[i] && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, Token{start, Number, src[start:i]})
+		case r == '"':
+			for i++; i < len(src) && src[i] != '"' && src[i] != '\n'; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+			i = min(i+1, len(src))
+			tokens = append(tokens, Token{start, String, src[start:i]})
+		default:
+			n, kind, ok := operators.LongestPrefixString(src[i:])
+			if !ok {
+				n, kind = size, Invalid
+			}
+			i += n
+			tokens = append(tokens, Token{start, kind, src[start:i]})
+		}
+	}
+	return tokens
+}
+
+func main() {
+	src, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	for _, t := range Lex(string(src)) {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", t.Pos, t.Kind, t.Text)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
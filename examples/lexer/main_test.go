@@ -0,0 +1,20 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	got := Lex(`for i := 0; i <= n; i++ { x &^= "a\"b" } ...@`)
+	want := []Token{
+		{0, Keyword, "for"}, {4, Ident, "i"}, {6, Operator, ":="}, {9, Number, "0"},
+		{10, Operator, ";"}, {12, Ident, "i"}, {14, Operator, "<="}, {17, Ident, "n"},
+		{18, Operator, ";"}, {20, Ident, "i"}, {21, Operator, "++"}, {24, Operator, "{"},
+		{26, Ident, "x"}, {28, Operator, "&^="}, {32, String, `"a\"b"`},
+		{39, Operator, "}"}, {41, Operator, "..."}, {44, Invalid, "@"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Lex() =\n%v\nwant\n%v", got, want)
+	}
+}
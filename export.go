@@ -0,0 +1,91 @@
+package faststringmap
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// ExportTransitions calls fn for every transition of the trie underlying the
+// map, so that it can be compiled into other matchers, such as a regular
+// expression set, or visualized. Nodes are identified by integers, and the
+// root is node 0. A transition leads from node from to node to on byte b.
+// accepting reports whether a key ends at node to, in which case valueIndex
+// is the index of its value (see AtIndex), and is otherwise 0. Whether the
+// empty key is present is reported by IndexString("").
+//
+// Transitions are reported in ascending order of from, and then of b. Nodes
+// that do not continue any key are skipped, and every other node except the
+// root is the target of exactly one transition.
+func (m *Map[T]) ExportTransitions(fn func(from, to int, b byte, accepting bool, valueIndex Uint)) {
+	if m == nil {
+		return
+	}
+
+	for from := range m.store {
+		node := &m.store[from]
+		for i := Uint(0); i < Uint(node.nextLen); i++ {
+			to := node.nextLo + i
+			next := &m.store[to]
+			if next.valueOffset == 0 && next.nextLen == 0 {
+				continue // not a valid next byte
+			}
+			fn(from, int(to), node.nextOffset+byte(i), next.valueOffset != 0, next.valueOffset)
+		}
+	}
+}
+
+// ExportTree writes the prefixes of the keys in the map to w as a nested
+// JSON tree, for dictionary browsers and for eyeballing skew in the
+// distribution of keys. Every node of the tree is an object such as
+//
+//	{"prefix":"ca","keys":3,"accepting":false,"children":[...]}
+//
+// where keys is the number of keys starting with the prefix, and accepting
+// reports whether the prefix is itself a key. Children extend the prefix by
+// one byte, in ascending byte order. Nodes more than maxDepth bytes deep
+// are left out, and the nodes at maxDepth have no children field, although
+// keys counts the keys under them; a negative maxDepth exports the whole
+// trie. Prefixes that are not valid UTF-8 are written with U+FFFD
+// replacing invalid bytes, as by encoding/json. An empty map is written as
+// null.
+func (m *Map[T]) ExportTree(w io.Writer, maxDepth int) error {
+	bw := bufio.NewWriter(w)
+	if m == nil || len(m.store) == 0 || (m.store[0].valueOffset == 0 && m.store[0].nextLen == 0) {
+		bw.WriteString("null")
+	} else {
+		m.exportTreeNode(bw, &m.store[0], nil, maxDepth)
+	}
+	bw.WriteByte('\n')
+	return bw.Flush()
+}
+
+// exportTreeNode writes the subtree of a valid node with the supplied key
+// to bw, down to levels bytes below node. Write errors are reported by
+// bw.Flush.
+func (m *Map[T]) exportTreeNode(bw *bufio.Writer, node *mapInternalNode, key []byte, levels int) {
+	prefix, _ := json.Marshal(string(key)) // strings always marshal
+	first, last := m.subtreeRange(node)
+
+	bw.WriteString(`{"prefix":`)
+	bw.Write(prefix)
+	bw.WriteString(`,"keys":`)
+	bw.WriteString(strconv.Itoa(int(last) - int(first) + 1))
+	bw.WriteString(`,"accepting":`)
+	bw.WriteString(strconv.FormatBool(node.valueOffset != 0))
+	if levels != 0 && node.nextLen != 0 {
+		bw.WriteString(`,"children":[`)
+		for i, sep := Uint(0), ""; i < Uint(node.nextLen); i++ {
+			next := &m.store[node.nextLo+i]
+			if next.valueOffset == 0 && next.nextLen == 0 {
+				continue // not a valid next byte
+			}
+			bw.WriteString(sep)
+			m.exportTreeNode(bw, next, append(key, node.nextOffset+byte(i)), levels-1)
+			sep = ","
+		}
+		bw.WriteByte(']')
+	}
+	bw.WriteByte('}')
+}
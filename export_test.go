@@ -0,0 +1,107 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestExportTransitions(t *testing.T) {
+	entries := randomSmallStrings(1000, 8)
+	m := faststringmap.NewMap(entries)
+
+	// reconstruct the keys from the transitions, which lead away from the
+	// root in ascending order of their source node
+	prefixes := map[int]string{0: ""}
+	got := map[string]uint32{}
+	if index := m.IndexString(""); index != 0 {
+		got[""], _ = m.AtIndex(index)
+	}
+
+	lastFrom := 0
+	m.ExportTransitions(func(from, to int, b byte, accepting bool, valueIndex faststringmap.Uint) {
+		prefix, ok := prefixes[from]
+		if !ok || from < lastFrom {
+			t.Fatalf("transition from node %d before reaching it", from)
+		}
+		if _, seen := prefixes[to]; seen {
+			t.Fatalf("node %d is the target of two transitions", to)
+		}
+		if accepting != (valueIndex != 0) {
+			t.Errorf("transition to %d accepting = %v with value index %d", to, accepting, valueIndex)
+		}
+
+		lastFrom = from
+		prefixes[to] = prefix + string(b)
+		if accepting {
+			got[prefixes[to]], _ = m.AtIndex(valueIndex)
+		}
+	})
+
+	if len(got) != len(entries) {
+		t.Errorf("transitions accept %d keys want %d", len(got), len(entries))
+	}
+	for _, e := range entries {
+		if v, ok := got[e.Key]; !ok || v != e.Value {
+			t.Errorf("transitions map %q to %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+	}
+}
+
+func TestExportTree(t *testing.T) {
+	m := faststringmap.FromMap(map[string]int{"ca": 1, "car": 2, "cat": 3, "do": 4})
+
+	var buf bytes.Buffer
+	if err := m.ExportTree(&buf, 2); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"prefix":"","keys":4,"accepting":false,"children":[` +
+		`{"prefix":"c","keys":3,"accepting":false,"children":[{"prefix":"ca","keys":3,"accepting":true}]},` +
+		`{"prefix":"d","keys":1,"accepting":false,"children":[{"prefix":"do","keys":1,"accepting":true}]}]}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("ExportTree(2) =\n%s want\n%s", got, want)
+	}
+
+	type treeNode struct {
+		Prefix    string
+		Keys      int
+		Accepting bool
+		Children  []treeNode
+	}
+	buf.Reset()
+	if err := m.ExportTree(&buf, -1); err != nil {
+		t.Fatal(err)
+	}
+	var tree treeNode
+	if err := json.Unmarshal(buf.Bytes(), &tree); err != nil {
+		t.Fatalf("ExportTree(-1) writes invalid JSON: %v", err)
+	}
+	var keys []string
+	var collect func(treeNode)
+	collect = func(n treeNode) {
+		if n.Accepting {
+			keys = append(keys, n.Prefix)
+		}
+		for _, c := range n.Children {
+			collect(c)
+		}
+	}
+	collect(tree)
+	if len(keys) != 4 || keys[0] != "ca" || keys[3] != "do" {
+		t.Errorf("ExportTree(-1) has keys %q", keys)
+	}
+
+	buf.Reset()
+	var empty faststringmap.Map[int]
+	if err := empty.ExportTree(&buf, -1); err != nil || buf.String() != "null\n" {
+		t.Errorf("ExportTree() of an empty map = %q, %v want null", buf.String(), err)
+	}
+
+	errWrite := errors.New("write failed")
+	if err := m.ExportTree(errorWriter{errWrite}, -1); !errors.Is(err, errWrite) {
+		t.Errorf("ExportTree() error = %v want %v", err, errWrite)
+	}
+}
@@ -3,18 +3,31 @@
 
 package faststringmap
 
-import (
-	"sort"
-)
-
 type Uint = uint32
 
 type (
 	// Map[T] is a fast read only map from string to generic type T
 	// Lookups are about 5x faster than the built-in Go map type
+	//
+	// Every API iterating over keys yields them in ascending byte order,
+	// which is also the order of value indices, unless its name ends in
+	// Desc, in which case it yields them in descending byte order.
 	Map[T any] struct {
-		store  []mapInternalNode[T]
+		store  []mapInternalNode
 		values []T
+
+		keys          []string          // keys in the same order as values, if retained
+		fingerprints  []byte            // key fingerprints in the same order as values, if enabled
+		lazy          *lazyValues[T]    // values decoded on first access, instead of values
+		formatVersion uint16            // serialization format version the map was loaded from
+		maxKeyLen     int               // length of the longest key, or noKeyLenLimit if unknown
+		metadata      map[string]string // set by WithMetadata, read only
+
+		keyTransform func([]byte) []byte // applied to probes, if set by WithKeyTransform
+		fold         bool                // probes are folded to lower case, if set by WithFold
+
+		fingerprintSalt uint64 // salt of the fingerprints
+		fingerprintSeed uint32 // hash state fingerprints start from, derived from the salt
 	}
 
 	// MapEntry[T] is for supplying data to initialize a new map
@@ -23,31 +36,20 @@ type (
 		Value T
 	}
 
-	mapInternalNode[T any] struct {
+	mapInternalNode struct {
 		nextLo      Uint // index in store of next mapEntry
 		nextLen     byte // number of mapEntries in store used for next possible bytes
 		nextOffset  byte // offset from zero byte value of first element of range of mapEntries
 		valueOffset Uint // index+1 in values for byte sequence with no more bytes. 0 if not valid
 	}
-
-	mapBuilder[T any] struct {
-		stores [][]mapInternalNode[T]
-		values []T
-		len    Uint
-	}
 )
 
-// NewMap[T] constructs a new Map from the provided map entries
+// NewMap[T] constructs a new Map from the provided map entries.
+// The entries slice is not modified. Keys must be unique; NewMap panics
+// otherwise. See New for constructing a map with options.
 func NewMap[T any](entries []MapEntry[T]) Map[T] {
-	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
-
-	b := mapBuilder[T]{}
-	root := b.allocateNodes(1)
-	if len(entries) > 0 {
-		b.makeEntry(&root[0], entries, 0)
-	}
-
-	return b.toMap()
+	b := Builder[T]{entries: entries}
+	return b.Build()
 }
 
 // FromMap[T] constructs a new Map from a builtin Go map
@@ -60,70 +62,23 @@ func FromMap[T any](m map[string]T) Map[T] {
 	return NewMap[T](entries)
 }
 
-// makeEntry will initialize the supplied mapInternalNode for
-// the sorted strings in slice a considering bytes at entryIndex in the strings
-func (b *mapBuilder[T]) makeEntry(node *mapInternalNode[T], entries []MapEntry[T], entryIndex int) {
-	// if there is a string with no more bytes then it is always first because they are sorted
-	if len(entries[0].Key) == entryIndex {
-		b.values = append(b.values, entries[0].Value)
-		node.valueOffset = uint32(len(b.values))
-		entries = entries[1:]
-	}
-
-	if len(entries) == 0 {
-		return
-	}
-
-	node.nextOffset = entries[0].Key[entryIndex]             // lowest value for next byte
-	node.nextLen = entries[len(entries)-1].Key[entryIndex] - // highest value for next byte
-		node.nextOffset + 1 // minus lowest value +1 = number of possible next bytes
-	node.nextLo = uint32(b.len)           // first mapEntry struct in eventual built slice
-	next := b.allocateNodes(node.nextLen) // new mapInternalNodes default to "not valid"
-
-	for i, n := 0, len(entries); i < n; {
-		// find range of strings starting with the same byte
-		iSameByteHi := i + 1
-		for iSameByteHi < n && entries[iSameByteHi].Key[entryIndex] == entries[i].Key[entryIndex] {
-			iSameByteHi++
-		}
-		b.makeEntry(
-			&next[(entries[i].Key[entryIndex]-node.nextOffset)],
-			entries[i:iSameByteHi],
-			entryIndex+1,
-		)
-		i = iSameByteHi
-	}
-}
-
-func (b *mapBuilder[T]) allocateNodes(n byte) []mapInternalNode[T] {
-	store := make([]mapInternalNode[T], n)
-	b.stores = append(b.stores, store)
-	b.len += uint32(n)
-	return store
-}
-
-func (b *mapBuilder[T]) toMap() Map[T] {
-	m := Map[T]{
-		store:  make([]mapInternalNode[T], 0, b.len),
-		values: b.values,
-	}
-
-	for _, store := range b.stores {
-		m.store = append(m.store, store...)
-	}
-
-	return m
-}
-
 // MARK: Index
 
 // IndexString returns the index of the value in the map for the supplied
 // string, or 0 if the value is not present in the map. Use AtIndex() to get
-// the value using the resulting index.
+// the value using the resulting index. Find returns a typed Index instead,
+// which can not be mistaken for a valid slot. Indices are preserved when the
+// map is serialized and loaded again, whichever way it is loaded.
 func (m *Map[T]) IndexString(s string) Uint {
-	if m == nil || len(m.values) == 0 {
+	if m == nil || len(m.store) == 0 {
 		return 0
 	}
+	if m.keyTransform != nil {
+		return m.IndexBytes(stringBytes(s))
+	}
+	if m.fold {
+		return indexFold(m, s, false)
+	}
 
 	bv := &m.store[0]
 	for i, n := 0, len(s); i < n; i++ {
@@ -142,16 +97,28 @@ func (m *Map[T]) IndexString(s string) Uint {
 		return 0
 	}
 
+	if m.fingerprints != nil {
+		return m.verifyFingerprint(bv.valueOffset, fingerprint(s, m.fingerprintSeed))
+	}
+
 	return bv.valueOffset
 }
 
 // IndexBytes returns the index of the value in the map for the supplied
 // byte slice, or 0 if the value is not present in the map. Use AtIndex() to get
-// the value using the resulting index.
+// the value using the resulting index. Like all lookup methods taking a byte
+// slice, it never allocates and does not retain s, unless the map has a key
+// transform that does (see WithKeyTransform).
 func (m *Map[T]) IndexBytes(s []byte) Uint {
-	if m == nil || len(m.values) == 0 {
+	if m == nil || len(m.store) == 0 {
 		return 0
 	}
+	if m.keyTransform != nil {
+		s = m.keyTransform(s)
+	}
+	if m.fold {
+		return indexFold(m, s, false)
+	}
 
 	bv := &m.store[0]
 	for _, b := range s {
@@ -169,6 +136,10 @@ func (m *Map[T]) IndexBytes(s []byte) Uint {
 		return 0
 	}
 
+	if m.fingerprints != nil {
+		return m.verifyFingerprint(bv.valueOffset, fingerprint(s, m.fingerprintSeed))
+	}
+
 	return bv.valueOffset
 }
 
@@ -179,11 +150,181 @@ func (m *Map[T]) LookupString(s string) (t T, ok bool) {
 	return m.AtIndex(m.IndexString(s))
 }
 
-// LookupBytes looks up the supplied byte slice in the map
+// LookupBytes looks up the supplied byte slice in the map.
+// It never allocates and does not retain s, so there is no need to convert
+// byte slices to strings (using unsafe or otherwise) in order to look them up,
+// and s may be modified as soon as LookupBytes returns.
 func (m *Map[T]) LookupBytes(s []byte) (t T, ok bool) {
 	return m.AtIndex(m.IndexBytes(s))
 }
 
+// LookupKey looks up the supplied string in the map, and also returns the
+// key stored in the map that is equal to it. This allows using the map as a
+// pool of canonical strings. ok is false if the key is not present, or the
+// map was built without retaining keys (see Builder.SetRetainKeys).
+func (m *Map[T]) LookupKey(s string) (key string, t T, ok bool) {
+	return m.keyAtIndex(m.IndexString(s))
+}
+
+// LookupKeyBytes looks up the supplied byte slice in the map like LookupKey.
+// It does not allocate, so it can be used to intern strings from byte slices.
+func (m *Map[T]) LookupKeyBytes(s []byte) (key string, t T, ok bool) {
+	return m.keyAtIndex(m.IndexBytes(s))
+}
+
+// LookupStringBounded looks up the supplied string in the map like
+// LookupString, but rejects strings longer than the longest key in the map
+// (see MaxKeyLen) without reading them. Lookups never read more than
+// MaxKeyLen()+1 bytes of the probe anyway, but for maps with long keys this
+// makes rejecting oversized probes, such as hostile input, cost O(1).
+// Probes of a map with a key transform are transformed before they are
+// bounded.
+func (m *Map[T]) LookupStringBounded(s string) (t T, ok bool) {
+	if m == nil || len(s) > m.maxKeyLen && m.keyTransform == nil {
+		return t, false
+	}
+	return m.LookupString(s)
+}
+
+// LookupBytesBounded looks up the supplied byte slice like LookupStringBounded.
+func (m *Map[T]) LookupBytesBounded(s []byte) (t T, ok bool) {
+	if m == nil || len(s) > m.maxKeyLen && m.keyTransform == nil {
+		return t, false
+	}
+	return m.LookupBytes(s)
+}
+
+// MaxKeyLen returns the length in bytes of the longest key in the map. It is
+// computed when the map is built or loaded.
+func (m *Map[T]) MaxKeyLen() int {
+	if m == nil {
+		return 0
+	}
+	return m.maxKeyLen
+}
+
+func (m *Map[T]) keyAtIndex(index Uint) (key string, t T, ok bool) {
+	if index == 0 || index-1 >= Uint(len(m.keys)) {
+		return "", t, false
+	}
+	t, ok = m.AtIndex(index)
+	return m.keys[index-1], t, ok
+}
+
+// MARK: Fold
+
+// LookupStringFold looks up the supplied string in the map with ASCII upper
+// case letters folded to lower case, so that it finds keys regardless of the
+// case of the probe. Only keys without upper case letters can be found.
+// Folding happens during the traversal, so it never allocates. For maps
+// built with WithFold, LookupString and LookupBytes fold probes in the
+// same way.
+func (m *Map[T]) LookupStringFold(s string) (t T, ok bool) {
+	return m.AtIndex(indexFold(m, s, false))
+}
+
+// LookupBytesFold looks up the supplied byte slice like LookupStringFold.
+func (m *Map[T]) LookupBytesFold(s []byte) (t T, ok bool) {
+	return m.AtIndex(indexFold(m, s, false))
+}
+
+// isFoldSeparator reports whether indexFold skips b when skipping separators.
+func isFoldSeparator(b byte) bool {
+	return b == '_' || b == '-' || b == '.'
+}
+
+// indexFold returns the index of the value for s with ASCII upper case
+// letters folded to lower case, and if skipSeparators is set, without the
+// bytes for which isFoldSeparator is true.
+func indexFold[T any, S string | []byte](m *Map[T], s S, skipSeparators bool) Uint {
+	if m == nil || len(m.store) == 0 {
+		return 0
+	}
+
+	h := m.fingerprintSeed // fingerprint of the folded probe, computed on the way
+	bv := &m.store[0]
+	for i, n := 0, len(s); i < n; i++ {
+		b := s[i]
+		if 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		} else if skipSeparators && isFoldSeparator(b) {
+			continue
+		}
+		h = (h ^ uint32(b)) * 16777619
+
+		if b < bv.nextOffset {
+			return 0
+		}
+		ni := b - bv.nextOffset
+		if ni >= bv.nextLen {
+			return 0
+		}
+		bv = &m.store[bv.nextLo+uint32(ni)]
+	}
+
+	if bv.valueOffset == 0 {
+		return 0
+	}
+
+	if m.fingerprints != nil {
+		return m.verifyFingerprint(bv.valueOffset, byte(h^h>>8^h>>16^h>>24))
+	}
+
+	return bv.valueOffset
+}
+
+// MARK: Prefix
+
+// LongestPrefixString looks up the longest key in the map that is a prefix of
+// the supplied string, and returns its length and value. If the map was
+// built with WithFold, upper case letters of s are folded like the probes
+// of LookupString.
+func (m *Map[T]) LongestPrefixString(s string) (prefixLen int, t T, ok bool) {
+	index, prefixLen := longestPrefix(m, s)
+	t, ok = m.AtIndex(index)
+	return prefixLen, t, ok
+}
+
+// LongestPrefixBytes looks up the longest key in the map that is a prefix of
+// the supplied byte slice, and returns its length and value, like
+// LongestPrefixString.
+func (m *Map[T]) LongestPrefixBytes(s []byte) (prefixLen int, t T, ok bool) {
+	index, prefixLen := longestPrefix(m, s)
+	t, ok = m.AtIndex(index)
+	return prefixLen, t, ok
+}
+
+func longestPrefix[T any, S string | []byte](m *Map[T], s S) (index Uint, prefixLen int) {
+	if m == nil || len(m.store) == 0 {
+		return 0, 0
+	}
+
+	h := m.fingerprintSeed // fingerprint of the folded prefix, computed on the way
+	bv := &m.store[0]
+	index = bv.valueOffset
+	for i, n := 0, len(s); i < n; i++ {
+		b := s[i]
+		if m.fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		if b < bv.nextOffset {
+			break
+		}
+		ni := b - bv.nextOffset
+		if ni >= bv.nextLen {
+			break
+		}
+		bv = &m.store[bv.nextLo+uint32(ni)]
+		h = (h ^ uint32(b)) * 16777619
+		if bv.valueOffset != 0 &&
+			(m.fingerprints == nil || m.verifyFingerprint(bv.valueOffset, byte(h^h>>8^h>>16^h>>24)) != 0) {
+			index, prefixLen = bv.valueOffset, i+1
+		}
+	}
+
+	return index, prefixLen
+}
+
 // MARK: At
 
 // AtIndex returns the value in the map at the supplied internal index
@@ -191,6 +332,50 @@ func (m *Map[T]) AtIndex(index Uint) (t T, ok bool) {
 	if index != 0 && index-1 < Uint(len(m.values)) {
 		return m.values[index-1], true
 	} else {
-		return t, false
+		return m.atIndexSlow(index)
+	}
+}
+
+// atIndexSlow is kept out of AtIndex so that AtIndex can be inlined.
+func (m *Map[T]) atIndexSlow(index Uint) (t T, ok bool) {
+	if index != 0 && m.lazy != nil {
+		return m.lazy.at(index - 1)
+	}
+	return t, false
+}
+
+// lenOrZero returns the number of values in the map, or 0 for a nil map.
+func (m *Map[T]) lenOrZero() int {
+	if m == nil {
+		return 0
+	}
+	return m.len()
+}
+
+// len returns the number of values in the map.
+func (m *Map[T]) len() int {
+	if m.lazy != nil {
+		return m.lazy.len()
+	}
+	return len(m.values)
+}
+
+// MARK: Export
+
+// ToGoMap returns a new builtin Go map holding all entries of the map.
+// It is the inverse of FromMap.
+func (m *Map[T]) ToGoMap() map[string]T {
+	if m == nil {
+		return map[string]T{}
 	}
+
+	gm := make(map[string]T, m.len())
+	m.walk(func(key []byte, index Uint) bool {
+		if v, ok := m.AtIndex(index); ok {
+			gm[string(key)] = v
+		}
+		return true
+	})
+
+	return gm
 }
@@ -4,6 +4,7 @@
 package faststringmap_test
 
 import (
+	"errors"
 	"math/rand"
 	"strconv"
 	"strings"
@@ -85,6 +86,36 @@ func TestFastStringToUint32(t *testing.T) {
 	testAgainstDescriptor(t, mapTestDescription[uint32]{in: inEntries, out: outKeys})
 }
 
+func TestFastStringToUint32LongCommonPrefix(t *testing.T) {
+	const prefix = "service.region.metric."
+	entries := randomSmallStrings(512, 8)
+	for i := range entries {
+		entries[i].Key = prefix + entries[i].Key
+	}
+	testAgainstDescriptor(t, mapTestDescription[uint32]{in: entries, out: []string{prefix[:4]}})
+}
+
+func TestFastStringToUint32DeepBranching(t *testing.T) {
+	// every level of the keys branches into more keys than are sorted
+	// using insertion sort
+	var entries []faststringmap.MapEntry[uint32]
+	for depth := 0; depth < 200; depth++ {
+		for c := byte('b'); c < 'b'+40; c++ {
+			key := strings.Repeat("a", depth) + string(c)
+			entries = append(entries, faststringmap.MapEntry[uint32]{key, uint32(len(entries))})
+		}
+	}
+	testAgainstDescriptor(t, mapTestDescription[uint32]{in: entries, out: []string{"a", "aaa", "aa\xff"}})
+}
+
+func TestNewMapDoesNotModifyEntries(t *testing.T) {
+	entries := []faststringmap.MapEntry[uint32]{{"c", 1}, {"a", 2}, {"b", 3}}
+	faststringmap.NewMap(entries)
+	if entries[0].Key != "c" || entries[1].Key != "a" || entries[2].Key != "b" {
+		t.Errorf("NewMap modified entries: %v", entries)
+	}
+}
+
 type mapTestDescription[T any] struct {
 	in  []faststringmap.MapEntry[T]
 	out []string
@@ -188,3 +219,169 @@ func BenchmarkGoStringMap(b *testing.B) {
 		}
 	}
 }
+
+func TestToGoMap(t *testing.T) {
+	entries := randomSmallStrings(1024, 8)
+	m := faststringmap.NewMap(entries)
+
+	gm := m.ToGoMap()
+	if len(gm) != len(entries) {
+		t.Errorf("len(ToGoMap()) = %d want %d", len(gm), len(entries))
+	}
+	for _, e := range entries {
+		if v, ok := gm[e.Key]; !ok || v != e.Value {
+			t.Errorf("ToGoMap()[%q] = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+	}
+
+	empty := (*faststringmap.Map[uint32])(nil).ToGoMap()
+	if len(empty) != 0 {
+		t.Errorf("len(nil.ToGoMap()) = %d want 0", len(empty))
+	}
+}
+
+func BenchmarkNewMap(b *testing.B) {
+	for _, n := range []int{1 << 10, 1 << 16, 1 << 20} {
+		entries := randomSmallStrings(n, 16)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			in := make([]faststringmap.MapEntry[uint32], len(entries))
+			for bi := 0; bi < b.N; bi++ {
+				copy(in, entries)
+				faststringmap.NewMap(in)
+			}
+		})
+	}
+}
+
+func TestLongestPrefix(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"", 0}, {"a", 1}, {"abc", 3}})
+
+	for _, tc := range []struct {
+		s       string
+		wantLen int
+		want    int
+	}{
+		{"", 0, 0},
+		{"x", 0, 0},
+		{"ab", 1, 1},
+		{"abc", 3, 3},
+		{"abcd", 3, 3},
+	} {
+		n, v, ok := m.LongestPrefixString(tc.s)
+		if !ok || n != tc.wantLen || v != tc.want {
+			t.Errorf("LongestPrefixString(%q) = %d, %v, %v want %d, %v, true", tc.s, n, v, ok, tc.wantLen, tc.want)
+		}
+		n, v, ok = m.LongestPrefixBytes([]byte(tc.s))
+		if !ok || n != tc.wantLen || v != tc.want {
+			t.Errorf("LongestPrefixBytes(%q) = %d, %v, %v want %d, %v, true", tc.s, n, v, ok, tc.wantLen, tc.want)
+		}
+	}
+
+	m = faststringmap.NewMap([]faststringmap.MapEntry[int]{{"abc", 3}})
+	if n, v, ok := m.LongestPrefixString("ab"); ok {
+		t.Errorf("LongestPrefixString(ab) = %d, %v, expected not to be present", n, v)
+	}
+}
+
+func TestLookupBounded(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"a", 1}, {"abcd", 4}, {"xy", 2}})
+	if got := m.MaxKeyLen(); got != 4 {
+		t.Errorf("MaxKeyLen() = %d want 4", got)
+	}
+
+	if v, ok := m.LookupStringBounded("abcd"); !ok || v != 4 {
+		t.Errorf("LookupStringBounded(abcd) = %v, %v want 4, true", v, ok)
+	}
+	if v, ok := m.LookupBytesBounded([]byte("xy")); !ok || v != 2 {
+		t.Errorf("LookupBytesBounded(xy) = %v, %v want 2, true", v, ok)
+	}
+	if v, ok := m.LookupStringBounded(strings.Repeat("a", 1<<20)); ok {
+		t.Errorf("LookupStringBounded(long) = %v, expected not to be present", v)
+	}
+
+	var nilMap *faststringmap.Map[int]
+	if _, ok := nilMap.LookupStringBounded(""); ok || nilMap.MaxKeyLen() != 0 {
+		t.Errorf("nil map reported a key")
+	}
+}
+
+func TestMaxKeyLenLoaded(t *testing.T) {
+	m := faststringmap.NewMap(randomSmallStrings(1000, 12))
+	data := serialize(t, m)
+	for name, unmarshal := range map[string]func([]byte, faststringmap.ValueCodec[uint32]) (faststringmap.Map[uint32], error){
+		"UnmarshalMap":     faststringmap.UnmarshalMap[uint32],
+		"UnmarshalMapLazy": faststringmap.UnmarshalMapLazy[uint32],
+	} {
+		loaded, err := unmarshal(data, faststringmap.IntCodec[uint32]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := loaded.MaxKeyLen(), m.MaxKeyLen(); got != want {
+			t.Errorf("MaxKeyLen() of map loaded by %s = %d want %d", name, got, want)
+		}
+	}
+
+	// a hand-crafted store whose root is its own child, accepting all of a*,
+	// is not a tree
+	cyclic := []byte{0, 0, 0, 0, 1, 'a', 0, 0, 1, 0, 0, 0}
+	if _, err := faststringmap.FromEncodedNodes(cyclic, []int{1}); !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+		t.Errorf("FromEncodedNodes(cyclic) error = %v want ErrInvalidEncoding", err)
+	}
+}
+
+// Lookups never allocating is one of the main promises of this package.
+func TestLookupAllocations(t *testing.T) {
+	var b faststringmap.Builder[string]
+	b.SetRetainKeys(true)
+	b.Add("key1", "value1")
+	b.Add("key2", "value2")
+	m := b.Build()
+
+	s, bs := "key2", []byte("key2")
+	for name, fn := range map[string]func(){
+		"IndexString":         func() { m.IndexString(s) },
+		"IndexBytes":          func() { m.IndexBytes(bs) },
+		"LookupString":        func() { m.LookupString(s) },
+		"LookupBytes":         func() { m.LookupBytes(bs) },
+		"AtIndex":             func() { m.AtIndex(1) },
+		"LookupKey":           func() { m.LookupKey(s) },
+		"LookupKeyBytes":      func() { m.LookupKeyBytes(bs) },
+		"LongestPrefixString": func() { m.LongestPrefixString(s) },
+		"LongestPrefixBytes":  func() { m.LongestPrefixBytes(bs) },
+		"LookupStringBounded": func() { m.LookupStringBounded(s) },
+		"LookupBytesBounded":  func() { m.LookupBytesBounded(bs) },
+	} {
+		if allocs := testing.AllocsPerRun(100, fn); allocs != 0 {
+			t.Errorf("%s allocates %v times per run want 0", name, allocs)
+		}
+	}
+}
+
+func BenchmarkLookupEntryPoints(b *testing.B) {
+	m, keys := typicalCodeStrings(nStrsBench)
+	fm := faststringmap.FromMap(m)
+	byteKeys := make([][]byte, len(keys))
+	for i, k := range keys {
+		byteKeys[i] = []byte(k)
+	}
+
+	for _, bc := range []struct {
+		name string
+		fn   func(si int)
+	}{
+		{"IndexString", func(si int) { fm.IndexString(keys[si]) }},
+		{"IndexBytes", func(si int) { fm.IndexBytes(byteKeys[si]) }},
+		{"LookupString", func(si int) { fm.LookupString(keys[si]) }},
+		{"LookupBytes", func(si int) { fm.LookupBytes(byteKeys[si]) }},
+		{"LongestPrefixBytes", func(si int) { fm.LongestPrefixBytes(byteKeys[si]) }},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for bi := 0; bi < b.N; bi++ {
+				for si := range keys {
+					bc.fn(si)
+				}
+			}
+		})
+	}
+}
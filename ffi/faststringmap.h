@@ -0,0 +1,143 @@
+/*
+ * faststringmap.h - lookups in maps serialized by the Go package
+ * alon.kr/x/faststringmap, from C and anything that can call C.
+ *
+ * Maps are built in Go and serialized with Map.AppendBinary, which writes
+ * format version 3. This header reads that format from a buffer in memory,
+ * such as a mapped file, without copying or allocating. It is header only:
+ * include it in one or more translation units, no library to link.
+ *
+ * All functions are safe on malformed data: every offset read from the
+ * buffer is checked against its size before use. The checksum of the data
+ * is not verified.
+ *
+ * Probes are looked up byte for byte. Maps built with WithFold or
+ * WithKeyTransform expect canonical probes: lower case ASCII letters, or the
+ * output of the key transform, which the caller must apply.
+ */
+#ifndef FASTSTRINGMAP_H
+#define FASTSTRINGMAP_H
+
+#include <stddef.h>
+#include <stdint.h>
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+/* A map opened by fsm_open. It points into the caller's buffer. */
+typedef struct fsm_map {
+	const uint8_t *nodes;   /* node store, 12 bytes per node */
+	uint32_t n_nodes;       /* number of nodes, at least 1 */
+	uint32_t n_values;      /* number of keys and values */
+	const uint8_t *offsets; /* n_values+1 value offsets, 8 bytes each */
+	const uint8_t *values;  /* value data */
+	uint64_t values_len;    /* size of the value data */
+} fsm_map;
+
+static inline uint32_t fsm__u32(const uint8_t *p) {
+	return (uint32_t)p[0] | (uint32_t)p[1] << 8 | (uint32_t)p[2] << 16 | (uint32_t)p[3] << 24;
+}
+
+static inline uint64_t fsm__u64(const uint8_t *p) {
+	return (uint64_t)fsm__u32(p) | (uint64_t)fsm__u32(p + 4) << 32;
+}
+
+/*
+ * fsm_open opens the serialized map in data[0:len]. It returns 0 on
+ * success, or -1 if data is not a map in format version 3.
+ *
+ * Layout: a 32 byte header holds the magic "FSTM" at offset 0, the format
+ * version (uint16) at 4, flags (uint16) at 6, the number of nodes n
+ * (uint32) at 8 and the number of values v (uint32) at 12. If bit 0 of the
+ * flags is set, v bytes of key fingerprints follow the nodes, which lookups
+ * here do not need. Then come v+1 value offsets (uint64), and the value
+ * data, followed by metadata if bit 1 of the flags is set. All integers
+ * are little-endian.
+ */
+static inline int fsm_open(fsm_map *m, const uint8_t *data, size_t len) {
+	uint64_t n, v, pos;
+
+	if (len < 32 || data[0] != 'F' || data[1] != 'S' || data[2] != 'T' || data[3] != 'M')
+		return -1;
+	if ((data[4] | data[5] << 8) != 3)
+		return -1;
+
+	n = fsm__u32(data + 8);
+	v = fsm__u32(data + 12);
+	if (n == 0)
+		return -1;
+
+	pos = 32 + 12 * n;
+	m->nodes = data + 32;
+	if (data[6] & 1)
+		pos += v; /* skip fingerprints */
+	m->offsets = data + pos;
+	pos += 8 * (v + 1);
+	if (pos > len)
+		return -1;
+	m->values = data + pos;
+	m->values_len = len - pos;
+	m->n_nodes = (uint32_t)n;
+	m->n_values = (uint32_t)v;
+	return 0;
+}
+
+/*
+ * fsm_lookup returns the index of the value of key[0:len], from 1 to
+ * n_values, or 0 if it is not a key. The indices are those of the AtIndex
+ * method of the Go map.
+ *
+ * Node i is the 12 bytes at nodes+12*i: the index of its first child
+ * (uint32) at 0, the number of children (uint8) at 4, the byte leading to
+ * the first child (uint8) at 5, and the index of the value of the key ending
+ * at the node (uint32) at 8, or 0 if none does. Lookups start at node 0 and
+ * follow one child per byte of the key.
+ */
+static inline uint32_t fsm_lookup(const fsm_map *m, const uint8_t *key, size_t len) {
+	const uint8_t *node = m->nodes;
+	uint32_t value;
+	size_t i;
+
+	for (i = 0; i < len; i++) {
+		uint8_t ni = (uint8_t)(key[i] - node[5]); /* bytes below the first wrap around */
+		uint64_t next;
+
+		if (ni >= node[4])
+			return 0;
+		next = (uint64_t)fsm__u32(node) + ni;
+		if (next >= m->n_nodes)
+			return 0;
+		node = m->nodes + 12 * next;
+	}
+
+	value = fsm__u32(node + 8);
+	return value <= m->n_values ? value : 0;
+}
+
+/*
+ * fsm_value sets *data and *len to the encoded value at index, as returned
+ * by fsm_lookup. It returns 0 on success, or -1 if index is not valid. The
+ * encoding of values is that of the ValueCodec the map was serialized with:
+ * IntCodec writes varints (LEB128, zigzag encoded for signed types), and
+ * StringCodec and BytesCodec write the bytes themselves.
+ */
+static inline int fsm_value(const fsm_map *m, uint32_t index, const uint8_t **data, size_t *len) {
+	uint64_t lo, hi;
+
+	if (index == 0 || index > m->n_values)
+		return -1;
+	lo = fsm__u64(m->offsets + 8 * (uint64_t)(index - 1));
+	hi = fsm__u64(m->offsets + 8 * (uint64_t)index);
+	if (lo > hi || hi > m->values_len)
+		return -1;
+	*data = m->values + lo;
+	*len = (size_t)(hi - lo);
+	return 0;
+}
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif /* FASTSTRINGMAP_H */
@@ -0,0 +1,109 @@
+// Package ffi supports querying maps built in Go from other languages,
+// without cgo. A map is exported in its flat serialized form, written by
+// Map.AppendBinary, and looked up in place by a small algorithm documented
+// in the C header Header, which is the reference for ports to other
+// languages, such as Rust or a WASM host.
+//
+// Map is the same algorithm in Go, function for function, and the
+// conformance tests of this package check both it and the C header, when a
+// C compiler is available, against lookups in faststringmap.Map.
+package ffi
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"errors"
+)
+
+// Header is the C header faststringmap.h, to be written next to exported
+// maps by build tools.
+//
+//go:embed faststringmap.h
+var Header string
+
+// ErrFormat is returned by Open for data that is not a serialized map in
+// format version 3.
+var ErrFormat = errors.New("faststringmap/ffi: not a map in format version 3")
+
+// Map is a serialized map opened by Open, like fsm_map in the C header. It
+// refers to the data it was opened from.
+type Map struct {
+	nodes   []byte
+	nNodes  uint32
+	nValues uint32
+	offsets []byte
+	values  []byte
+}
+
+// Open opens the serialized map in data, like fsm_open.
+func Open(data []byte) (Map, error) {
+	if len(data) < 32 || string(data[:4]) != "FSTM" || binary.LittleEndian.Uint16(data[4:]) != 3 {
+		return Map{}, ErrFormat
+	}
+
+	n := uint64(binary.LittleEndian.Uint32(data[8:]))
+	v := uint64(binary.LittleEndian.Uint32(data[12:]))
+	if n == 0 {
+		return Map{}, ErrFormat
+	}
+
+	pos := 32 + 12*n
+	nodes := pos
+	if data[6]&1 != 0 {
+		pos += v // skip fingerprints
+	}
+	offsets := pos
+	pos += 8 * (v + 1)
+	if pos > uint64(len(data)) {
+		return Map{}, ErrFormat
+	}
+	return Map{
+		nodes:   data[32:nodes],
+		nNodes:  uint32(n),
+		nValues: uint32(v),
+		offsets: data[offsets:pos],
+		values:  data[pos:],
+	}, nil
+}
+
+// Lookup returns the index of the value of key, from 1 to Len, or 0 if it
+// is not a key, like fsm_lookup.
+func (m *Map) Lookup(key []byte) uint32 {
+	node := m.nodes[:12]
+	for _, b := range key {
+		ni := b - node[5] // bytes below the first wrap around
+		if ni >= node[4] {
+			return 0
+		}
+		next := uint64(binary.LittleEndian.Uint32(node)) + uint64(ni)
+		if next >= uint64(m.nNodes) {
+			return 0
+		}
+		node = m.nodes[12*next : 12*next+12]
+	}
+
+	value := binary.LittleEndian.Uint32(node[8:])
+	if value > m.nValues {
+		return 0
+	}
+	return value
+}
+
+// Value returns the encoded value at index, as returned by Lookup, like
+// fsm_value. ok is false if index is not valid.
+func (m *Map) Value(index uint32) (data []byte, ok bool) {
+	if index == 0 || index > m.nValues {
+		return nil, false
+	}
+	lo := binary.LittleEndian.Uint64(m.offsets[8*(index-1):])
+	hi := binary.LittleEndian.Uint64(m.offsets[8*index:])
+	if lo > hi || hi > uint64(len(m.values)) {
+		return nil, false
+	}
+	return m.values[lo:hi], true
+}
+
+// Len returns the number of keys in the map.
+func (m *Map) Len() int {
+	return int(m.nValues)
+}
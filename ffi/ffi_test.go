@@ -0,0 +1,186 @@
+package ffi_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+	"alon.kr/x/faststringmap/ffi"
+)
+
+var conformanceKeys = []string{"", "a", "ab", "abc", "b", "GET", "POST", "\x01", "\xfe\xff", "key with spaces"}
+
+var conformanceProbes = append([]string{"ac", "abcd", "c", "get", "\x00", "\xff", "\xfe", strings.Repeat("a", 300)}, conformanceKeys...)
+
+// conformanceMaps returns serialized maps of conformanceKeys, with each key
+// mapped to itself followed by "!", with and without fingerprints.
+func conformanceMaps(t *testing.T) map[string][]byte {
+	t.Helper()
+	entries := make([]faststringmap.MapEntry[string], len(conformanceKeys))
+	for i, k := range conformanceKeys {
+		entries[i] = faststringmap.MapEntry[string]{Key: k, Value: k + "!"}
+	}
+
+	maps := map[string][]byte{}
+	for name, opts := range map[string][]faststringmap.Option{
+		"plain":        nil,
+		"fingerprints": {faststringmap.WithFingerprints()},
+		"metadata":     {faststringmap.WithMetadata(map[string]string{"version": "1"})},
+	} {
+		m, err := faststringmap.New(entries, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := m.AppendBinary(nil, faststringmap.StringCodec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		maps[name] = data
+	}
+	return maps
+}
+
+func TestMap(t *testing.T) {
+	for name, data := range conformanceMaps(t) {
+		m, err := faststringmap.UnmarshalMap(data, faststringmap.StringCodec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fm, err := ffi.Open(data)
+		if err != nil {
+			t.Fatalf("%s: Open() error = %v", name, err)
+		}
+		if fm.Len() != len(conformanceKeys) {
+			t.Errorf("%s: Len() = %d want %d", name, fm.Len(), len(conformanceKeys))
+		}
+
+		for _, p := range conformanceProbes {
+			index := fm.Lookup([]byte(p))
+			if want := m.IndexString(p); index != want {
+				t.Errorf("%s: Lookup(%q) = %d want %d", name, p, index, want)
+			}
+			v, ok := fm.Value(index)
+			want, wantOK := m.AtIndex(index)
+			if ok != wantOK || string(v) != want {
+				t.Errorf("%s: Value(%d) = %q, %v want %q, %v", name, index, v, ok, want, wantOK)
+			}
+		}
+	}
+}
+
+func TestMapMalformed(t *testing.T) {
+	data := conformanceMaps(t)["fingerprints"]
+
+	for n := 0; n < len(data); n++ {
+		if _, err := ffi.Open(data[:n]); err == nil && n < 32 {
+			t.Errorf("Open() of %d bytes succeeded", n)
+		}
+	}
+
+	// lookups in corrupted data must not panic
+	for i := 32; i < len(data); i++ {
+		corrupt := bytes.Clone(data)
+		corrupt[i] ^= 0xff
+		m, err := ffi.Open(corrupt)
+		if err != nil {
+			continue
+		}
+		for _, p := range conformanceProbes {
+			m.Value(m.Lookup([]byte(p)))
+		}
+	}
+}
+
+// cDriver prints the index and the hex encoded value of every probe read
+// from its standard input, each preceded by its length as 4 bytes.
+const cDriver = `#include <stdio.h>
+#include <stdlib.h>
+#include "faststringmap.h"
+
+int main(int argc, char **argv) {
+	static uint8_t data[1 << 16], probe[1 << 16];
+	FILE *f = fopen(argv[1], "rb");
+	size_t len = fread(data, 1, sizeof data, f);
+	fsm_map m;
+	uint8_t n[4];
+
+	if (fsm_open(&m, data, len) != 0) {
+		printf("invalid\n");
+		return 0;
+	}
+	while (fread(n, 1, 4, stdin) == 4) {
+		size_t plen = fsm__u32(n), vlen, i;
+		const uint8_t *v;
+		uint32_t index;
+
+		if (fread(probe, 1, plen, stdin) != plen)
+			return 1;
+		index = fsm_lookup(&m, probe, plen);
+		printf("%u ", index);
+		if (fsm_value(&m, index, &v, &vlen) == 0)
+			for (i = 0; i < vlen; i++)
+				printf("%02x", v[i]);
+		printf("\n");
+	}
+	return 0;
+}
+`
+
+func TestCHeader(t *testing.T) {
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("no C compiler")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "faststringmap.h"), []byte(ffi.Header), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "driver.c"), []byte(cDriver), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	driver := filepath.Join(dir, "driver")
+	if out, err := exec.Command(cc, "-std=c99", "-Wall", "-Werror", "-o", driver, filepath.Join(dir, "driver.c")).CombinedOutput(); err != nil {
+		t.Fatalf("compiling the C driver: %v\n%s", err, out)
+	}
+
+	var input []byte
+	for _, p := range conformanceProbes {
+		input = binary.LittleEndian.AppendUint32(input, uint32(len(p)))
+		input = append(input, p...)
+	}
+
+	for name, data := range conformanceMaps(t) {
+		m, err := ffi.Open(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want strings.Builder
+		for _, p := range conformanceProbes {
+			index := m.Lookup([]byte(p))
+			v, _ := m.Value(index)
+			fmt.Fprintf(&want, "%d %s\n", index, hex.EncodeToString(v))
+		}
+
+		path := filepath.Join(dir, name+".fstm")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command(driver, path)
+		cmd.Stdin = bytes.NewReader(input)
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%s: running the C driver: %v", name, err)
+		}
+		if string(out) != want.String() {
+			t.Errorf("%s: C lookups give\n%s\nwant\n%s", name, out, want.String())
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package faststringmap
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// StructFields maps the names a struct type's fields are encoded under, such
+// as their JSON or CSV names, to the indices of the fields, for hand-written
+// decoders that dispatch on field names. The struct type is inspected once,
+// when the StructFields is constructed, and lookups never allocate.
+type StructFields struct {
+	m     Map[int]
+	names []string // encoded names, by field index
+}
+
+// NewStructFields constructs a StructFields for the struct type typ, reading
+// the encoded names of its fields from the struct tag key tag, such as "json"
+// or "csv", following the conventions of encoding/json: the name is the part
+// of the tag before any comma, a field without a name in its tag is encoded
+// under its Go name, and fields tagged "-" and unexported fields are skipped.
+// Embedded structs are not flattened. Field indices are those of
+// reflect.Type.Field.
+func NewStructFields(typ reflect.Type, tag string) (*StructFields, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("faststringmap: %s is not a struct type", typ)
+	}
+
+	f := &StructFields{names: make([]string, typ.NumField())}
+	entries := make([]MapEntry[int], 0, typ.NumField())
+	seen := make(map[string]int, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if j, dup := seen[name]; dup {
+			return nil, fmt.Errorf("faststringmap: fields %s and %s of %s are both named %q",
+				typ.Field(j).Name, field.Name, typ, name)
+		}
+		seen[name] = i
+		f.names[i] = name
+		entries = append(entries, MapEntry[int]{name, i})
+	}
+
+	f.m = NewMap(entries)
+	return f, nil
+}
+
+// FieldIndex returns the index of the field encoded under the supplied name.
+func (f *StructFields) FieldIndex(name []byte) (int, bool) {
+	return f.m.LookupBytes(name)
+}
+
+// Name returns the name the field at index i is encoded under, or "" if the
+// field is skipped.
+func (f *StructFields) Name(i int) string {
+	return f.names[i]
+}
@@ -0,0 +1,65 @@
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+type fieldsRecord struct {
+	ID       int    `json:"id,string" csv:"ID"`
+	Name     string `json:"name"`
+	Email    string `json:",omitempty"`
+	Password string `json:"-"`
+	internal int
+	Zip      string `json:"zip" csv:"-"`
+}
+
+func TestStructFields(t *testing.T) {
+	f, err := faststringmap.NewStructFields(reflect.TypeOf(fieldsRecord{}), "json")
+	if err != nil {
+		t.Fatalf("NewStructFields() error = %v", err)
+	}
+
+	for name, want := range map[string]int{"id": 0, "name": 1, "Email": 2, "zip": 5} {
+		if i, ok := f.FieldIndex([]byte(name)); !ok || i != want {
+			t.Errorf("FieldIndex(%s) = %d, %v want %d, true", name, i, ok, want)
+		}
+		if got := f.Name(want); got != name {
+			t.Errorf("Name(%d) = %q want %q", want, got, name)
+		}
+	}
+	for _, name := range []string{"ID", "Password", "-", "internal", ""} {
+		if i, ok := f.FieldIndex([]byte(name)); ok {
+			t.Errorf("FieldIndex(%s) = %d, expected not to be present", name, i)
+		}
+	}
+
+	csv, err := faststringmap.NewStructFields(reflect.TypeOf(fieldsRecord{}), "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i, ok := csv.FieldIndex([]byte("ID")); !ok || i != 0 {
+		t.Errorf("FieldIndex(ID) = %d, %v want 0, true", i, ok)
+	}
+
+	name := []byte("name")
+	if allocs := testing.AllocsPerRun(100, func() { f.FieldIndex(name) }); allocs != 0 {
+		t.Errorf("FieldIndex() allocates %v times per run want 0", allocs)
+	}
+}
+
+func TestStructFieldsErrors(t *testing.T) {
+	if _, err := faststringmap.NewStructFields(reflect.TypeOf(0), "json"); err == nil {
+		t.Errorf("NewStructFields(int) succeeded")
+	}
+
+	type clash struct {
+		A int `csv:"x"`
+		B int `csv:"x"`
+	}
+	if _, err := faststringmap.NewStructFields(reflect.TypeOf(clash{}), "csv"); err == nil {
+		t.Errorf("NewStructFields() of clashing names succeeded")
+	}
+}
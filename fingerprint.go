@@ -0,0 +1,77 @@
+package faststringmap
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+const fnvOffsetBasis = 2166136261
+
+// fingerprint returns an 8-bit fingerprint of the supplied key: its 32-bit
+// FNV-1a hash starting from seed, with all four bytes folded together.
+func fingerprint[S string | []byte](s S, seed uint32) byte {
+	h := seed
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return byte(h ^ h>>8 ^ h>>16 ^ h>>24)
+}
+
+// fingerprintSeed returns the hash state fingerprints start from for the
+// supplied salt, which is the FNV-1a state after hashing the salt, so salting
+// costs nothing per lookup. A zero salt gives the unsalted FNV-1a state.
+func fingerprintSeed(salt uint64) uint32 {
+	if salt == 0 {
+		return fnvOffsetBasis
+	}
+
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], salt)
+	h := uint32(fnvOffsetBasis)
+	for _, c := range b {
+		h ^= uint32(c)
+		h *= 16777619
+	}
+	return h
+}
+
+// randomSalt returns a random non-zero fingerprint salt.
+func randomSalt() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		binary.LittleEndian.PutUint64(b[:], uint64(time.Now().UnixNano()))
+	}
+	if salt := binary.LittleEndian.Uint64(b[:]); salt != 0 {
+		return salt
+	}
+	return 1
+}
+
+// verifyFingerprint returns index if the fingerprint of the value at index
+// matches fp, or 0 otherwise.
+func (m *Map[T]) verifyFingerprint(index Uint, fp byte) Uint {
+	if index-1 < Uint(len(m.fingerprints)) && m.fingerprints[index-1] != fp {
+		return 0
+	}
+	return index
+}
+
+// setFingerprints sets the fingerprints of the map and their salt. A nil
+// fingerprints leaves the map without fingerprints.
+func (m *Map[T]) setFingerprints(fingerprints []byte, salt uint64) {
+	if fingerprints != nil {
+		m.fingerprints = fingerprints
+		m.fingerprintSalt, m.fingerprintSeed = salt, fingerprintSeed(salt)
+	}
+}
+
+// FingerprintSalt returns the salt of the key fingerprints of the map, or 0
+// if the map has no fingerprints (see Builder.SetFingerprints).
+func (m *Map[T]) FingerprintSalt() uint64 {
+	if m == nil || m.fingerprints == nil {
+		return 0
+	}
+	return m.fingerprintSalt
+}
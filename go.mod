@@ -1,3 +1,3 @@
 module alon.kr/x/faststringmap
 
-go 1.18
+go 1.23
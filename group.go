@@ -0,0 +1,109 @@
+package faststringmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Group coordinates AtomicMaps holding related maps, such as terms,
+// synonyms and weights, so that they are replaced together. Maps are staged
+// in a GroupTx and committed at once, producing a new generation of the
+// group. Readers that need the maps of a single generation take a
+// GroupSnapshot and load each map from it with AtomicMap.LoadFrom. Loading
+// the AtomicMaps directly can briefly mix generations while a commit
+// stores them one by one.
+//
+// The maps of a group should only be replaced through transactions. The
+// zero value is an empty group at generation 0. A Group is safe for
+// concurrent use, and must not be copied after first use.
+type Group struct {
+	mu      sync.Mutex // serializes commits
+	current atomic.Pointer[GroupSnapshot]
+}
+
+// GroupSnapshot is a generation of the maps of a Group.
+type GroupSnapshot struct {
+	generation uint64
+	maps       map[any]any // from *AtomicMap[T] to the *Map[T] of the generation
+}
+
+// GroupTx stages replacements of maps of a Group, which take effect
+// together when committed. A GroupTx is not safe for concurrent use.
+type GroupTx struct {
+	g      *Group
+	staged map[any]stagedMap
+}
+
+type stagedMap struct {
+	m     any    // *Map[T]
+	store func() // stores m in its AtomicMap
+}
+
+// Snapshot returns the current generation of the maps of the group.
+func (g *Group) Snapshot() *GroupSnapshot {
+	if s := g.current.Load(); s != nil {
+		return s
+	}
+	return &GroupSnapshot{}
+}
+
+// Generation returns the number of the generation, which counts the
+// commits of the group.
+func (s *GroupSnapshot) Generation() uint64 {
+	return s.generation
+}
+
+// Begin starts a transaction replacing maps of the group.
+func (g *Group) Begin() *GroupTx {
+	return &GroupTx{g: g, staged: map[any]stagedMap{}}
+}
+
+// Stage stages the replacement of the map held by a with m, to take effect
+// when tx is committed. Staging a map again replaces the staged map.
+func (a *AtomicMap[T]) Stage(tx *GroupTx, m Map[T]) {
+	mp := &m
+	tx.staged[a] = stagedMap{m: mp, store: func() { a.v.Store(mp) }}
+}
+
+// Commit makes the staged maps the maps of a new generation of the group,
+// together with the maps of the previous generation that were not
+// replaced, and then stores each staged map in its AtomicMap. It returns
+// the new generation, or the current one if nothing was staged. The
+// transaction is empty afterwards, and can be reused.
+func (tx *GroupTx) Commit() uint64 {
+	g := tx.g
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	prev := g.Snapshot()
+	if len(tx.staged) == 0 {
+		return prev.generation
+	}
+
+	next := &GroupSnapshot{
+		generation: prev.generation + 1,
+		maps:       make(map[any]any, len(prev.maps)+len(tx.staged)),
+	}
+	for a, m := range prev.maps {
+		next.maps[a] = m
+	}
+	for a, s := range tx.staged {
+		next.maps[a] = s.m
+	}
+	g.current.Store(next)
+
+	for _, s := range tx.staged {
+		s.store()
+	}
+	clear(tx.staged)
+	return next.generation
+}
+
+// LoadFrom returns the map held by a in the generation s of its group. If
+// the map of a was never replaced through the group, it is loaded from a.
+func (a *AtomicMap[T]) LoadFrom(s *GroupSnapshot) *Map[T] {
+	if m, ok := s.maps[a]; ok {
+		return m.(*Map[T])
+	}
+	return a.Load()
+}
@@ -0,0 +1,68 @@
+package faststringmap_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestGroup(t *testing.T) {
+	var g faststringmap.Group
+	terms := faststringmap.NewAtomicMap(faststringmap.NewMap([]faststringmap.MapEntry[int]{{Key: "gen", Value: 0}}))
+	names := faststringmap.NewAtomicMap(faststringmap.NewMap([]faststringmap.MapEntry[string]{{Key: "gen", Value: "0"}}))
+
+	s := g.Snapshot()
+	if s.Generation() != 0 {
+		t.Errorf("Generation() = %d want 0", s.Generation())
+	}
+	if v, _ := terms.LoadFrom(s).LookupString("gen"); v != 0 {
+		t.Errorf("LoadFrom() of the initial map gives %d want 0", v)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				s := g.Snapshot()
+				term, _ := terms.LoadFrom(s).LookupString("gen")
+				name, _ := names.LoadFrom(s).LookupString("gen")
+				if strconv.Itoa(term) != name {
+					t.Errorf("generation %d mixes maps %d and %s", s.Generation(), term, name)
+					return
+				}
+			}
+		}()
+	}
+
+	tx := g.Begin()
+	for i := 1; i <= 100; i++ {
+		terms.Stage(tx, faststringmap.NewMap([]faststringmap.MapEntry[int]{{Key: "gen", Value: i}}))
+		names.Stage(tx, faststringmap.NewMap([]faststringmap.MapEntry[string]{{Key: "gen", Value: strconv.Itoa(i)}}))
+		if gen := tx.Commit(); gen != uint64(i) {
+			t.Errorf("Commit() = %d want %d", gen, i)
+		}
+	}
+	wg.Wait()
+
+	if gen := tx.Commit(); gen != 100 {
+		t.Errorf("empty Commit() = %d want 100", gen)
+	}
+	if v, _ := terms.LookupString("gen"); v != 100 {
+		t.Errorf("committed map holds %d want 100", v)
+	}
+
+	// maps not staged in a commit are carried over from the previous one
+	terms.Stage(tx, faststringmap.NewMap([]faststringmap.MapEntry[int]{{Key: "gen", Value: 101}}))
+	tx.Commit()
+	s = g.Snapshot()
+	if v, _ := names.LoadFrom(s).LookupString("gen"); v != "100" {
+		t.Errorf("LoadFrom() of a map not staged gives %q want 100", v)
+	}
+	if v, _ := terms.LoadFrom(s).LookupString("gen"); v != 101 {
+		t.Errorf("LoadFrom() of a staged map gives %d want 101", v)
+	}
+}
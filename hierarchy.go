@@ -0,0 +1,66 @@
+package faststringmap
+
+// LookupHierarchy returns the values of s and of all its ancestors in the
+// map, where the ancestors of s are its prefixes ending right before a sep
+// byte. For example, with sep '/', probing "a/b/c" returns the values of
+// "a", "a/b" and "a/b/c", in that order, skipping those not in the map.
+// This serves configuration inheritance and permission trees. It traverses
+// the map once, and allocates only the returned slice, which is nil if no
+// values were found. If the map was built with WithFold, upper case letters
+// of s are folded like the probes of LookupString.
+func (m *Map[T]) LookupHierarchy(s string, sep byte) []T {
+	return appendHierarchy(m, nil, s, sep)
+}
+
+// LookupHierarchyBytes looks up the supplied byte slice like LookupHierarchy.
+func (m *Map[T]) LookupHierarchyBytes(s []byte, sep byte) []T {
+	return appendHierarchy(m, nil, s, sep)
+}
+
+// AppendHierarchy appends the values LookupHierarchy returns for s to dst,
+// for callers reusing a slice across lookups.
+func (m *Map[T]) AppendHierarchy(dst []T, s string, sep byte) []T {
+	return appendHierarchy(m, dst, s, sep)
+}
+
+func appendHierarchy[T any, S string | []byte](m *Map[T], dst []T, s S, sep byte) []T {
+	if m == nil || len(m.store) == 0 {
+		return dst
+	}
+
+	h := m.fingerprintSeed // fingerprint of the folded prefix, computed on the way
+	bv := &m.store[0]
+	for i, n := 0, len(s); i < n; i++ {
+		b := s[i]
+		if b == sep {
+			dst = appendIndexValue(m, dst, bv.valueOffset, h)
+		}
+		if m.fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		h = (h ^ uint32(b)) * 16777619
+
+		if b < bv.nextOffset {
+			return dst
+		}
+		ni := b - bv.nextOffset
+		if ni >= bv.nextLen {
+			return dst
+		}
+		bv = &m.store[bv.nextLo+uint32(ni)]
+	}
+
+	return appendIndexValue(m, dst, bv.valueOffset, h)
+}
+
+// appendIndexValue appends the value at index to dst, if index is not 0
+// and the fingerprint matches the key hashed to h.
+func appendIndexValue[T any](m *Map[T], dst []T, index Uint, h uint32) []T {
+	if index != 0 && m.fingerprints != nil {
+		index = m.verifyFingerprint(index, byte(h^h>>8^h>>16^h>>24))
+	}
+	if t, ok := m.AtIndex(index); ok {
+		dst = append(dst, t)
+	}
+	return dst
+}
@@ -0,0 +1,53 @@
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestLookupHierarchy(t *testing.T) {
+	m, err := faststringmap.New([]faststringmap.MapEntry[string]{
+		{"a", "A"},
+		{"a/b", "AB"},
+		{"a/b/c", "ABC"},
+		{"a/bc", "ABC'"},
+		{"x/y", "XY"},
+	}, faststringmap.WithFingerprints())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		probe string
+		want  []string
+	}{
+		{"a/b/c", []string{"A", "AB", "ABC"}},
+		{"a/b/c/d", []string{"A", "AB", "ABC"}},
+		{"a/b/cd", []string{"A", "AB"}},
+		{"a/bc", []string{"A", "ABC'"}},
+		{"a/bcd/e", []string{"A"}},
+		{"x/y/z", []string{"XY"}},
+		{"x", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := m.LookupHierarchy(tt.probe, '/'); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LookupHierarchy(%q) = %q want %q", tt.probe, got, tt.want)
+		}
+		if got := m.LookupHierarchyBytes([]byte(tt.probe), '/'); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LookupHierarchyBytes(%q) = %q want %q", tt.probe, got, tt.want)
+		}
+	}
+
+	buf := make([]string, 0, 4)
+	allocs := testing.AllocsPerRun(100, func() { buf = m.AppendHierarchy(buf[:0], "a/b/c", '/') })
+	if allocs != 0 || len(buf) != 3 {
+		t.Errorf("AppendHierarchy allocates %v times and found %d values", allocs, len(buf))
+	}
+
+	if got := (*faststringmap.Map[string])(nil).LookupHierarchy("a", '/'); got != nil {
+		t.Errorf("nil.LookupHierarchy() = %q want nil", got)
+	}
+}
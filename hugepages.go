@@ -0,0 +1,31 @@
+package faststringmap
+
+import "fmt"
+
+// AdviseHugePages advises the operating system to back the node store of
+// the map with transparent huge pages, which reduces TLB misses of lookups
+// in maps of hundreds of megabytes or more. It is only advice: the kernel
+// may ignore it, depending on its configuration (see
+// /sys/kernel/mm/transparent_hugepage/enabled), and it applies only to
+// whole pages in the store. It is supported on Linux; elsewhere, and in
+// the minimal profile, it returns an error wrapping errors.ErrUnsupported.
+func (m *Map[T]) AdviseHugePages() error {
+	if m == nil {
+		return nil
+	}
+	if err := adviseHugePages(storeBytes(m.store), false); err != nil {
+		return fmt.Errorf("faststringmap: advising huge pages: %w", err)
+	}
+	return nil
+}
+
+// AdviseHugePages advises the operating system to back the whole mapped
+// file with transparent huge pages, like Map.AdviseHugePages, and to start
+// reading it in, which unlike Map.Warm does not wait for the reads. Huge
+// pages of mapped files need a kernel built with CONFIG_READ_ONLY_THP_FOR_FS.
+func (m *MappedMap[T]) AdviseHugePages() error {
+	if err := adviseHugePages(m.data, true); err != nil {
+		return fmt.Errorf("faststringmap: advising huge pages: %w", err)
+	}
+	return nil
+}
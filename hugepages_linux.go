@@ -0,0 +1,33 @@
+//go:build linux && !faststringmap_minimal && !tinygo
+
+package faststringmap
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// adviseHugePages applies MADV_HUGEPAGE, and MADV_WILLNEED if willNeed is
+// set, to the whole pages in b.
+func adviseHugePages(b []byte, willNeed bool) error {
+	if len(b) == 0 {
+		return nil
+	}
+	page := uintptr(os.Getpagesize())
+	start := uintptr(unsafe.Pointer(&b[0]))
+	lo := (start+page-1)/page*page - start
+	hi := (start+uintptr(len(b)))/page*page - start
+	if lo >= hi {
+		return nil
+	}
+	b = b[lo:hi]
+
+	if err := syscall.Madvise(b, syscall.MADV_HUGEPAGE); err != nil {
+		return err
+	}
+	if willNeed {
+		return syscall.Madvise(b, syscall.MADV_WILLNEED)
+	}
+	return nil
+}
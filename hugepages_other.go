@@ -0,0 +1,10 @@
+//go:build !linux || faststringmap_minimal || tinygo
+
+package faststringmap
+
+import "errors"
+
+// adviseHugePages fails, as transparent huge pages are specific to Linux.
+func adviseHugePages(b []byte, willNeed bool) error {
+	return errors.ErrUnsupported
+}
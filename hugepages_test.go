@@ -0,0 +1,52 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+// checkAdvice checks the error of AdviseHugePages, which may fail with
+// EINVAL on Linux kernels built without transparent huge pages.
+func checkAdvice(t *testing.T, name string, err error) {
+	t.Helper()
+	if runtime.GOOS != "linux" || minimalProfile {
+		if !errors.Is(err, errors.ErrUnsupported) {
+			t.Errorf("%s.AdviseHugePages() error = %v want ErrUnsupported", name, err)
+		}
+		return
+	}
+	if err != nil && !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("%s.AdviseHugePages() error = %v", name, err)
+	}
+}
+
+func TestAdviseHugePages(t *testing.T) {
+	entries := randomSmallStrings(100000, 8)
+	m := faststringmap.NewMap(entries)
+	checkAdvice(t, "Map", m.AdviseHugePages())
+	checkEntries(t, &m, entries)
+
+	path := filepath.Join(t.TempDir(), "random.fstm")
+	if err := os.WriteFile(path, serialize(t, m), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mm, err := faststringmap.OpenMapped[uint32](path, faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mm.Close()
+	checkAdvice(t, "MappedMap", mm.AdviseHugePages())
+	checkAdvice(t, "MappedMap.Map", mm.Map.AdviseHugePages())
+	checkEntries(t, &mm.Map, entries)
+
+	var nilMap *faststringmap.Map[uint32]
+	if err := nilMap.AdviseHugePages(); err != nil {
+		t.Errorf("nil Map.AdviseHugePages() error = %v", err)
+	}
+}
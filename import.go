@@ -0,0 +1,70 @@
+package faststringmap
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotSorted is returned when importing records that are not in strictly
+// ascending key order.
+var ErrNotSorted = errors.New("faststringmap: keys not in ascending order")
+
+// ImportSortedKeys[T] builds a Map from a file of records sorted by key, as
+// produced by static dictionary tools such as marisa-trie or FST builders,
+// which share the ascending byte order of keys used by this package. Each
+// record is a key, followed by the separator byte sep, followed by exactly
+// valueWidth bytes of value, which are decoded using decode. Keys must not
+// contain sep. For example, a sorted word list, one word per line, is read
+// with a sep of '\n' and a valueWidth of 0, and records of NUL terminated keys
+// followed by 4 byte big-endian values are read with
+//
+//	m, err := faststringmap.ImportSortedKeys(r, 0, 4, func(b []byte) (uint32, error) {
+//		return binary.BigEndian.Uint32(b), nil
+//	})
+//
+// As the keys are sorted already, building the map skips sorting them.
+// ImportSortedKeys returns ErrNotSorted if a key is not greater than the
+// previous key, which also rules out duplicate keys.
+func ImportSortedKeys[T any](r io.Reader, sep byte, valueWidth int, decode func([]byte) (T, error)) (Map[T], error) {
+	br := bufio.NewReader(r)
+	b := Builder[T]{presorted: true}
+	value := make([]byte, valueWidth)
+
+	var prev []byte
+	for record := 1; ; record++ {
+		key, err := br.ReadBytes(sep)
+		if err == io.EOF && len(key) == 0 {
+			break
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return Map[T]{}, fmt.Errorf("faststringmap: record %d: %w", record, err)
+		}
+		key = key[:len(key)-1]
+
+		if _, err := io.ReadFull(br, value); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return Map[T]{}, fmt.Errorf("faststringmap: record %d: %w", record, err)
+		}
+
+		if record > 1 && bytes.Compare(prev, key) >= 0 {
+			return Map[T]{}, fmt.Errorf("faststringmap: record %d: %w", record, ErrNotSorted)
+		}
+		prev = key
+
+		v, err := decode(value)
+		if err != nil {
+			return Map[T]{}, fmt.Errorf("faststringmap: record %d: %w", record, err)
+		}
+		b.Add(string(key), v)
+	}
+
+	return b.Build(), nil
+}
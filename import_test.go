@@ -0,0 +1,65 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestImportSortedKeys(t *testing.T) {
+	entries := randomSmallStrings(1000, 8)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	var file bytes.Buffer
+	for _, e := range entries {
+		file.WriteString(e.Key)
+		file.WriteByte(0xff) // random keys never contain 0xff
+		binary.Write(&file, binary.BigEndian, e.Value)
+	}
+
+	m, err := faststringmap.ImportSortedKeys(&file, 0xff, 4, func(b []byte) (uint32, error) {
+		return binary.BigEndian.Uint32(b), nil
+	})
+	if err != nil {
+		t.Fatalf("ImportSortedKeys() error = %v", err)
+	}
+	checkEntries(t, &m, entries)
+}
+
+func TestImportSortedWordList(t *testing.T) {
+	m, err := faststringmap.ImportSortedKeys(strings.NewReader("apple\nbanana\ncherry\n"), '\n', 0, func([]byte) (bool, error) {
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("ImportSortedKeys() error = %v", err)
+	}
+	checkEntries(t, &m, []faststringmap.MapEntry[bool]{{"apple", true}, {"banana", true}, {"cherry", true}})
+}
+
+func TestImportSortedKeysErrors(t *testing.T) {
+	member := func([]byte) (bool, error) { return true, nil }
+	for _, tc := range []struct {
+		file string
+		want error
+	}{
+		{"b\na\n", faststringmap.ErrNotSorted},
+		{"a\na\n", faststringmap.ErrNotSorted},
+		{"a\nb", io.ErrUnexpectedEOF},
+	} {
+		_, err := faststringmap.ImportSortedKeys(strings.NewReader(tc.file), '\n', 0, member)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("ImportSortedKeys(%q) error = %v want %v", tc.file, err, tc.want)
+		}
+	}
+
+	_, err := faststringmap.ImportSortedKeys(strings.NewReader("a\x00\x01"), 0, 2, member)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ImportSortedKeys(truncated value) error = %v want io.ErrUnexpectedEOF", err)
+	}
+}
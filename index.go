@@ -0,0 +1,40 @@
+package faststringmap
+
+// Index[T] refers to a value in a Map[T], as returned by Find. Unlike the
+// raw indices returned by IndexString and IndexBytes, where 0 means absent,
+// an Index can not be mistaken for a valid slot: it must be checked with
+// Valid, or resolved with Value, which reports whether the key was found.
+// The zero Index is not valid.
+type Index[T any] struct {
+	offset Uint // index+1 of the value, or 0 if absent
+}
+
+// Valid reports whether the index refers to a value, that is whether the
+// key it was found for is present.
+func (i Index[T]) Valid() bool {
+	return i.offset != 0
+}
+
+// Value returns the value the index refers to in m, which must be the map
+// the index was found in. ok is false if the index is not valid.
+func (i Index[T]) Value(m *Map[T]) (t T, ok bool) {
+	return m.AtIndex(i.offset)
+}
+
+// Raw returns the index in the raw form used by IndexString, IndexBytes
+// and AtIndex, where 0 means absent.
+func (i Index[T]) Raw() Uint {
+	return i.offset
+}
+
+// Find returns the index of the value in the map for the supplied string.
+// The index is not valid if the string is not present.
+func (m *Map[T]) Find(s string) Index[T] {
+	return Index[T]{m.IndexString(s)}
+}
+
+// FindBytes returns the index of the value in the map for the supplied
+// byte slice, like Find. It never allocates and does not retain s.
+func (m *Map[T]) FindBytes(s []byte) Index[T] {
+	return Index[T]{m.IndexBytes(s)}
+}
@@ -0,0 +1,35 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestFind(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"zero", 0}, {"one", 1}})
+
+	for _, e := range []faststringmap.MapEntry[int]{{"zero", 0}, {"one", 1}} {
+		i := m.Find(e.Key)
+		if !i.Valid() {
+			t.Fatalf("Find(%q) is not valid", e.Key)
+		}
+		if v, ok := i.Value(&m); !ok || v != e.Value {
+			t.Errorf("Find(%q).Value() = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+		if i != m.FindBytes([]byte(e.Key)) || i.Raw() != m.IndexString(e.Key) {
+			t.Errorf("Find(%q) disagrees with FindBytes and IndexString", e.Key)
+		}
+	}
+
+	missing := m.Find("two")
+	if missing.Valid() {
+		t.Error("Find(two) is valid")
+	}
+	if v, ok := missing.Value(&m); ok || v != 0 {
+		t.Errorf("Find(two).Value() = %v, %v want 0, false", v, ok)
+	}
+	if (faststringmap.Index[int]{}).Valid() {
+		t.Error("zero Index is valid")
+	}
+}
@@ -0,0 +1,138 @@
+package faststringmap
+
+// InlineMap[T] is a fast read only map from string to generic type T, which
+// stores small values directly in the node accepting their key, like the
+// original uint32 store of this package, saving the indirection through a
+// values slice, and a cache miss, on every successful lookup. Values of
+// types of at most 8 bytes that hold no pointers are inlined. For other
+// types, and in the minimal profile built for TinyGo, where values are
+// never inlined, an InlineMap is a plain Map with no overhead.
+//
+// Nodes holding values take 16 bytes instead of the 12 of Map, so inlining
+// costs 4 bytes per node and saves unsafe.Sizeof(T) bytes per key. It only
+// saves memory with fewer than unsafe.Sizeof(T)/4 nodes per key, at most 2,
+// which dense sets of short keys reach but sets of longer keys do not:
+// random keys of up to 8 bytes take about 13 nodes per key. Past that
+// point, inlining trades memory for one cache miss less per hit; the Stats
+// of a Map of the keys tell which side of it they are on.
+type InlineMap[T any] struct {
+	store []inlineNode // nil if values are not inlined
+	m     Map[T]       // the map, if values are not inlined
+
+	keyTransform func([]byte) []byte
+	fold         bool
+}
+
+type inlineNode struct {
+	nextLo     Uint   // index in store of next node
+	nextLen    byte   // number of nodes in store used for next possible bytes
+	nextOffset byte   // offset from zero byte value of first element of range of nodes
+	accepting  bool   // whether a key ends at the node
+	value      uint64 // the value of the key ending at the node
+}
+
+// NewInlineMap[T] constructs a new InlineMap from the provided map entries,
+// configured by opts like New. If values are inlined, options for
+// fingerprints and retained keys have no effect: lookups in the nodes
+// always compare every byte of the probe, and can not return keys. The
+// entries slice is not modified.
+func NewInlineMap[T any](entries []MapEntry[T], opts ...Option) (InlineMap[T], error) {
+	m, err := New(entries, opts...)
+	if err != nil {
+		return InlineMap[T]{}, err
+	}
+	if !inlinable[T]() {
+		return InlineMap[T]{m: m}, nil
+	}
+
+	im := InlineMap[T]{
+		store:        make([]inlineNode, len(m.store)),
+		keyTransform: m.keyTransform,
+		fold:         m.fold,
+	}
+	for i, n := range m.store {
+		in := &im.store[i]
+		in.nextLo, in.nextLen, in.nextOffset = n.nextLo, n.nextLen, n.nextOffset
+		if n.valueOffset != 0 {
+			in.accepting = true
+			setInline(&in.value, m.values[n.valueOffset-1])
+		}
+	}
+	return im, nil
+}
+
+// Inlined reports whether the values of the map are stored in its nodes.
+func (im *InlineMap[T]) Inlined() bool {
+	return im.store != nil
+}
+
+// LookupString looks up the supplied string in the map.
+func (im *InlineMap[T]) LookupString(s string) (t T, ok bool) {
+	if im.store == nil {
+		return im.m.LookupString(s)
+	}
+	if im.keyTransform != nil {
+		return im.LookupBytes(stringBytes(s))
+	}
+	return im.at(inlineNodeOf(im, s))
+}
+
+// LookupBytes looks up the supplied byte slice in the map. It never
+// allocates and does not retain s, unless the map has a key transform that
+// does.
+func (im *InlineMap[T]) LookupBytes(s []byte) (t T, ok bool) {
+	if im.store == nil {
+		return im.m.LookupBytes(s)
+	}
+	if im.keyTransform != nil {
+		s = im.keyTransform(s)
+	}
+	return im.at(inlineNodeOf(im, s))
+}
+
+func (im *InlineMap[T]) at(node *inlineNode) (t T, ok bool) {
+	if node == nil || !node.accepting {
+		return t, false
+	}
+	return getInline[T](&node.value), true
+}
+
+// inlineNodeOf returns the node reached by the bytes of s, or nil if there
+// is none.
+func inlineNodeOf[T any, S string | []byte](im *InlineMap[T], s S) *inlineNode {
+	if len(im.store) == 0 {
+		return nil
+	}
+
+	if im.fold {
+		return inlineNodeFold(im, s)
+	}
+
+	bv := &im.store[0]
+	for i, n := 0, len(s); i < n; i++ {
+		ni := s[i] - bv.nextOffset // bytes below nextOffset wrap around past nextLen
+		if ni >= bv.nextLen {
+			return nil
+		}
+		bv = &im.store[bv.nextLo+uint32(ni)]
+	}
+	return bv
+}
+
+// inlineNodeFold is like inlineNodeOf, with ASCII upper case letters of
+// s folded to lower case.
+func inlineNodeFold[T any, S string | []byte](im *InlineMap[T], s S) *inlineNode {
+	bv := &im.store[0]
+	for i, n := 0, len(s); i < n; i++ {
+		b := s[i]
+		if 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		ni := b - bv.nextOffset
+		if ni >= bv.nextLen {
+			return nil
+		}
+		bv = &im.store[bv.nextLo+uint32(ni)]
+	}
+	return bv
+}
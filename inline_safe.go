@@ -0,0 +1,17 @@
+//go:build faststringmap_minimal || tinygo
+
+package faststringmap
+
+// inlinable reports that values are never stored in nodes in the minimal
+// profile, which would need unsafe.
+func inlinable[T any]() bool {
+	return false
+}
+
+func setInline[T any](dst *uint64, v T) {
+	panic("faststringmap: values are not inlined in the minimal profile")
+}
+
+func getInline[T any](src *uint64) T {
+	panic("faststringmap: values are not inlined in the minimal profile")
+}
@@ -0,0 +1,110 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestInlineMap(t *testing.T) {
+	entries := randomSmallStrings(1024, 8)
+	im, err := faststringmap.NewInlineMap(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !im.Inlined() && !minimalProfile {
+		t.Error("uint32 values are not inlined")
+	}
+	for _, e := range entries {
+		if v, ok := im.LookupString(e.Key); !ok || v != e.Value {
+			t.Errorf("LookupString(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+		if v, ok := im.LookupBytes([]byte(e.Key)); !ok || v != e.Value {
+			t.Errorf("LookupBytes(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+	}
+	if v, ok := im.LookupString("\x01"); ok {
+		t.Errorf("LookupString(missing) = %v, true", v)
+	}
+
+	type pair struct{ a, b int32 }
+	pairs, err := faststringmap.NewInlineMap([]faststringmap.MapEntry[pair]{{"x", pair{1, -2}}, {"y", pair{3, 4}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := pairs.LookupString("x"); !ok || v != (pair{1, -2}) || pairs.Inlined() == minimalProfile {
+		t.Errorf("LookupString(x) = %v, %v with Inlined() %v", v, ok, pairs.Inlined())
+	}
+
+	// values holding pointers, or larger than a node can hold, are kept
+	// out of the nodes
+	strs, err := faststringmap.NewInlineMap([]faststringmap.MapEntry[string]{{"a", "A"}, {"b", "B"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := strs.LookupString("b"); !ok || v != "B" || strs.Inlined() {
+		t.Errorf("LookupString(b) = %q, %v with Inlined() %v", v, ok, strs.Inlined())
+	}
+	wide, err := faststringmap.NewInlineMap([]faststringmap.MapEntry[[3]uint32]{{"a", [3]uint32{1, 2, 3}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := wide.LookupString("a"); !ok || v != [3]uint32{1, 2, 3} || wide.Inlined() {
+		t.Errorf("LookupString(a) = %v, %v with Inlined() %v", v, ok, wide.Inlined())
+	}
+}
+
+func TestInlineMapOptions(t *testing.T) {
+	im, err := faststringmap.NewInlineMap([]faststringmap.MapEntry[int]{{"Alpha", 1}, {" beta", 2}},
+		faststringmap.WithFold(), faststringmap.WithKeyTransform(func(b []byte) []byte { return []byte(strings.TrimSpace(string(b))) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for probe, want := range map[string]int{"ALPHA": 1, " alpha ": 1, "Beta": 2} {
+		if v, ok := im.LookupString(probe); !ok || v != want {
+			t.Errorf("LookupString(%q) = %v, %v want %v, true", probe, v, ok, want)
+		}
+	}
+
+	// values that are not inlined are looked up in a plain Map
+	strs, err := faststringmap.NewInlineMap([]faststringmap.MapEntry[string]{{"Alpha", "A"}, {" beta", "B"}},
+		faststringmap.WithFold(), faststringmap.WithKeyTransform(bytes.TrimSpace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for probe, want := range map[string]string{"ALPHA": "A", " alpha ": "A", "Beta": "B"} {
+		if v, ok := strs.LookupBytes([]byte(probe)); !ok || v != want {
+			t.Errorf("LookupBytes(%q) = %q, %v want %q, true", probe, v, ok, want)
+		}
+	}
+}
+
+func BenchmarkInlineMap(b *testing.B) {
+	m, keys := typicalCodeStrings(nStrsBench)
+	fm := faststringmap.FromMap(m)
+	entries := make([]faststringmap.MapEntry[uint32], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, faststringmap.MapEntry[uint32]{Key: k, Value: v})
+	}
+	im, err := faststringmap.NewInlineMap(entries)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Map", func(b *testing.B) {
+		for bi := 0; bi < b.N; bi++ {
+			for _, k := range keys {
+				fm.LookupString(k)
+			}
+		}
+	})
+	b.Run("InlineMap", func(b *testing.B) {
+		for bi := 0; bi < b.N; bi++ {
+			for _, k := range keys {
+				im.LookupString(k)
+			}
+		}
+	})
+}
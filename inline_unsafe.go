@@ -0,0 +1,49 @@
+//go:build !faststringmap_minimal && !tinygo
+
+package faststringmap
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// inlinable reports whether values of type T can be stored in the value
+// field of a node: they must fit in it, and must not hold pointers, which
+// the garbage collector would not see there.
+func inlinable[T any]() bool {
+	var zero T
+	if unsafe.Sizeof(zero) > unsafe.Sizeof(uint64(0)) {
+		return false
+	}
+	return !hasPointers(reflect.TypeOf(&zero).Elem())
+}
+
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64:
+		return false
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// setInline stores v in the value field of a node, for inlinable types.
+func setInline[T any](dst *uint64, v T) {
+	*(*T)(unsafe.Pointer(dst)) = v
+}
+
+// getInline returns the value stored in the value field of a node by
+// setInline.
+func getInline[T any](src *uint64) T {
+	return *(*T)(unsafe.Pointer(src))
+}
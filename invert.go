@@ -0,0 +1,23 @@
+package faststringmap
+
+// Invert[T] builds the reverse map of m, from each of its values to the key
+// associated with it, in a single traversal of m. It serves tables kept in
+// both directions, such as codes and names. opts configure the built map;
+// values associated with more than one key are duplicate keys of the
+// reverse map, reported as an error unless WithDuplicates selects the
+// smallest (DuplicatesKeepFirst) or the largest (DuplicatesKeepLast) key.
+func Invert[T ~string](m *Map[T], opts ...Option) (Map[string], error) {
+	return InvertFunc(m, func(v T) string { return string(v) }, opts...)
+}
+
+// InvertFunc[T] is like Invert, for maps with values of any type, using
+// key to convert each value to a key of the reverse map.
+func InvertFunc[T any](m *Map[T], key func(T) string, opts ...Option) (Map[string], error) {
+	entries := make([]MapEntry[string], 0, m.lenOrZero())
+	m.walk(func(k []byte, index Uint) bool {
+		v, _ := m.AtIndex(index)
+		entries = append(entries, MapEntry[string]{key(v), string(k)})
+		return true
+	})
+	return New(entries, opts...)
+}
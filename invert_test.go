@@ -0,0 +1,66 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestInvert(t *testing.T) {
+	codes := faststringmap.NewMap([]faststringmap.MapEntry[string]{
+		{"EUR", "Euro"},
+		{"GBP", "Pound sterling"},
+		{"USD", "US dollar"},
+	})
+	names, err := faststringmap.Invert(&codes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Euro", "Pound sterling", "US dollar"} {
+		code, ok := names.LookupString(name)
+		if !ok {
+			t.Fatalf("LookupString(%q) not found", name)
+		}
+		if back, _ := codes.LookupString(code); back != name {
+			t.Errorf("round trip of %q gives %q", name, back)
+		}
+	}
+
+	colors := faststringmap.NewMap(colorEntries)
+	if _, err := faststringmap.Invert(&colors); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("Invert() of duplicate values error = %v want ErrDuplicateKey", err)
+	}
+	for policy, want := range map[faststringmap.DuplicatePolicy]string{
+		faststringmap.DuplicatesKeepFirst: "blue",
+		faststringmap.DuplicatesKeepLast:  "green",
+	} {
+		inv, err := faststringmap.Invert(&colors, faststringmap.WithDuplicates(policy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := inv.LookupString("cool"); got != want {
+			t.Errorf("policy %d: LookupString(cool) = %q want %q", policy, got, want)
+		}
+	}
+}
+
+func TestInvertFunc(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"one", 1}, {"two", 2}, {"three", 3}})
+	inv, err := faststringmap.InvertFunc(&m, strconv.Itoa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := inv.LookupString("3"); !ok || got != "three" {
+		t.Errorf("LookupString(3) = %q, %v want three, true", got, ok)
+	}
+
+	empty, err := faststringmap.InvertFunc[int](nil, strconv.Itoa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := empty.LookupString(""); ok {
+		t.Error("inverse of a nil map is not empty")
+	}
+}
@@ -0,0 +1,239 @@
+package faststringmap
+
+import "iter"
+
+// IndicesUnderPrefix returns an iterator over the indices of the values of
+// all keys starting with p, including p itself, in ascending key order.
+// Indices are stable for a given map and dense in 1..Len, so they can
+// address bitmaps or bitsets scoped to a prefix without materializing any
+// keys. Use AtIndex to get the values. The prefix is folded or transformed
+// like the probes of LookupString, if the map was built with WithFold or
+// WithKeyTransform.
+func (m *Map[T]) IndicesUnderPrefix(p string) iter.Seq[Uint] {
+	return func(yield func(Uint) bool) {
+		node := m.prefixNode(p)
+		if node != nil {
+			m.yieldIndices(node, yield)
+		}
+	}
+}
+
+// prefixNode returns the node reached by p, canonicalized like the probes
+// of IndexString, or nil if no key starts with p.
+func (m *Map[T]) prefixNode(p string) *mapInternalNode {
+	i, ok := m.prefixIndex(p)
+	if !ok {
+		return nil
+	}
+	return &m.store[i]
+}
+
+// prefixIndex returns the index in the node store of the node reached by p,
+// canonicalized like the probes of IndexString, if some key starts with p.
+func (m *Map[T]) prefixIndex(p string) (Uint, bool) {
+	if m != nil && m.keyTransform != nil {
+		return prefixIndexOf(m, m.keyTransform(stringBytes(p)))
+	}
+	return prefixIndexOf(m, p)
+}
+
+// prefixIndexOf returns the index in the node store of the node reached by
+// the bytes of s, transformed by the caller if need be, with upper case
+// letters folded if the map folds them, if some key starts with s.
+func prefixIndexOf[T any, S string | []byte](m *Map[T], s S) (Uint, bool) {
+	if m == nil || len(m.store) == 0 {
+		return 0, false
+	}
+
+	i := Uint(0)
+	for j := 0; j < len(s); j++ {
+		b := s[j]
+		if m.fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		bv := &m.store[i]
+		ni := b - bv.nextOffset // bytes below nextOffset wrap around past nextLen
+		if ni >= bv.nextLen {
+			return 0, false
+		}
+		i = bv.nextLo + Uint(ni)
+	}
+	return i, m.store[i].valueOffset != 0 || m.store[i].nextLen != 0 // not a wasted slot
+}
+
+// prefixStart returns the node reached by p like prefixNode, and a key
+// buffer holding p as stored in the map, for walks of the keys under it.
+func (m *Map[T]) prefixStart(p string) (*mapInternalNode, []byte) {
+	p = m.canonicalKey(p)
+	i, ok := prefixIndexOf(m, p)
+	if !ok {
+		return nil, nil
+	}
+	return &m.store[i], m.keyBuffer(p)
+}
+
+// canonicalKey returns key transformed and folded like the probes of
+// IndexString, as it would be stored in the map. It only allocates if the
+// map has a key transform, or key has upper case letters to fold.
+func (m *Map[T]) canonicalKey(key string) string {
+	if m == nil {
+		return key
+	}
+	if m.keyTransform != nil {
+		key = string(m.keyTransform(stringBytes(key)))
+	}
+	if m.fold {
+		key = foldASCII(key)
+	}
+	return key
+}
+
+// All returns an iterator over the keys and values of the map, in
+// ascending byte order of keys. Keys are as stored, so they are canonical
+// if the map was built with WithFold or WithKeyTransform.
+func (m *Map[T]) All() iter.Seq2[string, T] {
+	return m.Prefix("")
+}
+
+// Prefix returns an iterator over the keys starting with p, including p
+// itself, and their values, in ascending byte order of keys. Like
+// IndicesUnderPrefix, p is folded or transformed like a probe, and keys are
+// as stored.
+func (m *Map[T]) Prefix(p string) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		node, key := m.prefixStart(p)
+		if node == nil {
+			return
+		}
+		m.walkNode(node, key, func(key []byte, index Uint) bool {
+			t, _ := m.AtIndex(index)
+			return yield(string(key), t)
+		})
+	}
+}
+
+// AllFrom returns an iterator over the keys greater than or equal to start
+// and their values, in ascending byte order of keys, like All. Paginated
+// listings resume from the key after the last one of the previous page
+// without walking over the keys before it. Like IndicesUnderPrefix, start
+// is folded or transformed like a probe.
+func (m *Map[T]) AllFrom(start string) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		if m == nil || len(m.store) == 0 {
+			return
+		}
+		m.walkFrom(m.canonicalKey(start), func(key []byte, index Uint) bool {
+			t, _ := m.AtIndex(index)
+			return yield(string(key), t)
+		})
+	}
+}
+
+// walkFrom is like walk, skipping the keys less than start. It returns
+// false if fn did.
+func (m *Map[T]) walkFrom(start string, fn func([]byte, Uint) bool) bool {
+	key := m.keyBuffer("")
+	var local [walkStackSize]walkFrame
+	stack := local[:0]
+
+	// descend along start, leaving on the stack the children of every
+	// node on the way that come after the byte of start
+	node := &m.store[0]
+	for j := 0; ; j++ {
+		if j == len(start) {
+			// the key of node is start
+			if node.valueOffset != 0 && !fn(key, node.valueOffset) {
+				return false
+			}
+			stack = append(stack, walkFrame{node: node})
+			break
+		}
+
+		b := start[j]
+		if b < node.nextOffset {
+			stack = append(stack, walkFrame{node: node}) // all children come after b
+			break
+		}
+		ni := Uint(b - node.nextOffset)
+		if ni >= Uint(node.nextLen) {
+			break // all children come before b
+		}
+		stack = append(stack, walkFrame{node, ni + 1})
+		next := &m.store[node.nextLo+ni]
+		if next.valueOffset == 0 && next.nextLen == 0 {
+			break // not a valid next byte
+		}
+		// the key of node itself is a proper prefix of start, so less than it
+		node = next
+		key = append(key, b)
+	}
+	return m.walkStack(stack, key, 0, fn)
+}
+
+// AllDesc returns an iterator over the keys and values of the map, like
+// All, in descending byte order of keys.
+func (m *Map[T]) AllDesc() iter.Seq2[string, T] {
+	return m.PrefixDesc("")
+}
+
+// PrefixDesc returns an iterator over the keys starting with p and their
+// values, like Prefix, in descending byte order of keys, such as the latest
+// version first for keys ending in versions.
+func (m *Map[T]) PrefixDesc(p string) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		node, key := m.prefixStart(p)
+		if node == nil {
+			return
+		}
+		m.walkNodeDesc(node, key, func(key []byte, index Uint) bool {
+			t, _ := m.AtIndex(index)
+			return yield(string(key), t)
+		})
+	}
+}
+
+// Keys returns an iterator over the keys of the map, in ascending byte
+// order, like All.
+func (m *Map[T]) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		m.walk(func(key []byte, _ Uint) bool {
+			return yield(string(key))
+		})
+	}
+}
+
+// Walk calls fn for every key of the map and its value, in ascending byte
+// order of keys, until fn returns false. Unlike All, it does not allocate
+// a string per key: key is a buffer reused between calls, which fn must
+// not retain or modify. A walk allocates at most a buffer for keys and, for
+// keys longer than 64 bytes, a stack of the nodes on the way, however many
+// keys it visits.
+func (m *Map[T]) Walk(fn func(key []byte, t T) bool) {
+	m.WalkPrefix("", fn)
+}
+
+// WalkPrefix is like Walk, for the keys starting with p, including p
+// itself. Like IndicesUnderPrefix, p is folded or transformed like a probe.
+func (m *Map[T]) WalkPrefix(p string, fn func(key []byte, t T) bool) {
+	node, key := m.prefixStart(p)
+	if node == nil {
+		return
+	}
+	m.walkNode(node, key, func(key []byte, index Uint) bool {
+		t, _ := m.AtIndex(index)
+		return fn(key, t)
+	})
+}
+
+// yieldIndices yields the indices of the values of node and of all its
+// descendants, in ascending key order. It returns false if yield did.
+func (m *Map[T]) yieldIndices(node *mapInternalNode, yield func(Uint) bool) bool {
+	// the values of the keys of the subtree are contiguous
+	first, last := m.subtreeRange(node)
+	for i := first; i <= last; i++ {
+		if !yield(i) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,286 @@
+package faststringmap_test
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestIndicesUnderPrefix(t *testing.T) {
+	entries := randomSmallStrings(1024, 8)
+	m := faststringmap.NewMap(entries)
+
+	for _, p := range []string{"", "a", "ab", entries[0].Key, entries[1].Key + "~", "\xff"} {
+		var want []string
+		for _, e := range entries {
+			if strings.HasPrefix(e.Key, p) {
+				want = append(want, e.Key)
+			}
+		}
+		sort.Strings(want)
+
+		var got []string
+		for index := range m.IndicesUnderPrefix(p) {
+			v, ok := m.AtIndex(index)
+			if !ok {
+				t.Fatalf("prefix %q: AtIndex(%d) not found", p, index)
+			}
+			got = append(got, keyOfValue(entries, v))
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("IndicesUnderPrefix(%q) gives keys %q want %q", p, got, want)
+		}
+	}
+
+	for range m.IndicesUnderPrefix("") {
+		break // stopping early must not panic
+	}
+	for range (*faststringmap.Map[uint32])(nil).IndicesUnderPrefix("") {
+		t.Error("nil map yields an index")
+	}
+}
+
+// keyOfValue returns the key of the entry with value v.
+func keyOfValue(entries []faststringmap.MapEntry[uint32], v uint32) string {
+	for _, e := range entries {
+		if e.Value == v {
+			return e.Key
+		}
+	}
+	return ""
+}
+
+func TestAllAndPrefix(t *testing.T) {
+	entries := randomSmallStrings(1024, 8)
+	m := faststringmap.NewMap(entries)
+
+	for _, p := range []string{"", "a", "ab", entries[0].Key, entries[1].Key + "~", "\xff"} {
+		var want []string
+		for _, e := range entries {
+			if strings.HasPrefix(e.Key, p) {
+				want = append(want, e.Key)
+			}
+		}
+		sort.Strings(want)
+
+		var got []string
+		for key, v := range m.Prefix(p) {
+			if keyOfValue(entries, v) != key {
+				t.Fatalf("Prefix(%q) yields %q with the value of %q", p, key, keyOfValue(entries, v))
+			}
+			got = append(got, key)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("Prefix(%q) yields %q want %q", p, got, want)
+		}
+
+		got = got[:0]
+		for key := range m.PrefixDesc(p) {
+			got = append(got, key)
+		}
+		slices.Reverse(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("PrefixDesc(%q) yields %q want %q", p, got, want)
+		}
+	}
+
+	var all, allDesc []string
+	for key := range m.All() {
+		all = append(all, key)
+	}
+	for key := range m.AllDesc() {
+		allDesc = append(allDesc, key)
+	}
+	if len(all) != len(entries) || !slices.IsSorted(all) {
+		t.Errorf("All() yields %d keys, sorted %v", len(all), slices.IsSorted(all))
+	}
+	slices.Reverse(allDesc)
+	if !slices.Equal(all, allDesc) {
+		t.Error("AllDesc() does not yield the keys of All() in reverse")
+	}
+
+	for range m.AllDesc() {
+		break // stopping early must not panic
+	}
+	for range (*faststringmap.Map[uint32])(nil).All() {
+		t.Error("nil map yields a key")
+	}
+}
+
+func TestAllFrom(t *testing.T) {
+	entries := randomSmallStrings(1024, 8)
+	m := faststringmap.NewMap(entries)
+
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+	sort.Strings(keys)
+
+	for _, start := range []string{"", "a", "ab", keys[1], keys[len(keys)/2], keys[len(keys)/2] + "\x00", keys[len(keys)-1], "\xff"} {
+		i := sort.SearchStrings(keys, start)
+		want := keys[i:]
+
+		var got []string
+		for key, v := range m.AllFrom(start) {
+			if keyOfValue(entries, v) != key {
+				t.Fatalf("AllFrom(%q) yields %q with the value of %q", start, key, keyOfValue(entries, v))
+			}
+			got = append(got, key)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("AllFrom(%q) yields %d keys from %q want %d keys from %q",
+				start, len(got), got[:min(1, len(got))], len(want), want[:min(1, len(want))])
+		}
+	}
+
+	// paginate over all keys, resuming after the last key of each page
+	var paged []string
+	for start := ""; ; {
+		page := 0
+		for key := range m.AllFrom(start) {
+			paged = append(paged, key)
+			if page++; page == 100 {
+				break
+			}
+		}
+		if page < 100 {
+			break
+		}
+		start = paged[len(paged)-1] + "\x00"
+	}
+	if !slices.Equal(paged, keys) {
+		t.Errorf("paginating with AllFrom yields %d keys want %d", len(paged), len(keys))
+	}
+}
+
+func TestKeysAndWalk(t *testing.T) {
+	entries := randomSmallStrings(1024, 8)
+	m := faststringmap.NewMap(entries)
+
+	var all []string
+	for key := range m.All() {
+		all = append(all, key)
+	}
+	if keys := slices.Collect(m.Keys()); !slices.Equal(keys, all) {
+		t.Errorf("Keys() yields %d keys, not those of All()", len(keys))
+	}
+
+	for _, p := range []string{"", "a", entries[0].Key, "\xff"} {
+		var want, got []string
+		for key := range m.Prefix(p) {
+			want = append(want, key)
+		}
+		m.WalkPrefix(p, func(key []byte, v uint32) bool {
+			if keyOfValue(entries, v) != string(key) {
+				t.Fatalf("WalkPrefix(%q) visits %q with the value of %q", p, key, keyOfValue(entries, v))
+			}
+			got = append(got, string(key))
+			return true
+		})
+		if !slices.Equal(got, want) {
+			t.Errorf("WalkPrefix(%q) visits %q want %q", p, got, want)
+		}
+	}
+
+	n := 0
+	m.Walk(func([]byte, uint32) bool {
+		n++
+		return n < 10
+	})
+	if n != 10 {
+		t.Errorf("Walk() visits %d keys after fn returned false want 10", n)
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() {
+		m.Walk(func([]byte, uint32) bool { return true })
+	}); allocs > 1 {
+		t.Errorf("Walk() allocates %v times want at most 1", allocs)
+	}
+}
+
+func TestWalkLazy(t *testing.T) {
+	entries := randomSmallStrings(1024, 8)
+	lazy, err := faststringmap.UnmarshalMapLazy[uint32](serialize(t, faststringmap.NewMap(entries)), faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	lazy.Walk(func(key []byte, v uint32) bool {
+		if keyOfValue(entries, v) != string(key) {
+			t.Fatalf("Walk() visits %q with the value of %q", key, keyOfValue(entries, v))
+		}
+		n++
+		return true
+	})
+	if n != len(entries) {
+		t.Errorf("Walk() visits %d keys want %d", n, len(entries))
+	}
+}
+
+func TestWalkLongKeys(t *testing.T) {
+	// keys far deeper than any recursion should go
+	long := strings.Repeat("x", 1<<16)
+	keys := []string{"a", long[:1000], long, long + "z", long[:1000] + "y"} // sorted
+	entries := make([]faststringmap.MapEntry[uint32], len(keys))
+	for i, k := range keys {
+		entries[i] = faststringmap.MapEntry[uint32]{Key: k, Value: uint32(i)}
+	}
+	m := faststringmap.NewMap(entries)
+
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, keys) {
+		t.Errorf("Keys() yields %d keys, not the sorted keys", len(got))
+	}
+	var desc []string
+	for key := range m.AllDesc() {
+		desc = append(desc, key)
+	}
+	slices.Reverse(desc)
+	if !slices.Equal(desc, keys) {
+		t.Errorf("AllDesc() yields %d keys, not the reversed keys", len(desc))
+	}
+	var from []string
+	for key := range m.AllFrom(long[:5000]) {
+		from = append(from, key)
+	}
+	if !slices.Equal(from, keys[2:]) {
+		t.Errorf("AllFrom() yields %d keys want 3", len(from))
+	}
+}
+
+func BenchmarkWalk(b *testing.B) {
+	entries := randomSmallStrings(1<<16, 16)
+	m := faststringmap.NewMap(entries)
+
+	b.Run("Walk", func(b *testing.B) {
+		b.ReportAllocs()
+		for bi := 0; bi < b.N; bi++ {
+			m.Walk(func([]byte, uint32) bool { return true })
+		}
+	})
+	b.Run("Keys", func(b *testing.B) {
+		b.ReportAllocs()
+		for bi := 0; bi < b.N; bi++ {
+			for range m.Keys() {
+			}
+		}
+	})
+	b.Run("AllDesc", func(b *testing.B) {
+		b.ReportAllocs()
+		for bi := 0; bi < b.N; bi++ {
+			for range m.AllDesc() {
+			}
+		}
+	})
+	b.Run("IndicesUnderPrefix", func(b *testing.B) {
+		b.ReportAllocs()
+		for bi := 0; bi < b.N; bi++ {
+			for range m.IndicesUnderPrefix("") {
+			}
+		}
+	})
+}
@@ -0,0 +1,273 @@
+package faststringmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"iter"
+)
+
+// A serialized key list is a companion of a serialized map, holding its keys
+// in the order of their value indices, which is ascending key order. Keys
+// are front coded in blocks of keyListBlockSize keys: the first key of a
+// block is stored in full, and every other key as the length of the prefix
+// it shares with the previous key and the rest of its bytes. An index of
+// block offsets finds the block of any key, so resolving an index decodes
+// at most a block. All integers are little-endian:
+//
+//	offset  size     field
+//	0       4        magic "FSTK"
+//	4       2        format version, 1
+//	6       2        number of keys per block (b)
+//	8       4        number of keys (n)
+//	12      4        CRC-32 (Castagnoli) of everything following the header
+//	16      8*(k+1)  offset of each of the k = ceil(n/b) blocks in the key
+//	                 data, followed by the total length of the key data
+//	...              key data: for each block, the uvarint length and the
+//	                 bytes of its first key, then for every other key the
+//	                 uvarint shared prefix length, and the uvarint length and
+//	                 the bytes of the rest of the key
+
+const (
+	keyListMagic      = "FSTK"
+	keyListVersion    = 1
+	keyListHeaderSize = 16
+	keyListBlockSize  = 16
+)
+
+var errKeyOrder = errors.New("faststringmap: value indices are not in ascending key order")
+
+// AppendKeys appends a key list holding the keys of the map to dst, and
+// returns the extended slice. Saved next to the serialized map, and opened
+// with OpenKeyList or OpenMappedKeyList, it resolves value indices to keys
+// without retaining keys in memory (see Builder.SetRetainKeys). Keys are
+// those stored in the map, so they are canonical if the map was built with
+// WithFold or WithKeyTransform. Maps are written whether they retain keys or
+// not. AppendKeys fails for hand-crafted maps whose value indices are not in
+// ascending key order.
+func (m *Map[T]) AppendKeys(dst []byte) ([]byte, error) {
+	n := m.lenOrZero()
+	nBlocks := (n + keyListBlockSize - 1) / keyListBlockSize
+
+	start := len(dst)
+	dst = append(dst, make([]byte, keyListHeaderSize+8*(nBlocks+1))...)
+	header := dst[start:]
+	copy(header[0:], keyListMagic)
+	binary.LittleEndian.PutUint16(header[4:], keyListVersion)
+	binary.LittleEndian.PutUint16(header[6:], keyListBlockSize)
+	binary.LittleEndian.PutUint32(header[8:], uint32(n))
+
+	offsetsStart := start + keyListHeaderSize
+	dataStart := len(dst)
+	var prev []byte
+	i := 0
+	var err error
+	m.walk(func(key []byte, index Uint) bool {
+		if index != Uint(i+1) {
+			err = errKeyOrder
+			return false
+		}
+		if i%keyListBlockSize == 0 {
+			binary.LittleEndian.PutUint64(dst[offsetsStart+8*(i/keyListBlockSize):], uint64(len(dst)-dataStart))
+			dst = binary.AppendUvarint(dst, uint64(len(key)))
+			dst = append(dst, key...)
+		} else {
+			shared := commonPrefixLen(string(prev), string(key))
+			dst = binary.AppendUvarint(dst, uint64(shared))
+			dst = binary.AppendUvarint(dst, uint64(len(key)-shared))
+			dst = append(dst, key[shared:]...)
+		}
+		prev = append(prev[:0], key...)
+		i++
+		return true
+	})
+	if err != nil {
+		return dst[:start], err
+	}
+	binary.LittleEndian.PutUint64(dst[offsetsStart+8*nBlocks:], uint64(len(dst)-dataStart))
+
+	checksum := crc32.Checksum(dst[offsetsStart:], serialChecksumTable)
+	binary.LittleEndian.PutUint32(dst[start+12:], checksum)
+	return dst, nil
+}
+
+// KeyList resolves the value indices of a map to its keys, from a key list
+// written by Map.AppendKeys. It uses the serialized data in place, so a key
+// list mapped from a file takes no heap memory however many keys it holds.
+// A KeyList is read only, and safe for concurrent use.
+type KeyList struct {
+	n         int
+	blockSize int
+	offsets   []byte // block offsets in data, and the total length
+	data      []byte // key data
+}
+
+// OpenKeyList returns the key list serialized in data, which must not be
+// modified while the KeyList is in use. The header, the checksum and the
+// block index are validated; keys are checked as they are decoded, so
+// corrupt data makes lookups fail instead of panicking.
+func OpenKeyList(data []byte) (KeyList, error) {
+	return openKeyList(data, true)
+}
+
+func openKeyList(data []byte, verify bool) (KeyList, error) {
+	if len(data) < keyListHeaderSize || string(data[:4]) != keyListMagic {
+		return KeyList{}, ErrInvalidEncoding
+	}
+	if binary.LittleEndian.Uint16(data[4:]) != keyListVersion {
+		return KeyList{}, ErrUnsupportedVersion
+	}
+
+	kl := KeyList{
+		blockSize: int(binary.LittleEndian.Uint16(data[6:])),
+		n:         int(binary.LittleEndian.Uint32(data[8:])),
+	}
+	if kl.blockSize == 0 {
+		return KeyList{}, ErrInvalidEncoding
+	}
+	nBlocks := (uint64(kl.n) + uint64(kl.blockSize) - 1) / uint64(kl.blockSize)
+	dataStart := keyListHeaderSize + 8*(nBlocks+1)
+	if dataStart > uint64(len(data)) {
+		return KeyList{}, ErrInvalidEncoding
+	}
+	if verify && crc32.Checksum(data[keyListHeaderSize:], serialChecksumTable) != binary.LittleEndian.Uint32(data[12:]) {
+		return KeyList{}, ErrInvalidEncoding
+	}
+
+	kl.offsets = data[keyListHeaderSize:dataStart]
+	kl.data = data[dataStart:]
+	prev := uint64(0)
+	for b := uint64(0); b <= nBlocks; b++ {
+		off := binary.LittleEndian.Uint64(kl.offsets[8*b:])
+		if off < prev || off > uint64(len(kl.data)) {
+			return KeyList{}, ErrInvalidEncoding
+		}
+		prev = off
+	}
+	if prev != uint64(len(kl.data)) {
+		return KeyList{}, ErrInvalidEncoding
+	}
+	return kl, nil
+}
+
+// Len returns the number of keys in the list.
+func (kl *KeyList) Len() int {
+	return kl.n
+}
+
+// KeyAtIndex returns the key of the value at index, as returned by
+// IndexString of the map the list was written from. ok is false if index
+// is not in 1..Len, or the data is corrupt.
+func (kl *KeyList) KeyAtIndex(index Uint) (key string, ok bool) {
+	b, ok := kl.AppendKeyAtIndex(nil, index)
+	return string(b), ok
+}
+
+// AppendKeyAtIndex appends the key of the value at index to dst, like
+// KeyAtIndex, and returns the extended slice. It does not allocate if dst
+// has room for the key and the keys before it in its block.
+func (kl *KeyList) AppendKeyAtIndex(dst []byte, index Uint) ([]byte, bool) {
+	if index == 0 || uint64(index) > uint64(kl.n) {
+		return dst, false
+	}
+	i := int(index - 1)
+	block, pos := i/kl.blockSize, i%kl.blockSize
+
+	start := len(dst)
+	data, ok := kl.block(block)
+	for j := 0; ok && j <= pos; j++ {
+		dst, data, ok = appendNextKey(dst, start, data, j == 0)
+	}
+	if !ok {
+		return dst[:start], false
+	}
+	return dst, true
+}
+
+// Keys returns an iterator over the keys of the list, in ascending order,
+// which is the order of their value indices, decoding every key once. It
+// stops early on corrupt data.
+func (kl *KeyList) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		var key []byte
+		for i := 0; i < kl.n; {
+			data, ok := kl.block(i / kl.blockSize)
+			for j := 0; ok && j < kl.blockSize && i < kl.n; j++ {
+				if key, data, ok = appendNextKey(key, 0, data, j == 0); !ok {
+					return
+				}
+				i++
+				if !yield(string(key)) {
+					return
+				}
+			}
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// block returns the key data of block b.
+func (kl *KeyList) block(b int) ([]byte, bool) {
+	lo := binary.LittleEndian.Uint64(kl.offsets[8*b:])
+	hi := binary.LittleEndian.Uint64(kl.offsets[8*b+8:])
+	if lo > hi || hi > uint64(len(kl.data)) {
+		return nil, false
+	}
+	return kl.data[lo:hi], true
+}
+
+// appendNextKey decodes the next key of a block from data, replacing the
+// previous key in dst[start:] with it, and returns the rest of data.
+func appendNextKey(dst []byte, start int, data []byte, first bool) ([]byte, []byte, bool) {
+	shared := uint64(0)
+	if !first {
+		var ok bool
+		if shared, data, ok = cutUvarint(data); !ok || shared > uint64(len(dst)-start) {
+			return dst, nil, false
+		}
+	}
+	n, data, ok := cutUvarint(data)
+	if !ok || n > uint64(len(data)) {
+		return dst, nil, false
+	}
+	dst = append(dst[:start+int(shared)], data[:n]...)
+	return dst, data[n:], true
+}
+
+// MappedKeyList is a key list loaded from a file that is mapped into memory.
+type MappedKeyList struct {
+	KeyList
+	data  []byte
+	unmap func([]byte) error
+}
+
+// OpenMappedKeyList loads the key list in the named file by mapping it into
+// memory, like OpenMapped, so keys are paged in as they are resolved. The
+// checksum is not verified, so that opening a large list does not read all
+// of it. The file must not be modified while the list is open.
+func OpenMappedKeyList(path string) (*MappedKeyList, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("faststringmap: loading %s: %w", path, err)
+	}
+
+	kl, err := openKeyList(data, false)
+	if err != nil {
+		unmap(data)
+		return nil, fmt.Errorf("faststringmap: loading %s: %w", path, err)
+	}
+	return &MappedKeyList{KeyList: kl, data: data, unmap: unmap}, nil
+}
+
+// Close unmaps the file. The list must not be used after Close is called.
+func (l *MappedKeyList) Close() error {
+	if l.data == nil {
+		return nil
+	}
+	data := l.data
+	l.data, l.KeyList = nil, KeyList{}
+	return l.unmap(data)
+}
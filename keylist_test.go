@@ -0,0 +1,113 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestKeyList(t *testing.T) {
+	m := faststringmap.NewMap(randomSmallStrings(1000, 12))
+	data, err := m.AppendKeys([]byte("prefix"))
+	if err != nil {
+		t.Fatalf("AppendKeys() error = %v", err)
+	}
+	kl, err := faststringmap.OpenKeyList(data[len("prefix"):])
+	if err != nil {
+		t.Fatalf("OpenKeyList() error = %v", err)
+	}
+
+	want := slices.Collect(m.Keys())
+	if kl.Len() != len(want) {
+		t.Fatalf("Len() = %d want %d", kl.Len(), len(want))
+	}
+	if got := slices.Collect(kl.Keys()); !slices.Equal(got, want) {
+		t.Errorf("Keys() = %q want %q", got, want)
+	}
+	for _, k := range want {
+		index := m.IndexString(k)
+		if got, ok := kl.KeyAtIndex(index); !ok || got != k {
+			t.Errorf("KeyAtIndex(%d) = %q, %v want %q", index, got, ok, k)
+		}
+	}
+	for _, index := range []faststringmap.Uint{0, faststringmap.Uint(len(want) + 1)} {
+		if got, ok := kl.KeyAtIndex(index); ok {
+			t.Errorf("KeyAtIndex(%d) = %q, true want false", index, got)
+		}
+	}
+
+	buf := make([]byte, 0, 1024)
+	if allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = kl.AppendKeyAtIndex(buf[:0], faststringmap.Uint(len(want)))
+	}); allocs != 0 {
+		t.Errorf("AppendKeyAtIndex() allocs = %v want 0", allocs)
+	}
+}
+
+func TestKeyListEmpty(t *testing.T) {
+	var m faststringmap.Map[uint32]
+	data, err := m.AppendKeys(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kl, err := faststringmap.OpenKeyList(data)
+	if err != nil {
+		t.Fatalf("OpenKeyList() error = %v", err)
+	}
+	if kl.Len() != 0 {
+		t.Errorf("Len() = %d want 0", kl.Len())
+	}
+	for k := range kl.Keys() {
+		t.Errorf("Keys() yields %q", k)
+	}
+}
+
+func TestKeyListCorrupt(t *testing.T) {
+	m := faststringmap.NewMap(goldenEntries)
+	data, err := m.AppendKeys(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := faststringmap.OpenKeyList(data[:10]); !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+		t.Errorf("OpenKeyList(truncated) error = %v want ErrInvalidEncoding", err)
+	}
+	for i := 16; i < len(data); i++ {
+		corrupt := slices.Clone(data)
+		corrupt[i] ^= 0x40
+		if _, err := faststringmap.OpenKeyList(corrupt); !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+			t.Errorf("OpenKeyList(byte %d flipped) error = %v want ErrInvalidEncoding", i, err)
+		}
+	}
+}
+
+func TestOpenMappedKeyList(t *testing.T) {
+	m := faststringmap.NewMap(randomSmallStrings(4096, 8))
+	data, err := m.AppendKeys(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "random.fstk")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	kl, err := faststringmap.OpenMappedKeyList(path)
+	if err != nil {
+		t.Fatalf("OpenMappedKeyList() error = %v", err)
+	}
+	for k := range m.Keys() {
+		if got, ok := kl.KeyAtIndex(m.IndexString(k)); !ok || got != k {
+			t.Errorf("KeyAtIndex(%d) = %q, %v want %q", m.IndexString(k), got, ok, k)
+		}
+	}
+	if err := kl.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err := kl.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
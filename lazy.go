@@ -0,0 +1,161 @@
+package faststringmap
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+const lazyPageSize = 1024 // number of values in a page of decoded values
+
+// lazyValues holds the encoded values of a map, and decodes each value on
+// first access. Decoded values are memoized in pages that are allocated on
+// demand, so memory use is proportional to the set of accessed values.
+type lazyValues[T any] struct {
+	source valueSource
+	codec  ValueCodec[T]
+
+	mu    sync.Mutex // held while decoding values and allocating pages
+	ready []uint32   // ready[p] is set atomically once pages[p] is allocated
+	pages []lazyPage[T]
+}
+
+// valueSource provides the encoded values of a lazily loaded map.
+type valueSource interface {
+	len() int
+	value(i int) ([]byte, error)
+}
+
+type lazyPage[T any] struct {
+	values []T
+	done   []uint32 // done[i] is set atomically once values[i] is decoded
+	failed []bool   // failed[i] is set if values[i] is invalid and could not be decoded
+}
+
+// UnmarshalMapLazy[T] constructs a Map from its serialized form like
+// UnmarshalMap, but leaves the values encoded in data, and decodes each value
+// using codec on its first access. This makes loading a map with large values
+// nearly instantaneous. The Map refers to data for its whole lifetime, so
+// data must not be modified while the Map is in use. A value that fails to
+// decode is reported as not present; AtIndexErr reports why.
+func UnmarshalMapLazy[T any](data []byte, codec ValueCodec[T]) (Map[T], error) {
+	return unmarshalLazy(data, codec, true)
+}
+
+func unmarshalLazy[T any](data []byte, codec ValueCodec[T], verify bool) (Map[T], error) {
+	layout, err := parseSerialized(data, verify)
+	if err != nil {
+		return Map[T]{}, err
+	}
+
+	store, err := loadNodes(layout.nodes, layout.nValues)
+	if err != nil {
+		return Map[T]{}, err
+	}
+
+	m := Map[T]{
+		store:         store,
+		lazy:          newLazyValues(&layout, codec),
+		maxKeyLen:     maxKeyLen(store),
+		formatVersion: layout.version,
+		fold:          layout.fold,
+		metadata:      layout.metadata,
+	}
+	m.setFingerprints(layout.fingerprints, layout.salt)
+	return m, nil
+}
+
+func newLazyValues[T any](source valueSource, codec ValueCodec[T]) *lazyValues[T] {
+	nPages := (source.len() + lazyPageSize - 1) / lazyPageSize
+	return &lazyValues[T]{
+		source: source,
+		codec:  codec,
+		ready:  make([]uint32, nPages),
+		pages:  make([]lazyPage[T], nPages),
+	}
+}
+
+// AtIndexErr returns the value in the map at the supplied internal index
+// like AtIndex, and if the map is lazily loaded and the value could not be
+// read or decoded, the error doing so. Values that fail to decode are
+// memoized as invalid, and report ErrInvalidEncoding from then on,
+// while errors reading values, such as those of the io.ReaderAt of
+// OpenReaderAt, are not memoized, so the next access reads the value again.
+func (m *Map[T]) AtIndexErr(index Uint) (t T, ok bool, err error) {
+	if index != 0 && m.lazy != nil {
+		return m.lazy.atErr(index - 1)
+	}
+	t, ok = m.AtIndex(index)
+	return t, ok, nil
+}
+
+// at returns the value at index i, decoding it if this is its first access.
+func (l *lazyValues[T]) at(i Uint) (t T, ok bool) {
+	t, ok, _ = l.atErr(i)
+	return t, ok
+}
+
+// atErr returns the value at index i like at, and the error reading or
+// decoding it, if any.
+func (l *lazyValues[T]) atErr(i Uint) (t T, ok bool, err error) {
+	if int(i) >= l.source.len() {
+		return t, false, nil
+	}
+
+	p, pi := i/lazyPageSize, i%lazyPageSize
+	if atomic.LoadUint32(&l.ready[p]) != 0 {
+		page := &l.pages[p]
+		if atomic.LoadUint32(&page.done[pi]) != 0 {
+			return page.result(pi)
+		}
+	}
+
+	return l.decode(p, pi)
+}
+
+func (l *lazyValues[T]) decode(p, pi Uint) (t T, ok bool, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	page := &l.pages[p]
+	if atomic.LoadUint32(&l.ready[p]) == 0 {
+		n := l.source.len() - int(p)*lazyPageSize
+		if n > lazyPageSize {
+			n = lazyPageSize
+		}
+		page.values = make([]T, n)
+		page.done = make([]uint32, n)
+		page.failed = make([]bool, n)
+		atomic.StoreUint32(&l.ready[p], 1)
+	}
+
+	if atomic.LoadUint32(&page.done[pi]) == 0 {
+		src, err := l.source.value(int(p*lazyPageSize + pi))
+		if err != nil && !errors.Is(err, ErrInvalidEncoding) {
+			return t, false, err // reading failed, so retry on the next access
+		}
+		if err == nil {
+			page.values[pi], err = l.codec.DecodeValue(src)
+		}
+		page.failed[pi] = err != nil
+		atomic.StoreUint32(&page.done[pi], 1)
+		if err != nil {
+			return t, false, err
+		}
+	}
+
+	return page.result(pi)
+}
+
+// result returns the memoized value at index pi of the page.
+func (page *lazyPage[T]) result(pi Uint) (t T, ok bool, err error) {
+	if page.failed[pi] {
+		return t, false, ErrInvalidEncoding
+	}
+	return page.values[pi], true, nil
+}
+
+// len returns the number of values in the map.
+func (l *lazyValues[T]) len() int {
+	return l.source.len()
+}
@@ -0,0 +1,94 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+// countingCodec counts the values it decodes.
+type countingCodec struct {
+	faststringmap.IntCodec[uint32]
+	mu      sync.Mutex
+	decoded int
+}
+
+func (c *countingCodec) DecodeValue(src []byte) (uint32, error) {
+	c.mu.Lock()
+	c.decoded++
+	c.mu.Unlock()
+	return c.IntCodec.DecodeValue(src)
+}
+
+func TestUnmarshalMapLazy(t *testing.T) {
+	entries := randomSmallStrings(4096, 8)
+	data := serialize(t, faststringmap.NewMap(entries))
+
+	codec := &countingCodec{}
+	m, err := faststringmap.UnmarshalMapLazy[uint32](data, codec)
+	if err != nil {
+		t.Fatalf("UnmarshalMapLazy() error = %v", err)
+	}
+	if codec.decoded != 0 {
+		t.Errorf("UnmarshalMapLazy() decoded %d values want 0", codec.decoded)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkEntries(t, &m, entries[:100])
+		}()
+	}
+	wg.Wait()
+
+	if codec.decoded != 100 {
+		t.Errorf("decoded %d values after looking up 100 keys want 100", codec.decoded)
+	}
+
+	if got := serialize(t, m); !bytes.Equal(got, data) {
+		t.Errorf("re-serialized lazy map differs from the original")
+	}
+	checkEntries(t, &m, entries)
+}
+
+// failingCodec fails to decode every value, and counts its attempts.
+type failingCodec struct {
+	faststringmap.IntCodec[uint32]
+	decoded int
+}
+
+func (c *failingCodec) DecodeValue(src []byte) (uint32, error) {
+	c.decoded++
+	return 0, errors.New("bad value")
+}
+
+func TestUnmarshalMapLazyDecodeError(t *testing.T) {
+	data := serialize(t, faststringmap.NewMap(goldenEntries))
+
+	codec := &failingCodec{}
+	m, err := faststringmap.UnmarshalMapLazy[uint32](data, codec)
+	if err != nil {
+		t.Fatalf("UnmarshalMapLazy() error = %v", err)
+	}
+
+	index := m.IndexString(goldenEntries[0].Key)
+	if _, ok, err := m.AtIndexErr(index); ok || err == nil {
+		t.Errorf("AtIndexErr() of an undecodable value = %v, %v want false, error", ok, err)
+	}
+	// decode errors are memoized
+	if _, ok, err := m.AtIndexErr(index); ok || !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+		t.Errorf("AtIndexErr() again = %v, %v want false, %v", ok, err, faststringmap.ErrInvalidEncoding)
+	}
+	if codec.decoded != 1 {
+		t.Errorf("decoded the value %d times want 1", codec.decoded)
+	}
+
+	if _, ok, err := m.AtIndexErr(0); ok || err != nil {
+		t.Errorf("AtIndexErr(0) = %v, %v want false, nil", ok, err)
+	}
+}
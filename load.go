@@ -0,0 +1,27 @@
+package faststringmap
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// LoadFS[T] loads a serialized map from the named file in fsys, using codec
+// to decode its values. Together with an embed.FS, it allows compiling a
+// serialized map into a program:
+//
+//	//go:embed dict.fstm
+//	var dictFS embed.FS
+//
+//	dict, err := faststringmap.LoadFS[int](dictFS, "dict.fstm", faststringmap.IntCodec[int]{})
+func LoadFS[T any](fsys fs.FS, path string, codec ValueCodec[T]) (Map[T], error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return Map[T]{}, fmt.Errorf("faststringmap: loading %s: %w", path, err)
+	}
+
+	m, err := UnmarshalMap(data, codec)
+	if err != nil {
+		return Map[T]{}, fmt.Errorf("faststringmap: loading %s: %w", path, err)
+	}
+	return m, nil
+}
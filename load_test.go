@@ -0,0 +1,33 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestLoadFS(t *testing.T) {
+	m, err := faststringmap.LoadFS[uint32](os.DirFS("testdata"), "methods_v3.fstm", faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatalf("LoadFS() error = %v", err)
+	}
+	checkEntries(t, &m, goldenEntries)
+}
+
+func TestLoadFSErrors(t *testing.T) {
+	fsys := fstest.MapFS{"corrupt.fstm": {Data: []byte("FSTM garbage")}}
+
+	_, err := faststringmap.LoadFS[uint32](fsys, "missing.fstm", faststringmap.IntCodec[uint32]{})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadFS(missing) error = %v want fs.ErrNotExist", err)
+	}
+
+	_, err = faststringmap.LoadFS[uint32](fsys, "corrupt.fstm", faststringmap.IntCodec[uint32]{})
+	if !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+		t.Errorf("LoadFS(corrupt) error = %v want ErrInvalidEncoding", err)
+	}
+}
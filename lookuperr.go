@@ -0,0 +1,64 @@
+package faststringmap
+
+// LookupStringErr looks up the supplied string in the map like
+// LookupString, and if it is not present, also reports where it diverged
+// from all keys, for diagnostics such as "unknown key max_connctions,
+// mismatch at byte 8". failPos is the length of the longest prefix of s
+// that some key starts with: the position of the first byte of s no key
+// continues with, or len(s) if keys continue s but none is equal to it.
+// failPos is -1 if s is present. For maps with a key transform, failPos
+// is a position in the transformed probe.
+func (m *Map[T]) LookupStringErr(s string) (t T, ok bool, failPos int) {
+	if m != nil && m.keyTransform != nil {
+		return m.LookupBytesErr(stringBytes(s))
+	}
+	index, failPos := indexFailPos(m, s)
+	t, ok = m.AtIndex(index)
+	return t, ok, failPos
+}
+
+// LookupBytesErr looks up the supplied byte slice in the map like
+// LookupStringErr. It does not allocate, and does not retain s.
+func (m *Map[T]) LookupBytesErr(s []byte) (t T, ok bool, failPos int) {
+	if m != nil && m.keyTransform != nil {
+		s = m.keyTransform(s)
+	}
+	index, failPos := indexFailPos(m, s)
+	t, ok = m.AtIndex(index)
+	return t, ok, failPos
+}
+
+func indexFailPos[T any, S string | []byte](m *Map[T], s S) (index Uint, failPos int) {
+	if m == nil || len(m.store) == 0 {
+		return 0, 0
+	}
+
+	h := m.fingerprintSeed // fingerprint of the probe, computed on the way
+	bv := &m.store[0]
+	for i, n := 0, len(s); i < n; i++ {
+		b := s[i]
+		if m.fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		h = (h ^ uint32(b)) * 16777619
+
+		ni := b - bv.nextOffset // bytes below nextOffset wrap around past nextLen
+		if ni >= bv.nextLen {
+			return 0, i
+		}
+		next := &m.store[bv.nextLo+uint32(ni)]
+		if next.valueOffset == 0 && next.nextLen == 0 {
+			return 0, i // not a valid byte
+		}
+		bv = next
+	}
+
+	index = bv.valueOffset
+	if index != 0 && m.fingerprints != nil {
+		index = m.verifyFingerprint(index, byte(h^h>>8^h>>16^h>>24))
+	}
+	if index == 0 {
+		return 0, len(s)
+	}
+	return index, -1
+}
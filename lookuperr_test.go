@@ -0,0 +1,61 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestLookupStringErr(t *testing.T) {
+	m := faststringmap.FromMap(map[string]int{
+		"max_connections": 1, "max_conns": 2, "min_idle": 3, "timeout": 4,
+	})
+
+	tests := []struct {
+		s       string
+		want    int
+		ok      bool
+		failPos int
+	}{
+		{"max_connections", 1, true, -1},
+		{"max_conns", 2, true, -1},
+		{"max_connctions", 0, false, 8},
+		{"max_conn", 0, false, 8},
+		{"max_connections_total", 0, false, 15},
+		{"mix", 0, false, 2},
+		{"zzz", 0, false, 0},
+		{"", 0, false, 0},
+	}
+	for _, tt := range tests {
+		if v, ok, pos := m.LookupStringErr(tt.s); v != tt.want || ok != tt.ok || pos != tt.failPos {
+			t.Errorf("LookupStringErr(%q) = %v, %v, %d want %v, %v, %d", tt.s, v, ok, pos, tt.want, tt.ok, tt.failPos)
+		}
+		if v, ok, pos := m.LookupBytesErr([]byte(tt.s)); v != tt.want || ok != tt.ok || pos != tt.failPos {
+			t.Errorf("LookupBytesErr(%q) = %v, %v, %d want %v, %v, %d", tt.s, v, ok, pos, tt.want, tt.ok, tt.failPos)
+		}
+	}
+
+	// a byte inside the range of a node that no key continues with
+	sparse := faststringmap.FromMap(map[string]int{"a": 1, "c": 2})
+	if _, ok, pos := sparse.LookupStringErr("b"); ok || pos != 0 {
+		t.Errorf("LookupStringErr(b) = %v, %d want false, 0", ok, pos)
+	}
+
+	folded, err := faststringmap.New([]faststringmap.MapEntry[int]{{" Timeout", 1}},
+		faststringmap.WithFold(), faststringmap.WithFingerprints(), faststringmap.WithKeyTransform(bytes.TrimSpace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, pos := folded.LookupStringErr("TIMEOUT "); !ok || v != 1 || pos != -1 {
+		t.Errorf("LookupStringErr(TIMEOUT) = %v, %v, %d want 1, true, -1", v, ok, pos)
+	}
+	if _, ok, pos := folded.LookupStringErr(" TIMEOUTS"); ok || pos != 7 {
+		t.Errorf("LookupStringErr(TIMEOUTS) = %v, %d want false, 7", ok, pos)
+	}
+
+	var empty *faststringmap.Map[int]
+	if _, ok, pos := empty.LookupStringErr("x"); ok || pos != 0 {
+		t.Errorf("nil LookupStringErr(x) = %v, %d want false, 0", ok, pos)
+	}
+}
@@ -0,0 +1,94 @@
+package faststringmap
+
+import (
+	"encoding/binary"
+	"maps"
+	"slices"
+)
+
+// Metadata is carried by a map from its construction through serialization,
+// so that a process can report which generation of a dictionary it serves.
+// It is serialized after the value data, in ascending order of keys, as
+//
+//	uvarint    number of pairs
+//	for each pair: uvarint length and bytes of the key, then of the value
+//
+// and flagged by bit 1 of the header flags.
+
+const serialFlagMetadata = 1 << 1
+
+// WithMetadata attaches metadata to the map, such as a version string, a
+// build timestamp or a checksum of the source data. See Builder.SetMetadata.
+func WithMetadata(md map[string]string) Option {
+	return func(o *buildOptions) { o.metadata = maps.Clone(md) }
+}
+
+// SetMetadata sets the metadata of maps built by the builder, which Metadata
+// returns, and which is kept when the maps are serialized. The builder keeps
+// a copy of md.
+func (b *Builder[T]) SetMetadata(md map[string]string) {
+	b.metadata = maps.Clone(md)
+}
+
+// Metadata returns a copy of the metadata attached to the map when it was
+// built, or nil if there is none.
+func (m *Map[T]) Metadata() map[string]string {
+	if m == nil || len(m.metadata) == 0 {
+		return nil
+	}
+	return maps.Clone(m.metadata)
+}
+
+// appendMetadata appends the serialized form of md to dst.
+func appendMetadata(dst []byte, md map[string]string) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(md)))
+	for _, k := range slices.Sorted(maps.Keys(md)) {
+		v := md[k]
+		dst = binary.AppendUvarint(dst, uint64(len(k)))
+		dst = append(dst, k...)
+		dst = binary.AppendUvarint(dst, uint64(len(v)))
+		dst = append(dst, v...)
+	}
+	return dst
+}
+
+// decodeMetadata decodes metadata serialized by appendMetadata, which must
+// fill b exactly.
+func decodeMetadata(b []byte) (map[string]string, error) {
+	n, b, ok := cutUvarint(b)
+	if !ok || n > uint64(len(b)) {
+		return nil, ErrInvalidEncoding
+	}
+
+	md := make(map[string]string, n)
+	for i := uint64(0); i < n; i++ {
+		var k, v string
+		if k, b, ok = cutUvarintString(b); !ok {
+			return nil, ErrInvalidEncoding
+		}
+		if v, b, ok = cutUvarintString(b); !ok {
+			return nil, ErrInvalidEncoding
+		}
+		md[k] = v
+	}
+	if len(b) != 0 || len(md) != int(n) {
+		return nil, ErrInvalidEncoding
+	}
+	return md, nil
+}
+
+func cutUvarint(b []byte) (x uint64, rest []byte, ok bool) {
+	x, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, false
+	}
+	return x, b[n:], true
+}
+
+func cutUvarintString(b []byte) (s string, rest []byte, ok bool) {
+	n, b, ok := cutUvarint(b)
+	if !ok || n > uint64(len(b)) {
+		return "", nil, false
+	}
+	return string(b[:n]), b[n:], true
+}
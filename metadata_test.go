@@ -0,0 +1,79 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"maps"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestMetadata(t *testing.T) {
+	md := map[string]string{"version": "2024-06-01", "source": "sha256:abc", "empty": ""}
+	m, err := faststringmap.New(goldenEntries, faststringmap.WithMetadata(md))
+	if err != nil {
+		t.Fatal(err)
+	}
+	md["version"] = "changed" // the map keeps its own copy
+	if got := m.Metadata(); got["version"] != "2024-06-01" || len(got) != 3 {
+		t.Errorf("Metadata() = %v", got)
+	}
+	m.Metadata()["version"] = "changed"
+	if got := m.Metadata()["version"]; got != "2024-06-01" {
+		t.Errorf("Metadata() shares its map: version = %q", got)
+	}
+	plainMap := faststringmap.NewMap(goldenEntries)
+	if got := plainMap.Metadata(); got != nil {
+		t.Errorf("Metadata() without metadata = %v want nil", got)
+	}
+
+	data := serialize(t, m)
+	for name, unmarshal := range map[string]func([]byte, faststringmap.ValueCodec[uint32]) (faststringmap.Map[uint32], error){
+		"UnmarshalMap":     faststringmap.UnmarshalMap[uint32],
+		"UnmarshalMapLazy": faststringmap.UnmarshalMapLazy[uint32],
+		"OpenReaderAt": func(data []byte, codec faststringmap.ValueCodec[uint32]) (faststringmap.Map[uint32], error) {
+			return faststringmap.OpenReaderAt(bytes.NewReader(data), int64(len(data)), codec)
+		},
+	} {
+		loaded, err := unmarshal(data, faststringmap.IntCodec[uint32]{})
+		if err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+		if got := loaded.Metadata(); !maps.Equal(got, m.Metadata()) {
+			t.Errorf("%s() metadata = %v want %v", name, got, m.Metadata())
+		}
+		checkEntries(t, &loaded, goldenEntries)
+		if !bytes.Equal(serialize(t, loaded), data) {
+			t.Errorf("re-serialized map loaded by %s() differs from the original", name)
+		}
+	}
+
+	// metadata does not change how the rest of the map is serialized
+	plain := serialize(t, plainMap)
+	if !bytes.Equal(data[32:len(plain)], plain[32:]) {
+		t.Error("metadata changes the serialized nodes or values")
+	}
+
+	// a truncated metadata section, with a valid checksum, is rejected
+	bad := bytes.Clone(data[:len(data)-1])
+	binary.LittleEndian.PutUint32(bad[16:], crc32.Checksum(bad[32:], crc32.MakeTable(crc32.Castagnoli)))
+	if _, err := faststringmap.UnmarshalMap(bad, faststringmap.IntCodec[uint32]{}); !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+		t.Errorf("UnmarshalMap() of truncated metadata error = %v want ErrInvalidEncoding", err)
+	}
+	if _, err := faststringmap.OpenReaderAt(bytes.NewReader(bad), int64(len(bad)), faststringmap.IntCodec[uint32]{}); !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+		t.Errorf("OpenReaderAt() of truncated metadata error = %v want ErrInvalidEncoding", err)
+	}
+}
+
+func TestBuilderMetadata(t *testing.T) {
+	var b faststringmap.Builder[uint32]
+	b.SetMetadata(map[string]string{"generation": "7"})
+	b.Add("a", 1)
+	m := b.Build()
+	if got := m.Metadata()["generation"]; got != "7" {
+		t.Errorf(`Metadata()["generation"] = %q want "7"`, got)
+	}
+}
@@ -0,0 +1,46 @@
+package faststringmap
+
+import (
+	"fmt"
+)
+
+// MappedMap[T] is a serialized map loaded from a file that is mapped into
+// memory, so the operating system pages it in as it is used instead of the
+// whole file being read up front.
+type MappedMap[T any] struct {
+	Map   Map[T]
+	data  []byte
+	unmap func([]byte) error
+}
+
+// OpenMapped[T] loads the serialized map in the named file by mapping the
+// file into memory, and decodes each value using codec on its first access
+// like UnmarshalMapLazy. The node store is validated, so lookups never panic,
+// but the checksum is not verified, so that opening a large map does not
+// read all of it. Use Map.Warm to page the map in ahead of its first use.
+// On platforms that do not support memory mapping, the file is read into
+// memory instead. The file must not be modified while the map is open.
+func OpenMapped[T any](path string, codec ValueCodec[T]) (*MappedMap[T], error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("faststringmap: loading %s: %w", path, err)
+	}
+
+	m, err := unmarshalLazy(data, codec, false)
+	if err != nil {
+		unmap(data)
+		return nil, fmt.Errorf("faststringmap: loading %s: %w", path, err)
+	}
+
+	return &MappedMap[T]{Map: m, data: data, unmap: unmap}, nil
+}
+
+// Close unmaps the file. The map must not be used after Close is called.
+func (m *MappedMap[T]) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	data := m.data
+	m.data, m.Map = nil, Map[T]{}
+	return m.unmap(data)
+}
@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd) || faststringmap_minimal || tinygo
+
+package faststringmap
+
+import "os"
+
+// mapFile reads the named file into memory, on platforms without mmap and
+// in the minimal profile.
+func mapFile(path string) (data []byte, unmap func([]byte) error, err error) {
+	data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func([]byte) error { return nil }, nil
+}
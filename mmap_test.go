@@ -0,0 +1,46 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestOpenMapped(t *testing.T) {
+	entries := randomSmallStrings(4096, 8)
+	path := filepath.Join(t.TempDir(), "random.fstm")
+	if err := os.WriteFile(path, serialize(t, faststringmap.NewMap(entries)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mm, err := faststringmap.OpenMapped[uint32](path, faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatalf("OpenMapped() error = %v", err)
+	}
+	checkEntries(t, &mm.Map, entries)
+
+	if err := mm.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err := mm.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+func TestOpenMappedErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.fstm")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := faststringmap.OpenMapped[uint32](filepath.Join(dir, "missing.fstm"), faststringmap.IntCodec[uint32]{}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenMapped(missing) error = %v want os.ErrNotExist", err)
+	}
+	if _, err := faststringmap.OpenMapped[uint32](empty, faststringmap.IntCodec[uint32]{}); !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+		t.Errorf("OpenMapped(empty) error = %v want ErrInvalidEncoding", err)
+	}
+}
@@ -0,0 +1,31 @@
+//go:build (darwin || dragonfly || freebsd || linux || netbsd || openbsd) && !faststringmap_minimal && !tinygo
+
+package faststringmap
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the named file into memory read only.
+func mapFile(path string) (data []byte, unmap func([]byte) error, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if fi.Size() == 0 || int64(int(fi.Size())) != fi.Size() {
+		return nil, nil, ErrInvalidEncoding
+	}
+
+	data, err = syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, syscall.Munmap, nil
+}
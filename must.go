@@ -0,0 +1,38 @@
+package faststringmap
+
+import "io/fs"
+
+// Must[T] returns m, and panics if err is not nil. It wraps any of the
+// error-returning constructors and loaders for package-level variables of
+// static tables, where an error is a programming mistake:
+//
+//	var keywords = faststringmap.Must(faststringmap.UnmarshalMapLazy(data, codec))
+func Must[T any](m Map[T], err error) Map[T] {
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MustNewMap[T] is like New, but panics if the map can not be constructed,
+// for example because of duplicate keys.
+func MustNewMap[T any](entries []MapEntry[T], opts ...Option) Map[T] {
+	return Must(New(entries, opts...))
+}
+
+// MustLoadFS[T] is like LoadFS, but panics if the map can not be loaded.
+// It suits maps embedded in the program with an embed.FS:
+//
+//	//go:embed dict.fstm
+//	var dictFS embed.FS
+//
+//	var dict = faststringmap.MustLoadFS[int](dictFS, "dict.fstm", faststringmap.IntCodec[int]{})
+func MustLoadFS[T any](fsys fs.FS, path string, codec ValueCodec[T]) Map[T] {
+	return Must(LoadFS(fsys, path, codec))
+}
+
+// MustUnmarshalMap[T] is like UnmarshalMap, but panics if data is not a
+// valid serialized map.
+func MustUnmarshalMap[T any](data []byte, codec ValueCodec[T]) Map[T] {
+	return Must(UnmarshalMap(data, codec))
+}
@@ -0,0 +1,46 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestMust(t *testing.T) {
+	m := faststringmap.MustNewMap([]faststringmap.MapEntry[int]{{"a", 1}, {"b", 2}})
+	if v, ok := m.LookupString("b"); !ok || v != 2 {
+		t.Errorf("LookupString(b) = %v, %v want 2, true", v, ok)
+	}
+
+	loaded := faststringmap.MustLoadFS[uint32](os.DirFS("testdata"), "methods_v3.fstm", faststringmap.IntCodec[uint32]{})
+	checkEntries(t, &loaded, goldenEntries)
+
+	tests := []struct {
+		name string
+		want error
+		fn   func()
+	}{
+		{"MustNewMap", faststringmap.ErrDuplicateKey, func() {
+			faststringmap.MustNewMap([]faststringmap.MapEntry[int]{{"a", 1}, {"a", 2}})
+		}},
+		{"MustLoadFS", os.ErrNotExist, func() {
+			faststringmap.MustLoadFS[uint32](os.DirFS("testdata"), "missing.fstm", faststringmap.IntCodec[uint32]{})
+		}},
+		{"MustUnmarshalMap", faststringmap.ErrInvalidEncoding, func() {
+			faststringmap.MustUnmarshalMap[uint32]([]byte("FSTM garbage"), faststringmap.IntCodec[uint32]{})
+		}},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, tt.want) {
+					t.Errorf("%s panicked with %v want %v", tt.name, err, tt.want)
+				}
+			}()
+			tt.fn()
+		}()
+	}
+}
@@ -0,0 +1,49 @@
+package faststringmap
+
+// Namespace[T] routes lookups to one of several maps, each mounted under a
+// key prefix. A key is looked up in the map mounted under its longest
+// mounted prefix, with that prefix stripped from the key. This allows
+// combining per-domain maps without rebuilding them into a single map.
+//
+// Mount must not be called concurrently with lookups.
+type Namespace[T any] struct {
+	prefixes Map[int] // mounted prefix -> index in maps
+	mounts   []MapEntry[int]
+	maps     []Map[T]
+}
+
+// Mount mounts m under the supplied prefix, replacing any map previously
+// mounted under the same prefix. The empty prefix mounts a map for all keys
+// that do not match a longer prefix.
+func (ns *Namespace[T]) Mount(prefix string, m Map[T]) {
+	for _, e := range ns.mounts {
+		if e.Key == prefix {
+			ns.maps[e.Value] = m
+			return
+		}
+	}
+
+	ns.mounts = append(ns.mounts, MapEntry[int]{prefix, len(ns.maps)})
+	ns.maps = append(ns.maps, m)
+	ns.prefixes = NewMap(ns.mounts)
+}
+
+// LookupString looks up the supplied string in the map mounted under its
+// longest mounted prefix.
+func (ns *Namespace[T]) LookupString(s string) (t T, ok bool) {
+	prefixLen, mi, ok := ns.prefixes.LongestPrefixString(s)
+	if !ok {
+		return t, false
+	}
+	return ns.maps[mi].LookupString(s[prefixLen:])
+}
+
+// LookupBytes looks up the supplied byte slice in the map mounted under its
+// longest mounted prefix.
+func (ns *Namespace[T]) LookupBytes(s []byte) (t T, ok bool) {
+	prefixLen, mi, ok := ns.prefixes.LongestPrefixBytes(s)
+	if !ok {
+		return t, false
+	}
+	return ns.maps[mi].LookupBytes(s[prefixLen:])
+}
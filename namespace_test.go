@@ -0,0 +1,40 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestNamespace(t *testing.T) {
+	var ns faststringmap.Namespace[int]
+	ns.Mount("color.", faststringmap.NewMap([]faststringmap.MapEntry[int]{{"red", 1}, {"blue", 2}}))
+	ns.Mount("color.dark.", faststringmap.NewMap([]faststringmap.MapEntry[int]{{"red", 3}}))
+	ns.Mount("", faststringmap.NewMap([]faststringmap.MapEntry[int]{{"other", 4}}))
+
+	for _, tc := range []struct {
+		key  string
+		want int
+		ok   bool
+	}{
+		{"color.red", 1, true},
+		{"color.blue", 2, true},
+		{"color.dark.red", 3, true},
+		{"color.dark.blue", 0, false}, // routed to the longest prefix only
+		{"other", 4, true},
+		{"color.", 0, false},
+		{"red", 0, false},
+	} {
+		if v, ok := ns.LookupString(tc.key); v != tc.want || ok != tc.ok {
+			t.Errorf("LookupString(%q) = %v, %v want %v, %v", tc.key, v, ok, tc.want, tc.ok)
+		}
+		if v, ok := ns.LookupBytes([]byte(tc.key)); v != tc.want || ok != tc.ok {
+			t.Errorf("LookupBytes(%q) = %v, %v want %v, %v", tc.key, v, ok, tc.want, tc.ok)
+		}
+	}
+
+	ns.Mount("color.dark.", faststringmap.NewMap([]faststringmap.MapEntry[int]{{"blue", 5}}))
+	if v, ok := ns.LookupString("color.dark.blue"); !ok || v != 5 {
+		t.Errorf("LookupString(color.dark.blue) after remount = %v, %v want 5, true", v, ok)
+	}
+}
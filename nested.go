@@ -0,0 +1,112 @@
+package faststringmap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NestedEntry[T] is for supplying data to initialize a new NestedMap.
+type NestedEntry[T any] struct {
+	Namespace string
+	Key       string
+	Value     T
+}
+
+// NestedMap[T] is a fast read only map from composite keys of a namespace
+// and a key to generic type T. It stores all entries in a single trie, with
+// a zero byte between namespace and key, and looks up composite keys by
+// walking the namespace and the key in turn, so probes never concatenate
+// strings.
+type NestedMap[T any] struct {
+	m Map[T]
+}
+
+// nestedSep separates namespaces from keys in the trie of a NestedMap.
+const nestedSep = "\x00"
+
+// errNestedTransform is returned for a NestedMap with a key transform, which
+// could only apply to whole composite keys.
+var errNestedTransform = errors.New("faststringmap: a NestedMap can not have a key transform")
+
+// NewNestedMap[T] constructs a new NestedMap from the provided entries and
+// options. It returns an error if a namespace contains a zero byte, if a
+// composite key appears more than once, or if the options set a key
+// transform. With WithFold, both namespaces and keys are case-insensitive.
+// The entries slice is not modified.
+func NewNestedMap[T any](entries []NestedEntry[T], opts ...Option) (NestedMap[T], error) {
+	var o buildOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.keyTransform != nil {
+		return NestedMap[T]{}, errNestedTransform
+	}
+
+	flat := make([]MapEntry[T], len(entries))
+	for i, e := range entries {
+		for j := 0; j < len(e.Namespace); j++ {
+			if e.Namespace[j] == nestedSep[0] {
+				return NestedMap[T]{}, fmt.Errorf("faststringmap: namespace %q contains a zero byte", e.Namespace)
+			}
+		}
+		flat[i] = MapEntry[T]{e.Namespace + nestedSep + e.Key, e.Value}
+	}
+
+	m, err := New(flat, opts...)
+	if err != nil {
+		return NestedMap[T]{}, err
+	}
+	return NestedMap[T]{m: m}, nil
+}
+
+// Lookup2 looks up the key in the namespace ns. It never allocates.
+func (nm *NestedMap[T]) Lookup2(ns, key string) (t T, ok bool) {
+	return nm.m.AtIndex(lookup2(&nm.m, ns, key))
+}
+
+// Lookup2Bytes looks up the key in the namespace ns, like Lookup2. It does
+// not retain ns or key.
+func (nm *NestedMap[T]) Lookup2Bytes(ns, key []byte) (t T, ok bool) {
+	return nm.m.AtIndex(lookup2(&nm.m, ns, key))
+}
+
+func lookup2[T any, S string | []byte](m *Map[T], ns, key S) Uint {
+	if len(m.store) == 0 {
+		return 0
+	}
+
+	node, h := descendFold(m, &m.store[0], ns, m.fingerprintSeed)
+	if node != nil {
+		node, h = descendFold(m, node, nestedSep, h)
+	}
+	if node != nil {
+		node, h = descendFold(m, node, key, h)
+	}
+	if node == nil || node.valueOffset == 0 {
+		return 0
+	}
+	if m.fingerprints != nil {
+		return m.verifyFingerprint(node.valueOffset, byte(h^h>>8^h>>16^h>>24))
+	}
+	return node.valueOffset
+}
+
+// descendFold returns the node reached from node by the bytes of s like
+// descend, with upper case letters folded if the map folds them, and the
+// fingerprint hash h continued over the folded bytes.
+func descendFold[T any, S string | []byte](m *Map[T], node *mapInternalNode, s S, h uint32) (*mapInternalNode, uint32) {
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if m.fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		h = (h ^ uint32(b)) * 16777619
+
+		ni := b - node.nextOffset // bytes below nextOffset wrap around past nextLen
+		if ni >= node.nextLen {
+			return nil, h
+		}
+		node = &m.store[node.nextLo+uint32(ni)]
+	}
+	return node, h
+}
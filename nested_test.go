@@ -0,0 +1,72 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestNestedMap(t *testing.T) {
+	entries := []faststringmap.NestedEntry[int]{
+		{"http", "get", 1},
+		{"http", "post", 2},
+		{"grpc", "get", 3},
+		{"", "root", 4},
+		{"http", "", 5},
+		{"httpx", "get", 6},
+	}
+	nm, err := faststringmap.NewNestedMap(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range entries {
+		if v, ok := nm.Lookup2(e.Namespace, e.Key); !ok || v != e.Value {
+			t.Errorf("Lookup2(%q, %q) = %v, %v want %v, true", e.Namespace, e.Key, v, ok, e.Value)
+		}
+		if v, ok := nm.Lookup2Bytes([]byte(e.Namespace), []byte(e.Key)); !ok || v != e.Value {
+			t.Errorf("Lookup2Bytes(%q, %q) = %v, %v want %v, true", e.Namespace, e.Key, v, ok, e.Value)
+		}
+	}
+	for _, k := range [][2]string{{"http", "put"}, {"htt", "pget"}, {"httpget", ""}, {"grpc", "post"}, {"", ""}} {
+		if v, ok := nm.Lookup2(k[0], k[1]); ok {
+			t.Errorf("Lookup2(%q, %q) = %v, true want false", k[0], k[1], v)
+		}
+	}
+
+	ns, key := "http", "post"
+	if allocs := testing.AllocsPerRun(100, func() { nm.Lookup2(ns, key) }); allocs != 0 {
+		t.Errorf("Lookup2 allocates %v times per run want 0", allocs)
+	}
+
+	if _, err := faststringmap.NewNestedMap([]faststringmap.NestedEntry[int]{{"a\x00b", "c", 1}}); err == nil {
+		t.Error("NewNestedMap() accepted a namespace with a zero byte")
+	}
+	if _, err := faststringmap.NewNestedMap(append(entries, entries[0])); err == nil {
+		t.Error("NewNestedMap() accepted duplicate keys")
+	}
+}
+
+func TestNestedMapFold(t *testing.T) {
+	entries := []faststringmap.NestedEntry[int]{{"HTTP", "Get", 1}, {"grpc", "get", 2}}
+	nm, err := faststringmap.NewNestedMap(entries, faststringmap.WithFold(), faststringmap.WithFingerprints())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range [][2]string{{"http", "get"}, {"HTTP", "GET"}, {"Http", "gEt"}} {
+		if v, ok := nm.Lookup2(k[0], k[1]); v != 1 || !ok {
+			t.Errorf("Lookup2(%q, %q) = %v, %v want 1, true", k[0], k[1], v, ok)
+		}
+	}
+	if v, ok := nm.Lookup2Bytes([]byte("GRPC"), []byte("GET")); v != 2 || !ok {
+		t.Errorf("Lookup2Bytes(GRPC, GET) = %v, %v want 2, true", v, ok)
+	}
+	if v, ok := nm.Lookup2("HTTPG", "ET"); ok {
+		t.Errorf("Lookup2(HTTPG, ET) = %v, true want false", v)
+	}
+
+	if _, err := faststringmap.NewNestedMap(entries, faststringmap.WithKeyTransform(bytes.TrimSpace)); err == nil {
+		t.Error("NewNestedMap() accepted a key transform")
+	}
+}
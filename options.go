@@ -0,0 +1,141 @@
+package faststringmap
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrDuplicateKey is returned when constructing a map from entries that
+// contain the same key more than once, unless a DuplicatePolicy says which
+// of the entries to keep.
+var ErrDuplicateKey = errors.New("faststringmap: duplicate key")
+
+// DuplicatePolicy selects how a map is constructed from entries that
+// contain the same key more than once.
+type DuplicatePolicy uint8
+
+const (
+	// DuplicatesError rejects duplicate keys. This is the default.
+	DuplicatesError DuplicatePolicy = iota
+	// DuplicatesKeepFirst keeps the value of the first entry with a key.
+	DuplicatesKeepFirst
+	// DuplicatesKeepLast keeps the value of the last entry with a key.
+	DuplicatesKeepLast
+)
+
+// Option configures the construction of a Map by New.
+type Option func(*buildOptions)
+
+// buildOptions holds the construction knobs shared by New and Builder.
+type buildOptions struct {
+	retainKeys       bool
+	withFingerprints bool
+	fixedSalt        bool   // whether salt was set by WithFingerprintSalt
+	salt             uint64 // fingerprint salt of the current build
+	duplicates       DuplicatePolicy
+	keyTransform     func([]byte) []byte // canonicalizes keys and probes, if set
+	fold             bool                // folds ASCII case of keys and probes
+
+	analyzeDuplicates bool              // whether builds report near-duplicate keys
+	metadata          map[string]string // attached to built maps
+	logger            *slog.Logger      // receives the timings of build phases, if set
+
+	wasteThreshold    int  // wasted slots per node above which builds warn
+	wasteThresholdSet bool // whether wasteThreshold was set, instead of the default
+	strictWaste       bool // whether builds fail instead of warning
+
+	firstBlockSize int // nodes in the first block of a build, if positive
+	maxBlockSize   int // maximum nodes in a block, if positive
+	entriesHint    int // expected number of entries, if positive
+	nodesHint      int // expected number of nodes, if positive
+
+	alloc func(size, align int) []byte // allocates built node stores and values, if set
+}
+
+// WithRetainKeys makes the map retain the original key strings. See
+// Builder.SetRetainKeys.
+func WithRetainKeys() Option {
+	return func(o *buildOptions) { o.retainKeys = true }
+}
+
+// WithFingerprints makes the map store an 8-bit fingerprint of every key.
+// See Builder.SetFingerprints.
+func WithFingerprints() Option {
+	return func(o *buildOptions) { o.withFingerprints = true }
+}
+
+// WithFingerprintSalt sets the salt of the fingerprints of the map, instead
+// of a random salt. It has no effect unless fingerprints are enabled. See
+// Builder.SetFingerprintSalt.
+func WithFingerprintSalt(salt uint64) Option {
+	return func(o *buildOptions) {
+		o.salt = salt
+		o.fixedSalt = true
+	}
+}
+
+// WithDuplicates sets how entries with duplicate keys are handled.
+func WithDuplicates(policy DuplicatePolicy) Option {
+	return func(o *buildOptions) { o.duplicates = policy }
+}
+
+// New constructs a new Map from the entries, configured by opts. Unlike
+// NewMap, New reports duplicate keys as an error wrapping ErrDuplicateKey,
+// unless WithDuplicates says which entry to keep. NewMap(entries) is the
+// same as New(entries) for entries with unique keys.
+func New[T any](entries []MapEntry[T], opts ...Option) (Map[T], error) {
+	var b Builder[T]
+	b.SetOptions(opts...)
+	if b.canonicalizes() {
+		entries = canonicalEntries(entries, &b.buildOptions)
+	}
+	b.entries = entries
+	return b.build()
+}
+
+// SetOptions applies opts to the builder, for all following builds.
+func (b *Builder[T]) SetOptions(opts ...Option) {
+	for _, opt := range opts {
+		opt(&b.buildOptions)
+	}
+}
+
+// SetDuplicates sets how the builder handles entries with duplicate keys.
+// By default, Build panics and BuildInto fails on duplicate keys.
+func (b *Builder[T]) SetDuplicates(policy DuplicatePolicy) {
+	b.duplicates = policy
+}
+
+// removeDuplicates drops entries with duplicate keys from b.order, as
+// chosen by the duplicate policy. Entries with equal keys are adjacent in
+// b.order, but not necessarily in the order they were added.
+func (b *Builder[T]) removeDuplicates() error {
+	if len(b.order) < 2 {
+		return nil
+	}
+
+	out := b.order[:1]
+	for _, i := range b.order[1:] {
+		last := &out[len(out)-1]
+		if b.key(i) != b.key(*last) {
+			out = append(out, i)
+			continue
+		}
+
+		switch b.duplicates {
+		case DuplicatesKeepFirst:
+			if i < *last {
+				*last = i
+			}
+		case DuplicatesKeepLast:
+			if i > *last {
+				*last = i
+			}
+		default:
+			return fmt.Errorf("%w %q", ErrDuplicateKey, b.key(i))
+		}
+	}
+	b.order = out
+	return nil
+}
@@ -0,0 +1,87 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestNewOptions(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{{"b", 2}, {"a", 1}, {"ab", 3}}
+	m, err := faststringmap.New(entries,
+		faststringmap.WithRetainKeys(),
+		faststringmap.WithFingerprints(),
+		faststringmap.WithFingerprintSalt(42),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if v, ok := m.LookupString(e.Key); !ok || v != e.Value {
+			t.Errorf("LookupString(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+		if k, _, ok := m.LookupKey(e.Key); !ok || k != e.Key {
+			t.Errorf("LookupKey(%q) = %q, %v", e.Key, k, ok)
+		}
+	}
+	if salt := m.FingerprintSalt(); salt != 42 {
+		t.Errorf("FingerprintSalt() = %d want 42", salt)
+	}
+}
+
+func TestNewDuplicates(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{{"a", 1}, {"b", 2}, {"a", 3}, {"c", 4}, {"a", 5}}
+
+	if _, err := faststringmap.New(entries); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("New() error = %v want ErrDuplicateKey", err)
+	}
+
+	tests := []struct {
+		policy faststringmap.DuplicatePolicy
+		want   int
+	}{
+		{faststringmap.DuplicatesKeepFirst, 1},
+		{faststringmap.DuplicatesKeepLast, 5},
+	}
+	for _, tt := range tests {
+		m, err := faststringmap.New(entries, faststringmap.WithDuplicates(tt.policy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, ok := m.LookupString("a"); !ok || v != tt.want {
+			t.Errorf("policy %d: LookupString(a) = %v, %v want %v, true", tt.policy, v, ok, tt.want)
+		}
+		if v, ok := m.LookupString("c"); !ok || v != 4 {
+			t.Errorf("policy %d: LookupString(c) = %v, %v want 4, true", tt.policy, v, ok)
+		}
+	}
+}
+
+func TestBuilderDuplicates(t *testing.T) {
+	var b faststringmap.Builder[int]
+	b.Add("x", 1)
+	b.Add("x", 2)
+
+	if _, _, err := b.BuildInto(make([]byte, 1024)); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("BuildInto() error = %v want ErrDuplicateKey", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Build() did not panic on duplicate keys")
+			}
+		}()
+		b.Build()
+	}()
+
+	b.SetDuplicates(faststringmap.DuplicatesKeepLast)
+	m := b.Build()
+	if v, ok := m.LookupString("x"); !ok || v != 2 {
+		t.Errorf("LookupString(x) = %v, %v want 2, true", v, ok)
+	}
+	if r := b.Report(); r.Entries != 1 {
+		t.Errorf("Report().Entries = %d want 1", r.Entries)
+	}
+}
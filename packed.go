@@ -0,0 +1,248 @@
+package faststringmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"unsafe"
+)
+
+// PackedMap[T] is a fast read only map from string to generic type T, which
+// packs runs of nodes that have a single child and accept no key into byte
+// runs stored in a side arena, as a cheaper alternative to full path
+// compression. Nodes keep the layout of Map, so the memory saved is that of
+// the packed nodes, less the bytes of the runs. It suits sets of long keys
+// sharing few prefixes, such as URLs or file paths, and saves little for
+// short or dense keys; compare both with Stats().Bytes of Map and Bytes of
+// PackedMap before choosing.
+type PackedMap[T any] struct {
+	store  []mapInternalNode
+	chains []byte // packed runs, see packChains
+	values []T
+
+	keyTransform func([]byte) []byte
+	fold         bool
+}
+
+const (
+	// packedChain is the nextOffset of nodes followed by a packed run, which
+	// are told apart from leaves, whose nextOffset is 0, by it.
+	packedChain = 1
+
+	minChainLen = 2   // shortest run of bytes worth packing
+	maxChainLen = 255 // longest run of bytes in a single packed run
+)
+
+var errChainsTooLarge = errors.New("faststringmap: packed runs do not fit in a map")
+
+// NewPackedMap[T] constructs a new PackedMap from the provided map entries,
+// configured by opts like New. Options for fingerprints and retained keys
+// have no effect: lookups in a PackedMap always compare every byte of the
+// probe, and it can not return keys. The entries slice is not modified.
+func NewPackedMap[T any](entries []MapEntry[T], opts ...Option) (PackedMap[T], error) {
+	m, err := New(entries, opts...)
+	if err != nil {
+		return PackedMap[T]{}, err
+	}
+	if len(m.store) == 0 {
+		return PackedMap[T]{}, nil
+	}
+
+	store, chains, err := packChains(m.store)
+	if err != nil {
+		return PackedMap[T]{}, err
+	}
+	return PackedMap[T]{store: store, chains: chains, values: m.values, keyTransform: m.keyTransform, fold: m.fold}, nil
+}
+
+// packChains returns a copy of a store built by a Builder, with runs of at
+// least minChainLen edges through nodes that have a single child and accept
+// no key replaced by a packed run. The node the run starts at has nextLen 0,
+// nextOffset packedChain, and nextLo the offset in chains of the run: the
+// index of the node at its end as 4 little-endian bytes, its length in
+// bytes as 1 byte, and its bytes.
+func packChains(store []mapInternalNode) ([]mapInternalNode, []byte, error) {
+	type move struct{ from, to Uint }
+
+	packed := make([]mapInternalNode, 1, len(store))
+	var chains, run []byte
+	queue := []move{{0, 0}}
+	for head := 0; head < len(queue); head++ {
+		mv := queue[head]
+		node := &store[mv.from]
+		out := mapInternalNode{valueOffset: node.valueOffset}
+
+		run = run[:0]
+		end := mv.from
+		for len(run) < maxChainLen && store[end].nextLen == 1 && (end == mv.from || store[end].valueOffset == 0) {
+			run = append(run, store[end].nextOffset)
+			end = store[end].nextLo
+		}
+
+		switch {
+		case len(run) >= minChainLen:
+			if uint64(len(chains)) > math.MaxUint32 {
+				return nil, nil, errChainsTooLarge
+			}
+			out.nextOffset, out.nextLo = packedChain, Uint(len(chains))
+			chains = binary.LittleEndian.AppendUint32(chains, Uint(len(packed)))
+			chains = append(chains, byte(len(run)))
+			chains = append(chains, run...)
+			queue = append(queue, move{end, Uint(len(packed))})
+			packed = append(packed, mapInternalNode{})
+		case node.nextLen > 0:
+			out.nextLo, out.nextLen, out.nextOffset = Uint(len(packed)), node.nextLen, node.nextOffset
+			for c := Uint(0); c < Uint(node.nextLen); c++ {
+				queue = append(queue, move{node.nextLo + c, Uint(len(packed)) + c})
+			}
+			packed = append(packed, make([]mapInternalNode, node.nextLen)...)
+		}
+		packed[mv.to] = out
+	}
+	return packed, chains, nil
+}
+
+// countPacked returns the number of nodes and the bytes of packed runs of
+// a PackedMap of the sorted, distinct entries in b.order, mirroring
+// packChains on the store a build would make, without making it.
+func (b *Builder[T]) countPacked() (nodes, chainBytes int) {
+	if len(b.order) == 0 {
+		return 1, 0
+	}
+
+	nodes = 1
+	stack := append(b.stack[:0], buildFrame{order: b.order})
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// follow the run of nodes with a single child accepting no key
+		run := 0
+		for run < maxChainLen {
+			order := f.order
+			if len(b.key(order[0])) == f.entryIndex {
+				if run > 0 {
+					break
+				}
+				order = order[1:]
+			}
+			if len(order) == 0 || b.key(order[0])[f.entryIndex] != b.key(order[len(order)-1])[f.entryIndex] {
+				break
+			}
+			f = buildFrame{order: order, entryIndex: f.entryIndex + 1}
+			run++
+		}
+
+		switch {
+		case run >= minChainLen:
+			nodes++
+			chainBytes += 5 + run
+			stack = append(stack, f)
+			continue
+		case run == 1:
+			nodes++
+			stack = append(stack, f)
+			continue
+		}
+
+		order := f.order
+		if len(b.key(order[0])) == f.entryIndex {
+			order = order[1:]
+		}
+		if len(order) == 0 {
+			continue
+		}
+		nodes += int(b.key(order[len(order)-1])[f.entryIndex]-b.key(order[0])[f.entryIndex]) + 1
+		for i := 0; i < len(order); {
+			c := b.key(order[i])[f.entryIndex]
+			iSameByteHi := i + 1
+			for iSameByteHi < len(order) && b.key(order[iSameByteHi])[f.entryIndex] == c {
+				iSameByteHi++
+			}
+			stack = append(stack, buildFrame{order: order[i:iSameByteHi], entryIndex: f.entryIndex + 1})
+			i = iSameByteHi
+		}
+	}
+	clear(stack[:cap(stack)])
+	b.stack = stack[:0]
+	return nodes, chainBytes
+}
+
+// Len returns the number of keys in the map.
+func (pm *PackedMap[T]) Len() int {
+	return len(pm.values)
+}
+
+// Bytes returns the number of bytes used by the nodes, packed runs and
+// values of the map.
+func (pm *PackedMap[T]) Bytes() int {
+	var zero T
+	return len(pm.store)*nodeSize + len(pm.chains) + len(pm.values)*int(unsafe.Sizeof(zero))
+}
+
+// LookupString looks up the supplied string in the map.
+func (pm *PackedMap[T]) LookupString(s string) (t T, ok bool) {
+	if pm.keyTransform != nil {
+		return pm.LookupBytes(stringBytes(s))
+	}
+	return pm.at(packedNodeOf(pm, s))
+}
+
+// LookupBytes looks up the supplied byte slice in the map. It never
+// allocates and does not retain s, unless the map has a key transform that
+// does.
+func (pm *PackedMap[T]) LookupBytes(s []byte) (t T, ok bool) {
+	if pm.keyTransform != nil {
+		s = pm.keyTransform(s)
+	}
+	return pm.at(packedNodeOf(pm, s))
+}
+
+func (pm *PackedMap[T]) at(node *mapInternalNode) (t T, ok bool) {
+	if node == nil || node.valueOffset == 0 {
+		return t, false
+	}
+	return pm.values[node.valueOffset-1], true
+}
+
+// packedNodeOf returns the node reached by the bytes of s, or nil if there
+// is none.
+func packedNodeOf[T any, S string | []byte](pm *PackedMap[T], s S) *mapInternalNode {
+	if len(pm.store) == 0 {
+		return nil
+	}
+
+	bv := &pm.store[0]
+	for i, n := 0, len(s); i < n; {
+		b := s[i]
+		if pm.fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		if ni := b - bv.nextOffset; ni < bv.nextLen {
+			bv = &pm.store[bv.nextLo+uint32(ni)]
+			i++
+			continue
+		}
+		if bv.nextLen != 0 || bv.nextOffset != packedChain {
+			return nil
+		}
+
+		chain := pm.chains[bv.nextLo:]
+		run := chain[5 : 5+int(chain[4])]
+		if len(run) > n-i {
+			return nil
+		}
+		for j, c := range run {
+			b := s[i+j]
+			if pm.fold && 'A' <= b && b <= 'Z' {
+				b += 'a' - 'A'
+			}
+			if b != c {
+				return nil
+			}
+		}
+		i += len(run)
+		bv = &pm.store[binary.LittleEndian.Uint32(chain)]
+	}
+	return bv
+}
@@ -0,0 +1,125 @@
+package faststringmap_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+// urlEntries returns entries with long keys sharing few prefixes, whose
+// tries have long runs of single-child nodes.
+func urlEntries(n int) []faststringmap.MapEntry[uint32] {
+	entries := make([]faststringmap.MapEntry[uint32], n)
+	for i := range entries {
+		key := fmt.Sprintf("https://example.com/%d/articles/%08x-introduction", i%7, uint32(i)*2654435761)
+		entries[i] = faststringmap.MapEntry[uint32]{Key: key, Value: uint32(i)}
+	}
+	return entries
+}
+
+func TestPackedMap(t *testing.T) {
+	sets := map[string][]faststringmap.MapEntry[uint32]{
+		"random": randomSmallStrings(2000, 8),
+		"urls":   urlEntries(2000),
+		"chains": {{"a", 1}, {"abcdef", 2}, {"abcdefghij", 3}, {"abcdx", 4}, {strings.Repeat("z", 600), 5}},
+	}
+	for name, entries := range sets {
+		m := faststringmap.NewMap(entries)
+		pm, err := faststringmap.NewPackedMap(entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pm.Len() != len(entries) {
+			t.Errorf("%s: Len() = %d want %d", name, pm.Len(), len(entries))
+		}
+
+		for _, e := range entries {
+			if v, ok := pm.LookupString(e.Key); !ok || v != e.Value {
+				t.Errorf("%s: LookupString(%q) = %v, %v want %v, true", name, e.Key, v, ok, e.Value)
+			}
+			if v, ok := pm.LookupBytes([]byte(e.Key)); !ok || v != e.Value {
+				t.Errorf("%s: LookupBytes(%q) = %v, %v want %v, true", name, e.Key, v, ok, e.Value)
+			}
+
+			// probes ending inside, diverging from and extending runs
+			if e.Key == "" {
+				continue
+			}
+			for _, probe := range []string{e.Key[:len(e.Key)/2], e.Key[:len(e.Key)-1] + "\x00", e.Key + "/", e.Key + "x"} {
+				want, wantOK := m.LookupString(probe)
+				if v, ok := pm.LookupString(probe); v != want || ok != wantOK {
+					t.Errorf("%s: LookupString(%q) = %v, %v want %v, %v", name, probe, v, ok, want, wantOK)
+				}
+			}
+		}
+	}
+
+	urls := urlEntries(2000)
+	m := faststringmap.NewMap(urls)
+	pm, err := faststringmap.NewPackedMap(urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := m.Stats(); pm.Bytes() >= stats.Bytes/2 {
+		t.Errorf("Bytes() = %d, not less than half of the %d bytes of Map", pm.Bytes(), stats.Bytes)
+	}
+
+	key := []byte(urls[0].Key)
+	if n := testing.AllocsPerRun(100, func() { pm.LookupBytes(key) }); n != 0 {
+		t.Errorf("LookupBytes allocates %v times", n)
+	}
+
+	var empty faststringmap.PackedMap[uint32]
+	if v, ok := empty.LookupString(""); ok {
+		t.Errorf("empty LookupString(\"\") = %v, true", v)
+	}
+}
+
+func TestPackedMapOptions(t *testing.T) {
+	pm, err := faststringmap.NewPackedMap([]faststringmap.MapEntry[int]{{"Alphabetical", 1}, {" beta", 2}},
+		faststringmap.WithFold(), faststringmap.WithKeyTransform(func(b []byte) []byte { return []byte(strings.TrimSpace(string(b))) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for probe, want := range map[string]int{"ALPHABETICAL": 1, " alphaBETical ": 1, "Beta": 2} {
+		if v, ok := pm.LookupString(probe); !ok || v != want {
+			t.Errorf("LookupString(%q) = %v, %v want %v, true", probe, v, ok, want)
+		}
+	}
+	if v, ok := pm.LookupString("alphabet"); ok {
+		t.Errorf("LookupString(alphabet) = %v, true", v)
+	}
+}
+
+func BenchmarkPackedMap(b *testing.B) {
+	sets := map[string][]faststringmap.MapEntry[uint32]{
+		"random": randomSmallStrings(nStrsBench, 8),
+		"urls":   urlEntries(nStrsBench),
+	}
+	for name, entries := range sets {
+		fm := faststringmap.NewMap(entries)
+		pm, err := faststringmap.NewPackedMap(entries)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(name+"/Map", func(b *testing.B) {
+			for bi := 0; bi < b.N; bi++ {
+				for _, e := range entries {
+					fm.LookupString(e.Key)
+				}
+			}
+			b.ReportMetric(float64(fm.Stats().Bytes), "bytes")
+		})
+		b.Run(name+"/PackedMap", func(b *testing.B) {
+			for bi := 0; bi < b.N; bi++ {
+				for _, e := range entries {
+					pm.LookupString(e.Key)
+				}
+			}
+			b.ReportMetric(float64(pm.Bytes()), "bytes")
+		})
+	}
+}
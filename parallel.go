@@ -0,0 +1,56 @@
+package faststringmap
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const parallelBatchSize = 4096 // keys looked up by a worker per batch it takes
+
+// ParallelLookupAll looks up every key of keys, with up to parallelism
+// goroutines each taking batches of keys in turn, and returns the values
+// and whether each key was found, in the order of keys. A parallelism less
+// than 1 uses runtime.GOMAXPROCS(0) goroutines. Small sets of keys are
+// looked up by the calling goroutine alone, as starting goroutines would
+// take longer than the lookups.
+func (m *Map[T]) ParallelLookupAll(keys []string, parallelism int) ([]T, []bool) {
+	values := make([]T, len(keys))
+	found := make([]bool, len(keys))
+
+	lookup := func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			values[i], found[i] = m.LookupString(keys[i])
+		}
+	}
+
+	batches := (len(keys) + parallelBatchSize - 1) / parallelBatchSize
+	if parallelism < 1 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	if parallelism > batches {
+		parallelism = batches
+	}
+	if parallelism <= 1 {
+		lookup(0, len(keys))
+		return values, found
+	}
+
+	var next int64 = -1 // index of the last batch taken by a worker
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				b := int(atomic.AddInt64(&next, 1))
+				if b >= batches {
+					return
+				}
+				lookup(b*parallelBatchSize, min((b+1)*parallelBatchSize, len(keys)))
+			}
+		}()
+	}
+	wg.Wait()
+	return values, found
+}
@@ -0,0 +1,49 @@
+package faststringmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestParallelLookupAll(t *testing.T) {
+	entries := randomSmallStrings(20000, 8)
+	m := faststringmap.NewMap(entries[:len(entries)/2])
+
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+	for _, parallelism := range []int{0, 1, 3, 100} {
+		values, found := m.ParallelLookupAll(keys, parallelism)
+		if len(values) != len(keys) || len(found) != len(keys) {
+			t.Fatalf("ParallelLookupAll(%d) returned %d values and %d found", parallelism, len(values), len(found))
+		}
+		for i, key := range keys {
+			if v, ok := m.LookupString(key); values[i] != v || found[i] != ok {
+				t.Fatalf("ParallelLookupAll(%d)[%d] = %v, %v want %v, %v", parallelism, i, values[i], found[i], v, ok)
+			}
+		}
+	}
+
+	if values, found := m.ParallelLookupAll(nil, 4); len(values) != 0 || len(found) != 0 {
+		t.Errorf("ParallelLookupAll(nil) = %v, %v", values, found)
+	}
+}
+
+func BenchmarkParallelLookupAll(b *testing.B) {
+	entries := randomSmallStrings(1<<18, 16)
+	m := faststringmap.NewMap(entries)
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+	for _, parallelism := range []int{1, 4, 0} {
+		b.Run(fmt.Sprint(parallelism), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				m.ParallelLookupAll(keys, parallelism)
+			}
+		})
+	}
+}
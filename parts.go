@@ -0,0 +1,53 @@
+package faststringmap
+
+// LookupParts looks up the key made of the supplied parts, as if they were
+// concatenated, such as a header, a name and a suffix assembled at runtime.
+// It walks the trie across the parts without concatenating them, so it
+// never allocates and does not retain the parts, unless the map has a key
+// transform, which is applied to the concatenated key.
+func (m *Map[T]) LookupParts(parts ...[]byte) (t T, ok bool) {
+	return m.AtIndex(m.IndexParts(parts...))
+}
+
+// IndexParts returns the index of the value for the key made of the
+// supplied parts, like LookupParts, or 0 if the key is not present in the
+// map.
+func (m *Map[T]) IndexParts(parts ...[]byte) Uint {
+	if m == nil || len(m.store) == 0 {
+		return 0
+	}
+	if m.keyTransform != nil {
+		var key []byte
+		for _, p := range parts {
+			key = append(key, p...)
+		}
+		return m.IndexBytes(key)
+	}
+
+	h := m.fingerprintSeed // fingerprint of the key, computed on the way
+	bv := &m.store[0]
+	for _, p := range parts {
+		for _, b := range p {
+			if m.fold && 'A' <= b && b <= 'Z' {
+				b += 'a' - 'A'
+			}
+			h = (h ^ uint32(b)) * 16777619
+
+			ni := b - bv.nextOffset // bytes below nextOffset wrap around past nextLen
+			if ni >= bv.nextLen {
+				return 0
+			}
+			bv = &m.store[bv.nextLo+uint32(ni)]
+		}
+	}
+
+	if bv.valueOffset == 0 {
+		return 0
+	}
+
+	if m.fingerprints != nil {
+		return m.verifyFingerprint(bv.valueOffset, byte(h^h>>8^h>>16^h>>24))
+	}
+
+	return bv.valueOffset
+}
@@ -0,0 +1,62 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestLookupParts(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{
+		{"x-header-name", 1},
+		{"x-header-name-suffix", 2},
+		{"x-other", 3},
+		{"", 4},
+	}
+	optionSets := map[string][]faststringmap.Option{
+		"default":      nil,
+		"fingerprints": {faststringmap.WithFingerprints()},
+		"fold":         {faststringmap.WithFold()},
+		"transform":    {faststringmap.WithKeyTransform(bytes.TrimSpace)},
+	}
+	for name, opts := range optionSets {
+		m, err := faststringmap.New(entries, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			parts [][]byte
+			want  int
+			ok    bool
+		}{
+			{[][]byte{[]byte("x-"), []byte("header"), []byte("-name")}, 1, true},
+			{[][]byte{[]byte("x-header-name"), []byte("-suffix")}, 2, true},
+			{[][]byte{[]byte("x-"), nil, []byte("other")}, 3, true},
+			{[][]byte{[]byte("x-"), []byte("header")}, 0, false},
+			{[][]byte{[]byte("x-"), []byte("others")}, 0, false},
+			{nil, 4, true},
+			{[][]byte{{}, {}}, 4, true},
+		}
+		for _, tt := range tests {
+			v, ok := m.LookupParts(tt.parts...)
+			if v != tt.want || ok != tt.ok {
+				t.Errorf("%s: LookupParts(%q) = %v, %v want %v, %v", name, tt.parts, v, ok, tt.want, tt.ok)
+			}
+		}
+	}
+
+	m, err := faststringmap.New(entries, faststringmap.WithFold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := m.LookupParts([]byte("X-Header"), []byte("-NAME")); !ok || v != 1 {
+		t.Errorf("LookupParts(X-Header, -NAME) = %v, %v want 1, true", v, ok)
+	}
+
+	header, name := []byte("x-header"), []byte("-name")
+	if allocs := testing.AllocsPerRun(100, func() { m.LookupParts(header, name) }); allocs != 0 {
+		t.Errorf("LookupParts allocates %v times per run want 0", allocs)
+	}
+}
@@ -0,0 +1,96 @@
+package faststringmap
+
+// CommonPrefix returns the longest prefix shared by all keys in the map,
+// read from the path of non-accepting nodes with a single child from the
+// root. Keys are as stored, so they are canonical if the map was built with
+// WithFold or WithKeyTransform. An empty map has an empty common prefix.
+func (m *Map[T]) CommonPrefix() string {
+	if m == nil || len(m.store) == 0 {
+		return ""
+	}
+
+	var prefix []byte
+	node := &m.store[0]
+	for node.valueOffset == 0 {
+		child, b, ok := m.onlyChild(node)
+		if !ok {
+			break
+		}
+		prefix = append(prefix, b)
+		node = child
+	}
+	return string(prefix)
+}
+
+// onlyChild returns the child of node and the byte leading to it, if node
+// has exactly one child.
+func (m *Map[T]) onlyChild(node *mapInternalNode) (child *mapInternalNode, b byte, ok bool) {
+	for i := Uint(0); i < Uint(node.nextLen); i++ {
+		next := &m.store[node.nextLo+i]
+		if next.valueOffset == 0 && next.nextLen == 0 {
+			continue // not a valid next byte
+		}
+		if child != nil {
+			return nil, 0, false
+		}
+		child, b = next, node.nextOffset+byte(i)
+	}
+	return child, b, child != nil
+}
+
+// IsPrefixOfAnyKey reports whether some key in the map starts with s,
+// including a key equal to s, such as when validating input as it is typed
+// or fed to a tokenizer. The probe is folded or transformed like the probes
+// of LookupString, if the map was built with WithFold or WithKeyTransform.
+func (m *Map[T]) IsPrefixOfAnyKey(s string) bool {
+	_, ok := m.prefixIndex(s)
+	return ok
+}
+
+// IsPrefixOfAnyKeyBytes reports whether some key in the map starts with s,
+// like IsPrefixOfAnyKey. It does not retain s.
+func (m *Map[T]) IsPrefixOfAnyKeyBytes(s []byte) bool {
+	if m != nil && m.keyTransform != nil {
+		s = m.keyTransform(s)
+	}
+	_, ok := prefixIndexOf(m, s)
+	return ok
+}
+
+// CountPrefix returns the number of keys in the map starting with p,
+// including p itself, like counting IndicesUnderPrefix(p) without
+// enumerating it. Values are stored in ascending key order, so the keys
+// under a prefix have consecutive indices, and counting them takes a walk
+// down to the first and the last of them, whatever their number. Like
+// IndicesUnderPrefix, p is folded or transformed like a probe.
+func (m *Map[T]) CountPrefix(p string) int {
+	node := m.prefixNode(p)
+	if node == nil {
+		return 0
+	}
+
+	first, last := m.subtreeRange(node)
+	return int(last) - int(first) + 1
+}
+
+// subtreeRange returns the indices of the values of the first and the last
+// key of the subtree of a valid node, between which the values of all keys
+// of the subtree lie, or 1 and 0 if there are none. Validated stores number
+// values in ascending key order, so the range holds only those keys.
+func (m *Map[T]) subtreeRange(node *mapInternalNode) (first, last Uint) {
+	// the first and last slots of every range of next nodes are valid bytes
+	n := node
+	for n.valueOffset == 0 {
+		if n.nextLen == 0 {
+			return 1, 0
+		}
+		n = &m.store[n.nextLo]
+	}
+	first = n.valueOffset
+
+	n = node
+	for n.nextLen != 0 {
+		n = &m.store[n.nextLo+Uint(n.nextLen)-1]
+	}
+	return first, n.valueOffset
+}
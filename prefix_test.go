@@ -0,0 +1,167 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		keys []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"only"}, "only"},
+		{[]string{"/api/v1/users", "/api/v1/groups", "/api/v1/user"}, "/api/v1/"},
+		{[]string{"/api/v1/user", "/api/v1/users"}, "/api/v1/user"},
+		{[]string{"ab", "abc", "abd"}, "ab"},
+		{[]string{"", "a"}, ""},
+		{[]string{"a", "b"}, ""},
+		{[]string{"xa", "xz"}, "x"}, // children far apart in the byte range
+	}
+	for _, tt := range tests {
+		entries := make([]faststringmap.MapEntry[int], len(tt.keys))
+		for i, k := range tt.keys {
+			entries[i] = faststringmap.MapEntry[int]{Key: k, Value: i}
+		}
+		m := faststringmap.NewMap(entries)
+		if got := m.CommonPrefix(); got != tt.want {
+			t.Errorf("CommonPrefix() of %q = %q want %q", tt.keys, got, tt.want)
+		}
+	}
+}
+
+func TestIsPrefixOfAnyKey(t *testing.T) {
+	m, err := faststringmap.New([]faststringmap.MapEntry[int]{{"select", 1}, {"set", 2}, {"show", 3}},
+		faststringmap.WithFold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"", true},
+		{"s", true},
+		{"se", true},
+		{"SEL", true},
+		{"select", true},
+		{"selects", false},
+		{"sf", false}, // between the children of "s"
+		{"sex", false},
+		{"x", false},
+	}
+	for _, tt := range tests {
+		if got := m.IsPrefixOfAnyKey(tt.s); got != tt.want {
+			t.Errorf("IsPrefixOfAnyKey(%q) = %v want %v", tt.s, got, tt.want)
+		}
+		if got := m.IsPrefixOfAnyKeyBytes([]byte(tt.s)); got != tt.want {
+			t.Errorf("IsPrefixOfAnyKeyBytes(%q) = %v want %v", tt.s, got, tt.want)
+		}
+	}
+
+	var empty faststringmap.Map[int]
+	if empty.IsPrefixOfAnyKey("") {
+		t.Error("IsPrefixOfAnyKey(\"\") of an empty map = true")
+	}
+}
+
+func TestCountPrefix(t *testing.T) {
+	keys := []string{"", "a", "ab", "abc", "abd", "b", "ba", "bz", "c"}
+	entries := make([]faststringmap.MapEntry[int], len(keys))
+	for i, k := range keys {
+		entries[i] = faststringmap.MapEntry[int]{Key: k, Value: i}
+	}
+	m := faststringmap.NewMap(entries)
+
+	for _, p := range []string{"", "a", "ab", "abc", "abe", "b", "bm", "c", "d", "abcd"} {
+		want := 0
+		for range m.IndicesUnderPrefix(p) {
+			want++
+		}
+		if got := m.CountPrefix(p); got != want {
+			t.Errorf("CountPrefix(%q) = %d want %d", p, got, want)
+		}
+	}
+	if got := m.CountPrefix("ab"); got != 3 {
+		t.Errorf("CountPrefix(ab) = %d want 3", got)
+	}
+
+	var empty faststringmap.Map[int]
+	if got := empty.CountPrefix(""); got != 0 {
+		t.Errorf("CountPrefix(\"\") of an empty map = %d want 0", got)
+	}
+	built := faststringmap.NewMap[int](nil)
+	if got := built.CountPrefix(""); got != 0 {
+		t.Errorf("CountPrefix(\"\") of a map built without keys = %d want 0", got)
+	}
+
+	// a store whose root leads to a node continuing no key is rejected,
+	// instead of making CountPrefix read past the store
+	nodes := concat(encodedNode(1, 1, 'a', 0), encodedNode(3, 0, 0, 0), encodedNode(0, 0, 0, 1))
+	if _, err := faststringmap.FromEncodedNodes(nodes, []int{1}); err == nil {
+		t.Error("FromEncodedNodes() accepted a store with a dead child")
+	}
+}
+
+func TestPrefixCanonical(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{{"Abc", 1}, {"abd", 2}, {"b", 3}}
+	for _, tc := range []struct {
+		name   string
+		opts   []faststringmap.Option
+		prefix string
+	}{
+		{"fold", []faststringmap.Option{faststringmap.WithFold()}, "AB"},
+		{"transform", []faststringmap.Option{faststringmap.WithFold(), faststringmap.WithKeyTransform(bytes.TrimSpace)}, " aB "},
+	} {
+		m, err := faststringmap.New(entries, tc.opts...)
+		if err != nil {
+			t.Fatalf("%s: New() error: %v", tc.name, err)
+		}
+		want := []string{"abc", "abd"}
+
+		if !m.IsPrefixOfAnyKey(tc.prefix) {
+			t.Errorf("%s: IsPrefixOfAnyKey(%q) = false", tc.name, tc.prefix)
+		}
+		if got := m.CountPrefix(tc.prefix); got != 2 {
+			t.Errorf("%s: CountPrefix(%q) = %d want 2", tc.name, tc.prefix, got)
+		}
+		if got := slices.Collect(m.IndicesUnderPrefix(tc.prefix)); len(got) != 2 {
+			t.Errorf("%s: IndicesUnderPrefix(%q) = %v want 2 indices", tc.name, tc.prefix, got)
+		}
+
+		var keys, desc, walked []string
+		for k := range m.Prefix(tc.prefix) {
+			keys = append(keys, k)
+		}
+		for k := range m.PrefixDesc(tc.prefix) {
+			desc = append(desc, k)
+		}
+		m.WalkPrefix(tc.prefix, func(key []byte, _ int) bool {
+			walked = append(walked, string(key))
+			return true
+		})
+		slices.Reverse(desc)
+		for name, got := range map[string][]string{"Prefix": keys, "PrefixDesc": desc, "WalkPrefix": walked} {
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: %s(%q) = %q want %q", tc.name, name, tc.prefix, got, want)
+			}
+		}
+
+		var from []string
+		for k := range m.AllFrom(tc.prefix) {
+			from = append(from, k)
+		}
+		if !slices.Equal(from, []string{"abc", "abd", "b"}) {
+			t.Errorf("%s: AllFrom(%q) = %q", tc.name, tc.prefix, from)
+		}
+
+		top := m.TopKUnderPrefix(tc.prefix, 1, func(v int) float64 { return float64(v) })
+		if len(top) != 1 || top[0].Key != "abd" {
+			t.Errorf("%s: TopKUnderPrefix(%q) = %v want abd", tc.name, tc.prefix, top)
+		}
+	}
+}
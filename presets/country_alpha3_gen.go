@@ -0,0 +1,260 @@
+// Code generated by faststringmap.WriteGoSource; DO NOT EDIT.
+
+package presets
+
+import (
+	"alon.kr/x/faststringmap"
+)
+
+// CountryAlpha3 maps ISO 3166-1 alpha-3 country codes to alpha-2 country codes.
+var CountryAlpha3 = faststringmap.NewMap([]faststringmap.MapEntry[string]{
+	{Key: "ABW", Value: "AW"},
+	{Key: "AFG", Value: "AF"},
+	{Key: "AGO", Value: "AO"},
+	{Key: "AIA", Value: "AI"},
+	{Key: "ALA", Value: "AX"},
+	{Key: "ALB", Value: "AL"},
+	{Key: "AND", Value: "AD"},
+	{Key: "ARE", Value: "AE"},
+	{Key: "ARG", Value: "AR"},
+	{Key: "ARM", Value: "AM"},
+	{Key: "ASM", Value: "AS"},
+	{Key: "ATA", Value: "AQ"},
+	{Key: "ATF", Value: "TF"},
+	{Key: "ATG", Value: "AG"},
+	{Key: "AUS", Value: "AU"},
+	{Key: "AUT", Value: "AT"},
+	{Key: "AZE", Value: "AZ"},
+	{Key: "BDI", Value: "BI"},
+	{Key: "BEL", Value: "BE"},
+	{Key: "BEN", Value: "BJ"},
+	{Key: "BES", Value: "BQ"},
+	{Key: "BFA", Value: "BF"},
+	{Key: "BGD", Value: "BD"},
+	{Key: "BGR", Value: "BG"},
+	{Key: "BHR", Value: "BH"},
+	{Key: "BHS", Value: "BS"},
+	{Key: "BIH", Value: "BA"},
+	{Key: "BLM", Value: "BL"},
+	{Key: "BLR", Value: "BY"},
+	{Key: "BLZ", Value: "BZ"},
+	{Key: "BMU", Value: "BM"},
+	{Key: "BOL", Value: "BO"},
+	{Key: "BRA", Value: "BR"},
+	{Key: "BRB", Value: "BB"},
+	{Key: "BRN", Value: "BN"},
+	{Key: "BTN", Value: "BT"},
+	{Key: "BVT", Value: "BV"},
+	{Key: "BWA", Value: "BW"},
+	{Key: "CAF", Value: "CF"},
+	{Key: "CAN", Value: "CA"},
+	{Key: "CCK", Value: "CC"},
+	{Key: "CHE", Value: "CH"},
+	{Key: "CHL", Value: "CL"},
+	{Key: "CHN", Value: "CN"},
+	{Key: "CIV", Value: "CI"},
+	{Key: "CMR", Value: "CM"},
+	{Key: "COD", Value: "CD"},
+	{Key: "COG", Value: "CG"},
+	{Key: "COK", Value: "CK"},
+	{Key: "COL", Value: "CO"},
+	{Key: "COM", Value: "KM"},
+	{Key: "CPV", Value: "CV"},
+	{Key: "CRI", Value: "CR"},
+	{Key: "CUB", Value: "CU"},
+	{Key: "CUW", Value: "CW"},
+	{Key: "CXR", Value: "CX"},
+	{Key: "CYM", Value: "KY"},
+	{Key: "CYP", Value: "CY"},
+	{Key: "CZE", Value: "CZ"},
+	{Key: "DEU", Value: "DE"},
+	{Key: "DJI", Value: "DJ"},
+	{Key: "DMA", Value: "DM"},
+	{Key: "DNK", Value: "DK"},
+	{Key: "DOM", Value: "DO"},
+	{Key: "DZA", Value: "DZ"},
+	{Key: "ECU", Value: "EC"},
+	{Key: "EGY", Value: "EG"},
+	{Key: "ERI", Value: "ER"},
+	{Key: "ESH", Value: "EH"},
+	{Key: "ESP", Value: "ES"},
+	{Key: "EST", Value: "EE"},
+	{Key: "ETH", Value: "ET"},
+	{Key: "FIN", Value: "FI"},
+	{Key: "FJI", Value: "FJ"},
+	{Key: "FLK", Value: "FK"},
+	{Key: "FRA", Value: "FR"},
+	{Key: "FRO", Value: "FO"},
+	{Key: "FSM", Value: "FM"},
+	{Key: "GAB", Value: "GA"},
+	{Key: "GBR", Value: "GB"},
+	{Key: "GEO", Value: "GE"},
+	{Key: "GGY", Value: "GG"},
+	{Key: "GHA", Value: "GH"},
+	{Key: "GIB", Value: "GI"},
+	{Key: "GIN", Value: "GN"},
+	{Key: "GLP", Value: "GP"},
+	{Key: "GMB", Value: "GM"},
+	{Key: "GNB", Value: "GW"},
+	{Key: "GNQ", Value: "GQ"},
+	{Key: "GRC", Value: "GR"},
+	{Key: "GRD", Value: "GD"},
+	{Key: "GRL", Value: "GL"},
+	{Key: "GTM", Value: "GT"},
+	{Key: "GUF", Value: "GF"},
+	{Key: "GUM", Value: "GU"},
+	{Key: "GUY", Value: "GY"},
+	{Key: "HKG", Value: "HK"},
+	{Key: "HMD", Value: "HM"},
+	{Key: "HND", Value: "HN"},
+	{Key: "HRV", Value: "HR"},
+	{Key: "HTI", Value: "HT"},
+	{Key: "HUN", Value: "HU"},
+	{Key: "IDN", Value: "ID"},
+	{Key: "IMN", Value: "IM"},
+	{Key: "IND", Value: "IN"},
+	{Key: "IOT", Value: "IO"},
+	{Key: "IRL", Value: "IE"},
+	{Key: "IRN", Value: "IR"},
+	{Key: "IRQ", Value: "IQ"},
+	{Key: "ISL", Value: "IS"},
+	{Key: "ISR", Value: "IL"},
+	{Key: "ITA", Value: "IT"},
+	{Key: "JAM", Value: "JM"},
+	{Key: "JEY", Value: "JE"},
+	{Key: "JOR", Value: "JO"},
+	{Key: "JPN", Value: "JP"},
+	{Key: "KAZ", Value: "KZ"},
+	{Key: "KEN", Value: "KE"},
+	{Key: "KGZ", Value: "KG"},
+	{Key: "KHM", Value: "KH"},
+	{Key: "KIR", Value: "KI"},
+	{Key: "KNA", Value: "KN"},
+	{Key: "KOR", Value: "KR"},
+	{Key: "KWT", Value: "KW"},
+	{Key: "LAO", Value: "LA"},
+	{Key: "LBN", Value: "LB"},
+	{Key: "LBR", Value: "LR"},
+	{Key: "LBY", Value: "LY"},
+	{Key: "LCA", Value: "LC"},
+	{Key: "LIE", Value: "LI"},
+	{Key: "LKA", Value: "LK"},
+	{Key: "LSO", Value: "LS"},
+	{Key: "LTU", Value: "LT"},
+	{Key: "LUX", Value: "LU"},
+	{Key: "LVA", Value: "LV"},
+	{Key: "MAC", Value: "MO"},
+	{Key: "MAF", Value: "MF"},
+	{Key: "MAR", Value: "MA"},
+	{Key: "MCO", Value: "MC"},
+	{Key: "MDA", Value: "MD"},
+	{Key: "MDG", Value: "MG"},
+	{Key: "MDV", Value: "MV"},
+	{Key: "MEX", Value: "MX"},
+	{Key: "MHL", Value: "MH"},
+	{Key: "MKD", Value: "MK"},
+	{Key: "MLI", Value: "ML"},
+	{Key: "MLT", Value: "MT"},
+	{Key: "MMR", Value: "MM"},
+	{Key: "MNE", Value: "ME"},
+	{Key: "MNG", Value: "MN"},
+	{Key: "MNP", Value: "MP"},
+	{Key: "MOZ", Value: "MZ"},
+	{Key: "MRT", Value: "MR"},
+	{Key: "MSR", Value: "MS"},
+	{Key: "MTQ", Value: "MQ"},
+	{Key: "MUS", Value: "MU"},
+	{Key: "MWI", Value: "MW"},
+	{Key: "MYS", Value: "MY"},
+	{Key: "MYT", Value: "YT"},
+	{Key: "NAM", Value: "NA"},
+	{Key: "NCL", Value: "NC"},
+	{Key: "NER", Value: "NE"},
+	{Key: "NFK", Value: "NF"},
+	{Key: "NGA", Value: "NG"},
+	{Key: "NIC", Value: "NI"},
+	{Key: "NIU", Value: "NU"},
+	{Key: "NLD", Value: "NL"},
+	{Key: "NOR", Value: "NO"},
+	{Key: "NPL", Value: "NP"},
+	{Key: "NRU", Value: "NR"},
+	{Key: "NZL", Value: "NZ"},
+	{Key: "OMN", Value: "OM"},
+	{Key: "PAK", Value: "PK"},
+	{Key: "PAN", Value: "PA"},
+	{Key: "PCN", Value: "PN"},
+	{Key: "PER", Value: "PE"},
+	{Key: "PHL", Value: "PH"},
+	{Key: "PLW", Value: "PW"},
+	{Key: "PNG", Value: "PG"},
+	{Key: "POL", Value: "PL"},
+	{Key: "PRI", Value: "PR"},
+	{Key: "PRK", Value: "KP"},
+	{Key: "PRT", Value: "PT"},
+	{Key: "PRY", Value: "PY"},
+	{Key: "PSE", Value: "PS"},
+	{Key: "PYF", Value: "PF"},
+	{Key: "QAT", Value: "QA"},
+	{Key: "REU", Value: "RE"},
+	{Key: "ROU", Value: "RO"},
+	{Key: "RUS", Value: "RU"},
+	{Key: "RWA", Value: "RW"},
+	{Key: "SAU", Value: "SA"},
+	{Key: "SDN", Value: "SD"},
+	{Key: "SEN", Value: "SN"},
+	{Key: "SGP", Value: "SG"},
+	{Key: "SGS", Value: "GS"},
+	{Key: "SHN", Value: "SH"},
+	{Key: "SJM", Value: "SJ"},
+	{Key: "SLB", Value: "SB"},
+	{Key: "SLE", Value: "SL"},
+	{Key: "SLV", Value: "SV"},
+	{Key: "SMR", Value: "SM"},
+	{Key: "SOM", Value: "SO"},
+	{Key: "SPM", Value: "PM"},
+	{Key: "SRB", Value: "RS"},
+	{Key: "SSD", Value: "SS"},
+	{Key: "STP", Value: "ST"},
+	{Key: "SUR", Value: "SR"},
+	{Key: "SVK", Value: "SK"},
+	{Key: "SVN", Value: "SI"},
+	{Key: "SWE", Value: "SE"},
+	{Key: "SWZ", Value: "SZ"},
+	{Key: "SXM", Value: "SX"},
+	{Key: "SYC", Value: "SC"},
+	{Key: "SYR", Value: "SY"},
+	{Key: "TCA", Value: "TC"},
+	{Key: "TCD", Value: "TD"},
+	{Key: "TGO", Value: "TG"},
+	{Key: "THA", Value: "TH"},
+	{Key: "TJK", Value: "TJ"},
+	{Key: "TKL", Value: "TK"},
+	{Key: "TKM", Value: "TM"},
+	{Key: "TLS", Value: "TL"},
+	{Key: "TON", Value: "TO"},
+	{Key: "TTO", Value: "TT"},
+	{Key: "TUN", Value: "TN"},
+	{Key: "TUR", Value: "TR"},
+	{Key: "TUV", Value: "TV"},
+	{Key: "TWN", Value: "TW"},
+	{Key: "TZA", Value: "TZ"},
+	{Key: "UGA", Value: "UG"},
+	{Key: "UKR", Value: "UA"},
+	{Key: "UMI", Value: "UM"},
+	{Key: "URY", Value: "UY"},
+	{Key: "USA", Value: "US"},
+	{Key: "UZB", Value: "UZ"},
+	{Key: "VAT", Value: "VA"},
+	{Key: "VCT", Value: "VC"},
+	{Key: "VEN", Value: "VE"},
+	{Key: "VGB", Value: "VG"},
+	{Key: "VIR", Value: "VI"},
+	{Key: "VNM", Value: "VN"},
+	{Key: "VUT", Value: "VU"},
+	{Key: "WLF", Value: "WF"},
+	{Key: "WSM", Value: "WS"},
+	{Key: "YEM", Value: "YE"},
+	{Key: "ZAF", Value: "ZA"},
+	{Key: "ZMB", Value: "ZM"},
+	{Key: "ZWE", Value: "ZW"},
+})
@@ -0,0 +1,260 @@
+// Code generated by faststringmap.WriteGoSource; DO NOT EDIT.
+
+package presets
+
+import (
+	"alon.kr/x/faststringmap"
+)
+
+// CountryNames maps ISO 3166-1 alpha-2 country codes to English country names.
+var CountryNames = faststringmap.NewMap([]faststringmap.MapEntry[string]{
+	{Key: "AD", Value: "Andorra"},
+	{Key: "AE", Value: "United Arab Emirates"},
+	{Key: "AF", Value: "Afghanistan"},
+	{Key: "AG", Value: "Antigua and Barbuda"},
+	{Key: "AI", Value: "Anguilla"},
+	{Key: "AL", Value: "Albania"},
+	{Key: "AM", Value: "Armenia"},
+	{Key: "AO", Value: "Angola"},
+	{Key: "AQ", Value: "Antarctica"},
+	{Key: "AR", Value: "Argentina"},
+	{Key: "AS", Value: "American Samoa"},
+	{Key: "AT", Value: "Austria"},
+	{Key: "AU", Value: "Australia"},
+	{Key: "AW", Value: "Aruba"},
+	{Key: "AX", Value: "Åland Islands"},
+	{Key: "AZ", Value: "Azerbaijan"},
+	{Key: "BA", Value: "Bosnia and Herzegovina"},
+	{Key: "BB", Value: "Barbados"},
+	{Key: "BD", Value: "Bangladesh"},
+	{Key: "BE", Value: "Belgium"},
+	{Key: "BF", Value: "Burkina Faso"},
+	{Key: "BG", Value: "Bulgaria"},
+	{Key: "BH", Value: "Bahrain"},
+	{Key: "BI", Value: "Burundi"},
+	{Key: "BJ", Value: "Benin"},
+	{Key: "BL", Value: "Saint Barthélemy"},
+	{Key: "BM", Value: "Bermuda"},
+	{Key: "BN", Value: "Brunei Darussalam"},
+	{Key: "BO", Value: "Bolivia"},
+	{Key: "BQ", Value: "Bonaire, Sint Eustatius and Saba"},
+	{Key: "BR", Value: "Brazil"},
+	{Key: "BS", Value: "Bahamas"},
+	{Key: "BT", Value: "Bhutan"},
+	{Key: "BV", Value: "Bouvet Island"},
+	{Key: "BW", Value: "Botswana"},
+	{Key: "BY", Value: "Belarus"},
+	{Key: "BZ", Value: "Belize"},
+	{Key: "CA", Value: "Canada"},
+	{Key: "CC", Value: "Cocos (Keeling) Islands"},
+	{Key: "CD", Value: "Congo, The Democratic Republic of the"},
+	{Key: "CF", Value: "Central African Republic"},
+	{Key: "CG", Value: "Congo"},
+	{Key: "CH", Value: "Switzerland"},
+	{Key: "CI", Value: "Côte d'Ivoire"},
+	{Key: "CK", Value: "Cook Islands"},
+	{Key: "CL", Value: "Chile"},
+	{Key: "CM", Value: "Cameroon"},
+	{Key: "CN", Value: "China"},
+	{Key: "CO", Value: "Colombia"},
+	{Key: "CR", Value: "Costa Rica"},
+	{Key: "CU", Value: "Cuba"},
+	{Key: "CV", Value: "Cabo Verde"},
+	{Key: "CW", Value: "Curaçao"},
+	{Key: "CX", Value: "Christmas Island"},
+	{Key: "CY", Value: "Cyprus"},
+	{Key: "CZ", Value: "Czechia"},
+	{Key: "DE", Value: "Germany"},
+	{Key: "DJ", Value: "Djibouti"},
+	{Key: "DK", Value: "Denmark"},
+	{Key: "DM", Value: "Dominica"},
+	{Key: "DO", Value: "Dominican Republic"},
+	{Key: "DZ", Value: "Algeria"},
+	{Key: "EC", Value: "Ecuador"},
+	{Key: "EE", Value: "Estonia"},
+	{Key: "EG", Value: "Egypt"},
+	{Key: "EH", Value: "Western Sahara"},
+	{Key: "ER", Value: "Eritrea"},
+	{Key: "ES", Value: "Spain"},
+	{Key: "ET", Value: "Ethiopia"},
+	{Key: "FI", Value: "Finland"},
+	{Key: "FJ", Value: "Fiji"},
+	{Key: "FK", Value: "Falkland Islands (Malvinas)"},
+	{Key: "FM", Value: "Micronesia, Federated States of"},
+	{Key: "FO", Value: "Faroe Islands"},
+	{Key: "FR", Value: "France"},
+	{Key: "GA", Value: "Gabon"},
+	{Key: "GB", Value: "United Kingdom"},
+	{Key: "GD", Value: "Grenada"},
+	{Key: "GE", Value: "Georgia"},
+	{Key: "GF", Value: "French Guiana"},
+	{Key: "GG", Value: "Guernsey"},
+	{Key: "GH", Value: "Ghana"},
+	{Key: "GI", Value: "Gibraltar"},
+	{Key: "GL", Value: "Greenland"},
+	{Key: "GM", Value: "Gambia"},
+	{Key: "GN", Value: "Guinea"},
+	{Key: "GP", Value: "Guadeloupe"},
+	{Key: "GQ", Value: "Equatorial Guinea"},
+	{Key: "GR", Value: "Greece"},
+	{Key: "GS", Value: "South Georgia and the South Sandwich Islands"},
+	{Key: "GT", Value: "Guatemala"},
+	{Key: "GU", Value: "Guam"},
+	{Key: "GW", Value: "Guinea-Bissau"},
+	{Key: "GY", Value: "Guyana"},
+	{Key: "HK", Value: "Hong Kong"},
+	{Key: "HM", Value: "Heard Island and McDonald Islands"},
+	{Key: "HN", Value: "Honduras"},
+	{Key: "HR", Value: "Croatia"},
+	{Key: "HT", Value: "Haiti"},
+	{Key: "HU", Value: "Hungary"},
+	{Key: "ID", Value: "Indonesia"},
+	{Key: "IE", Value: "Ireland"},
+	{Key: "IL", Value: "Israel"},
+	{Key: "IM", Value: "Isle of Man"},
+	{Key: "IN", Value: "India"},
+	{Key: "IO", Value: "British Indian Ocean Territory"},
+	{Key: "IQ", Value: "Iraq"},
+	{Key: "IR", Value: "Iran"},
+	{Key: "IS", Value: "Iceland"},
+	{Key: "IT", Value: "Italy"},
+	{Key: "JE", Value: "Jersey"},
+	{Key: "JM", Value: "Jamaica"},
+	{Key: "JO", Value: "Jordan"},
+	{Key: "JP", Value: "Japan"},
+	{Key: "KE", Value: "Kenya"},
+	{Key: "KG", Value: "Kyrgyzstan"},
+	{Key: "KH", Value: "Cambodia"},
+	{Key: "KI", Value: "Kiribati"},
+	{Key: "KM", Value: "Comoros"},
+	{Key: "KN", Value: "Saint Kitts and Nevis"},
+	{Key: "KP", Value: "North Korea"},
+	{Key: "KR", Value: "South Korea"},
+	{Key: "KW", Value: "Kuwait"},
+	{Key: "KY", Value: "Cayman Islands"},
+	{Key: "KZ", Value: "Kazakhstan"},
+	{Key: "LA", Value: "Laos"},
+	{Key: "LB", Value: "Lebanon"},
+	{Key: "LC", Value: "Saint Lucia"},
+	{Key: "LI", Value: "Liechtenstein"},
+	{Key: "LK", Value: "Sri Lanka"},
+	{Key: "LR", Value: "Liberia"},
+	{Key: "LS", Value: "Lesotho"},
+	{Key: "LT", Value: "Lithuania"},
+	{Key: "LU", Value: "Luxembourg"},
+	{Key: "LV", Value: "Latvia"},
+	{Key: "LY", Value: "Libya"},
+	{Key: "MA", Value: "Morocco"},
+	{Key: "MC", Value: "Monaco"},
+	{Key: "MD", Value: "Moldova"},
+	{Key: "ME", Value: "Montenegro"},
+	{Key: "MF", Value: "Saint Martin (French part)"},
+	{Key: "MG", Value: "Madagascar"},
+	{Key: "MH", Value: "Marshall Islands"},
+	{Key: "MK", Value: "North Macedonia"},
+	{Key: "ML", Value: "Mali"},
+	{Key: "MM", Value: "Myanmar"},
+	{Key: "MN", Value: "Mongolia"},
+	{Key: "MO", Value: "Macao"},
+	{Key: "MP", Value: "Northern Mariana Islands"},
+	{Key: "MQ", Value: "Martinique"},
+	{Key: "MR", Value: "Mauritania"},
+	{Key: "MS", Value: "Montserrat"},
+	{Key: "MT", Value: "Malta"},
+	{Key: "MU", Value: "Mauritius"},
+	{Key: "MV", Value: "Maldives"},
+	{Key: "MW", Value: "Malawi"},
+	{Key: "MX", Value: "Mexico"},
+	{Key: "MY", Value: "Malaysia"},
+	{Key: "MZ", Value: "Mozambique"},
+	{Key: "NA", Value: "Namibia"},
+	{Key: "NC", Value: "New Caledonia"},
+	{Key: "NE", Value: "Niger"},
+	{Key: "NF", Value: "Norfolk Island"},
+	{Key: "NG", Value: "Nigeria"},
+	{Key: "NI", Value: "Nicaragua"},
+	{Key: "NL", Value: "Netherlands"},
+	{Key: "NO", Value: "Norway"},
+	{Key: "NP", Value: "Nepal"},
+	{Key: "NR", Value: "Nauru"},
+	{Key: "NU", Value: "Niue"},
+	{Key: "NZ", Value: "New Zealand"},
+	{Key: "OM", Value: "Oman"},
+	{Key: "PA", Value: "Panama"},
+	{Key: "PE", Value: "Peru"},
+	{Key: "PF", Value: "French Polynesia"},
+	{Key: "PG", Value: "Papua New Guinea"},
+	{Key: "PH", Value: "Philippines"},
+	{Key: "PK", Value: "Pakistan"},
+	{Key: "PL", Value: "Poland"},
+	{Key: "PM", Value: "Saint Pierre and Miquelon"},
+	{Key: "PN", Value: "Pitcairn"},
+	{Key: "PR", Value: "Puerto Rico"},
+	{Key: "PS", Value: "Palestine, State of"},
+	{Key: "PT", Value: "Portugal"},
+	{Key: "PW", Value: "Palau"},
+	{Key: "PY", Value: "Paraguay"},
+	{Key: "QA", Value: "Qatar"},
+	{Key: "RE", Value: "Réunion"},
+	{Key: "RO", Value: "Romania"},
+	{Key: "RS", Value: "Serbia"},
+	{Key: "RU", Value: "Russian Federation"},
+	{Key: "RW", Value: "Rwanda"},
+	{Key: "SA", Value: "Saudi Arabia"},
+	{Key: "SB", Value: "Solomon Islands"},
+	{Key: "SC", Value: "Seychelles"},
+	{Key: "SD", Value: "Sudan"},
+	{Key: "SE", Value: "Sweden"},
+	{Key: "SG", Value: "Singapore"},
+	{Key: "SH", Value: "Saint Helena, Ascension and Tristan da Cunha"},
+	{Key: "SI", Value: "Slovenia"},
+	{Key: "SJ", Value: "Svalbard and Jan Mayen"},
+	{Key: "SK", Value: "Slovakia"},
+	{Key: "SL", Value: "Sierra Leone"},
+	{Key: "SM", Value: "San Marino"},
+	{Key: "SN", Value: "Senegal"},
+	{Key: "SO", Value: "Somalia"},
+	{Key: "SR", Value: "Suriname"},
+	{Key: "SS", Value: "South Sudan"},
+	{Key: "ST", Value: "Sao Tome and Principe"},
+	{Key: "SV", Value: "El Salvador"},
+	{Key: "SX", Value: "Sint Maarten (Dutch part)"},
+	{Key: "SY", Value: "Syria"},
+	{Key: "SZ", Value: "Eswatini"},
+	{Key: "TC", Value: "Turks and Caicos Islands"},
+	{Key: "TD", Value: "Chad"},
+	{Key: "TF", Value: "French Southern Territories"},
+	{Key: "TG", Value: "Togo"},
+	{Key: "TH", Value: "Thailand"},
+	{Key: "TJ", Value: "Tajikistan"},
+	{Key: "TK", Value: "Tokelau"},
+	{Key: "TL", Value: "Timor-Leste"},
+	{Key: "TM", Value: "Turkmenistan"},
+	{Key: "TN", Value: "Tunisia"},
+	{Key: "TO", Value: "Tonga"},
+	{Key: "TR", Value: "Türkiye"},
+	{Key: "TT", Value: "Trinidad and Tobago"},
+	{Key: "TV", Value: "Tuvalu"},
+	{Key: "TW", Value: "Taiwan"},
+	{Key: "TZ", Value: "Tanzania"},
+	{Key: "UA", Value: "Ukraine"},
+	{Key: "UG", Value: "Uganda"},
+	{Key: "UM", Value: "United States Minor Outlying Islands"},
+	{Key: "US", Value: "United States"},
+	{Key: "UY", Value: "Uruguay"},
+	{Key: "UZ", Value: "Uzbekistan"},
+	{Key: "VA", Value: "Holy See (Vatican City State)"},
+	{Key: "VC", Value: "Saint Vincent and the Grenadines"},
+	{Key: "VE", Value: "Venezuela"},
+	{Key: "VG", Value: "Virgin Islands, British"},
+	{Key: "VI", Value: "Virgin Islands, U.S."},
+	{Key: "VN", Value: "Vietnam"},
+	{Key: "VU", Value: "Vanuatu"},
+	{Key: "WF", Value: "Wallis and Futuna"},
+	{Key: "WS", Value: "Samoa"},
+	{Key: "YE", Value: "Yemen"},
+	{Key: "YT", Value: "Mayotte"},
+	{Key: "ZA", Value: "South Africa"},
+	{Key: "ZM", Value: "Zambia"},
+	{Key: "ZW", Value: "Zimbabwe"},
+})
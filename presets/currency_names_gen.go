@@ -0,0 +1,192 @@
+// Code generated by faststringmap.WriteGoSource; DO NOT EDIT.
+
+package presets
+
+import (
+	"alon.kr/x/faststringmap"
+)
+
+// CurrencyNames maps ISO 4217 currency codes to English currency names.
+var CurrencyNames = faststringmap.NewMap([]faststringmap.MapEntry[string]{
+	{Key: "AED", Value: "UAE Dirham"},
+	{Key: "AFN", Value: "Afghani"},
+	{Key: "ALL", Value: "Lek"},
+	{Key: "AMD", Value: "Armenian Dram"},
+	{Key: "ANG", Value: "Netherlands Antillean Guilder"},
+	{Key: "AOA", Value: "Kwanza"},
+	{Key: "ARS", Value: "Argentine Peso"},
+	{Key: "AUD", Value: "Australian Dollar"},
+	{Key: "AWG", Value: "Aruban Florin"},
+	{Key: "AZN", Value: "Azerbaijan Manat"},
+	{Key: "BAM", Value: "Convertible Mark"},
+	{Key: "BBD", Value: "Barbados Dollar"},
+	{Key: "BDT", Value: "Taka"},
+	{Key: "BGN", Value: "Bulgarian Lev"},
+	{Key: "BHD", Value: "Bahraini Dinar"},
+	{Key: "BIF", Value: "Burundi Franc"},
+	{Key: "BMD", Value: "Bermudian Dollar"},
+	{Key: "BND", Value: "Brunei Dollar"},
+	{Key: "BOB", Value: "Boliviano"},
+	{Key: "BOV", Value: "Mvdol"},
+	{Key: "BRL", Value: "Brazilian Real"},
+	{Key: "BSD", Value: "Bahamian Dollar"},
+	{Key: "BTN", Value: "Ngultrum"},
+	{Key: "BWP", Value: "Pula"},
+	{Key: "BYN", Value: "Belarusian Ruble"},
+	{Key: "BZD", Value: "Belize Dollar"},
+	{Key: "CAD", Value: "Canadian Dollar"},
+	{Key: "CDF", Value: "Congolese Franc"},
+	{Key: "CHE", Value: "WIR Euro"},
+	{Key: "CHF", Value: "Swiss Franc"},
+	{Key: "CHW", Value: "WIR Franc"},
+	{Key: "CLF", Value: "Unidad de Fomento"},
+	{Key: "CLP", Value: "Chilean Peso"},
+	{Key: "CNY", Value: "Yuan Renminbi"},
+	{Key: "COP", Value: "Colombian Peso"},
+	{Key: "COU", Value: "Unidad de Valor Real"},
+	{Key: "CRC", Value: "Costa Rican Colon"},
+	{Key: "CUC", Value: "Peso Convertible"},
+	{Key: "CUP", Value: "Cuban Peso"},
+	{Key: "CVE", Value: "Cabo Verde Escudo"},
+	{Key: "CZK", Value: "Czech Koruna"},
+	{Key: "DJF", Value: "Djibouti Franc"},
+	{Key: "DKK", Value: "Danish Krone"},
+	{Key: "DOP", Value: "Dominican Peso"},
+	{Key: "DZD", Value: "Algerian Dinar"},
+	{Key: "EGP", Value: "Egyptian Pound"},
+	{Key: "ERN", Value: "Nakfa"},
+	{Key: "ETB", Value: "Ethiopian Birr"},
+	{Key: "EUR", Value: "Euro"},
+	{Key: "FJD", Value: "Fiji Dollar"},
+	{Key: "FKP", Value: "Falkland Islands Pound"},
+	{Key: "GBP", Value: "Pound Sterling"},
+	{Key: "GEL", Value: "Lari"},
+	{Key: "GHS", Value: "Ghana Cedi"},
+	{Key: "GIP", Value: "Gibraltar Pound"},
+	{Key: "GMD", Value: "Dalasi"},
+	{Key: "GNF", Value: "Guinean Franc"},
+	{Key: "GTQ", Value: "Quetzal"},
+	{Key: "GYD", Value: "Guyana Dollar"},
+	{Key: "HKD", Value: "Hong Kong Dollar"},
+	{Key: "HNL", Value: "Lempira"},
+	{Key: "HRK", Value: "Kuna"},
+	{Key: "HTG", Value: "Gourde"},
+	{Key: "HUF", Value: "Forint"},
+	{Key: "IDR", Value: "Rupiah"},
+	{Key: "ILS", Value: "New Israeli Sheqel"},
+	{Key: "INR", Value: "Indian Rupee"},
+	{Key: "IQD", Value: "Iraqi Dinar"},
+	{Key: "IRR", Value: "Iranian Rial"},
+	{Key: "ISK", Value: "Iceland Krona"},
+	{Key: "JMD", Value: "Jamaican Dollar"},
+	{Key: "JOD", Value: "Jordanian Dinar"},
+	{Key: "JPY", Value: "Yen"},
+	{Key: "KES", Value: "Kenyan Shilling"},
+	{Key: "KGS", Value: "Som"},
+	{Key: "KHR", Value: "Riel"},
+	{Key: "KMF", Value: "Comorian Franc"},
+	{Key: "KPW", Value: "North Korean Won"},
+	{Key: "KRW", Value: "Won"},
+	{Key: "KWD", Value: "Kuwaiti Dinar"},
+	{Key: "KYD", Value: "Cayman Islands Dollar"},
+	{Key: "KZT", Value: "Tenge"},
+	{Key: "LAK", Value: "Lao Kip"},
+	{Key: "LBP", Value: "Lebanese Pound"},
+	{Key: "LKR", Value: "Sri Lanka Rupee"},
+	{Key: "LRD", Value: "Liberian Dollar"},
+	{Key: "LSL", Value: "Loti"},
+	{Key: "LYD", Value: "Libyan Dinar"},
+	{Key: "MAD", Value: "Moroccan Dirham"},
+	{Key: "MDL", Value: "Moldovan Leu"},
+	{Key: "MGA", Value: "Malagasy Ariary"},
+	{Key: "MKD", Value: "Denar"},
+	{Key: "MMK", Value: "Kyat"},
+	{Key: "MNT", Value: "Tugrik"},
+	{Key: "MOP", Value: "Pataca"},
+	{Key: "MRU", Value: "Ouguiya"},
+	{Key: "MUR", Value: "Mauritius Rupee"},
+	{Key: "MVR", Value: "Rufiyaa"},
+	{Key: "MWK", Value: "Malawi Kwacha"},
+	{Key: "MXN", Value: "Mexican Peso"},
+	{Key: "MXV", Value: "Mexican Unidad de Inversion (UDI)"},
+	{Key: "MYR", Value: "Malaysian Ringgit"},
+	{Key: "MZN", Value: "Mozambique Metical"},
+	{Key: "NAD", Value: "Namibia Dollar"},
+	{Key: "NGN", Value: "Naira"},
+	{Key: "NIO", Value: "Cordoba Oro"},
+	{Key: "NOK", Value: "Norwegian Krone"},
+	{Key: "NPR", Value: "Nepalese Rupee"},
+	{Key: "NZD", Value: "New Zealand Dollar"},
+	{Key: "OMR", Value: "Rial Omani"},
+	{Key: "PAB", Value: "Balboa"},
+	{Key: "PEN", Value: "Sol"},
+	{Key: "PGK", Value: "Kina"},
+	{Key: "PHP", Value: "Philippine Peso"},
+	{Key: "PKR", Value: "Pakistan Rupee"},
+	{Key: "PLN", Value: "Zloty"},
+	{Key: "PYG", Value: "Guarani"},
+	{Key: "QAR", Value: "Qatari Rial"},
+	{Key: "RON", Value: "Romanian Leu"},
+	{Key: "RSD", Value: "Serbian Dinar"},
+	{Key: "RUB", Value: "Russian Ruble"},
+	{Key: "RWF", Value: "Rwanda Franc"},
+	{Key: "SAR", Value: "Saudi Riyal"},
+	{Key: "SBD", Value: "Solomon Islands Dollar"},
+	{Key: "SCR", Value: "Seychelles Rupee"},
+	{Key: "SDG", Value: "Sudanese Pound"},
+	{Key: "SEK", Value: "Swedish Krona"},
+	{Key: "SGD", Value: "Singapore Dollar"},
+	{Key: "SHP", Value: "Saint Helena Pound"},
+	{Key: "SLE", Value: "Leone"},
+	{Key: "SLL", Value: "Leone"},
+	{Key: "SOS", Value: "Somali Shilling"},
+	{Key: "SRD", Value: "Surinam Dollar"},
+	{Key: "SSP", Value: "South Sudanese Pound"},
+	{Key: "STN", Value: "Dobra"},
+	{Key: "SVC", Value: "El Salvador Colon"},
+	{Key: "SYP", Value: "Syrian Pound"},
+	{Key: "SZL", Value: "Lilangeni"},
+	{Key: "THB", Value: "Baht"},
+	{Key: "TJS", Value: "Somoni"},
+	{Key: "TMT", Value: "Turkmenistan New Manat"},
+	{Key: "TND", Value: "Tunisian Dinar"},
+	{Key: "TOP", Value: "Pa’anga"},
+	{Key: "TRY", Value: "Turkish Lira"},
+	{Key: "TTD", Value: "Trinidad and Tobago Dollar"},
+	{Key: "TWD", Value: "New Taiwan Dollar"},
+	{Key: "TZS", Value: "Tanzanian Shilling"},
+	{Key: "UAH", Value: "Hryvnia"},
+	{Key: "UGX", Value: "Uganda Shilling"},
+	{Key: "USD", Value: "US Dollar"},
+	{Key: "USN", Value: "US Dollar (Next day)"},
+	{Key: "UYI", Value: "Uruguay Peso en Unidades Indexadas (UI)"},
+	{Key: "UYU", Value: "Peso Uruguayo"},
+	{Key: "UYW", Value: "Unidad Previsional"},
+	{Key: "UZS", Value: "Uzbekistan Sum"},
+	{Key: "VED", Value: "Bolívar Soberano"},
+	{Key: "VES", Value: "Bolívar Soberano"},
+	{Key: "VND", Value: "Dong"},
+	{Key: "VUV", Value: "Vatu"},
+	{Key: "WST", Value: "Tala"},
+	{Key: "XAF", Value: "CFA Franc BEAC"},
+	{Key: "XAG", Value: "Silver"},
+	{Key: "XAU", Value: "Gold"},
+	{Key: "XBA", Value: "Bond Markets Unit European Composite Unit (EURCO)"},
+	{Key: "XBB", Value: "Bond Markets Unit European Monetary Unit (E.M.U.-6)"},
+	{Key: "XBC", Value: "Bond Markets Unit European Unit of Account 9 (E.U.A.-9)"},
+	{Key: "XBD", Value: "Bond Markets Unit European Unit of Account 17 (E.U.A.-17)"},
+	{Key: "XCD", Value: "East Caribbean Dollar"},
+	{Key: "XDR", Value: "SDR (Special Drawing Right)"},
+	{Key: "XOF", Value: "CFA Franc BCEAO"},
+	{Key: "XPD", Value: "Palladium"},
+	{Key: "XPF", Value: "CFP Franc"},
+	{Key: "XPT", Value: "Platinum"},
+	{Key: "XSU", Value: "Sucre"},
+	{Key: "XTS", Value: "Codes specifically reserved for testing purposes"},
+	{Key: "XUA", Value: "ADB Unit of Account"},
+	{Key: "XXX", Value: "The codes assigned for transactions where no currency is involved"},
+	{Key: "YER", Value: "Yemeni Rial"},
+	{Key: "ZAR", Value: "Rand"},
+	{Key: "ZMW", Value: "Zambian Kwacha"},
+	{Key: "ZWL", Value: "Zimbabwe Dollar"},
+})
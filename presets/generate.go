@@ -0,0 +1,25 @@
+//go:build ignore
+
+// This program writes the generated source files of the presets package
+// to the current directory.
+package main
+
+import (
+	"log"
+	"os"
+
+	"alon.kr/x/faststringmap/presets/internal/gen"
+)
+
+func main() {
+	files, err := gen.Files()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for name, src := range files {
+		if err := os.WriteFile(name, src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
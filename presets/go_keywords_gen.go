@@ -0,0 +1,37 @@
+// Code generated by faststringmap.WriteGoSource; DO NOT EDIT.
+
+package presets
+
+import (
+	"alon.kr/x/faststringmap"
+	"go/token"
+)
+
+// GoKeywords maps the keywords of the Go programming language to their tokens.
+var GoKeywords = faststringmap.NewMap([]faststringmap.MapEntry[token.Token]{
+	{Key: "break", Value: token.BREAK},
+	{Key: "case", Value: token.CASE},
+	{Key: "chan", Value: token.CHAN},
+	{Key: "const", Value: token.CONST},
+	{Key: "continue", Value: token.CONTINUE},
+	{Key: "default", Value: token.DEFAULT},
+	{Key: "defer", Value: token.DEFER},
+	{Key: "else", Value: token.ELSE},
+	{Key: "fallthrough", Value: token.FALLTHROUGH},
+	{Key: "for", Value: token.FOR},
+	{Key: "func", Value: token.FUNC},
+	{Key: "go", Value: token.GO},
+	{Key: "goto", Value: token.GOTO},
+	{Key: "if", Value: token.IF},
+	{Key: "import", Value: token.IMPORT},
+	{Key: "interface", Value: token.INTERFACE},
+	{Key: "map", Value: token.MAP},
+	{Key: "package", Value: token.PACKAGE},
+	{Key: "range", Value: token.RANGE},
+	{Key: "return", Value: token.RETURN},
+	{Key: "select", Value: token.SELECT},
+	{Key: "struct", Value: token.STRUCT},
+	{Key: "switch", Value: token.SWITCH},
+	{Key: "type", Value: token.TYPE},
+	{Key: "var", Value: token.VAR},
+})
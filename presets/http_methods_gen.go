@@ -0,0 +1,21 @@
+// Code generated by faststringmap.WriteGoSource; DO NOT EDIT.
+
+package presets
+
+import (
+	"alon.kr/x/faststringmap"
+)
+
+// HTTPMethods maps the HTTP request methods defined in RFC 9110 and RFC 5789
+// to whether they are idempotent.
+var HTTPMethods = faststringmap.NewMap([]faststringmap.MapEntry[bool]{
+	{Key: "CONNECT", Value: false},
+	{Key: "DELETE", Value: true},
+	{Key: "GET", Value: true},
+	{Key: "HEAD", Value: true},
+	{Key: "OPTIONS", Value: true},
+	{Key: "PATCH", Value: false},
+	{Key: "POST", Value: false},
+	{Key: "PUT", Value: true},
+	{Key: "TRACE", Value: true},
+})
@@ -0,0 +1,73 @@
+// Code generated by faststringmap.WriteGoSource; DO NOT EDIT.
+
+package presets
+
+import (
+	"alon.kr/x/faststringmap"
+)
+
+// HTTPStatusCodes maps HTTP status reason phrases to their status codes.
+var HTTPStatusCodes = faststringmap.NewMap([]faststringmap.MapEntry[int]{
+	{Key: "Accepted", Value: 202},
+	{Key: "Already Reported", Value: 208},
+	{Key: "Bad Gateway", Value: 502},
+	{Key: "Bad Request", Value: 400},
+	{Key: "Conflict", Value: 409},
+	{Key: "Continue", Value: 100},
+	{Key: "Created", Value: 201},
+	{Key: "Early Hints", Value: 103},
+	{Key: "Expectation Failed", Value: 417},
+	{Key: "Failed Dependency", Value: 424},
+	{Key: "Forbidden", Value: 403},
+	{Key: "Found", Value: 302},
+	{Key: "Gateway Timeout", Value: 504},
+	{Key: "Gone", Value: 410},
+	{Key: "HTTP Version Not Supported", Value: 505},
+	{Key: "I'm a teapot", Value: 418},
+	{Key: "IM Used", Value: 226},
+	{Key: "Insufficient Storage", Value: 507},
+	{Key: "Internal Server Error", Value: 500},
+	{Key: "Length Required", Value: 411},
+	{Key: "Locked", Value: 423},
+	{Key: "Loop Detected", Value: 508},
+	{Key: "Method Not Allowed", Value: 405},
+	{Key: "Misdirected Request", Value: 421},
+	{Key: "Moved Permanently", Value: 301},
+	{Key: "Multi-Status", Value: 207},
+	{Key: "Multiple Choices", Value: 300},
+	{Key: "Network Authentication Required", Value: 511},
+	{Key: "No Content", Value: 204},
+	{Key: "Non-Authoritative Information", Value: 203},
+	{Key: "Not Acceptable", Value: 406},
+	{Key: "Not Extended", Value: 510},
+	{Key: "Not Found", Value: 404},
+	{Key: "Not Implemented", Value: 501},
+	{Key: "Not Modified", Value: 304},
+	{Key: "OK", Value: 200},
+	{Key: "Partial Content", Value: 206},
+	{Key: "Payment Required", Value: 402},
+	{Key: "Permanent Redirect", Value: 308},
+	{Key: "Precondition Failed", Value: 412},
+	{Key: "Precondition Required", Value: 428},
+	{Key: "Processing", Value: 102},
+	{Key: "Proxy Authentication Required", Value: 407},
+	{Key: "Request Entity Too Large", Value: 413},
+	{Key: "Request Header Fields Too Large", Value: 431},
+	{Key: "Request Timeout", Value: 408},
+	{Key: "Request URI Too Long", Value: 414},
+	{Key: "Requested Range Not Satisfiable", Value: 416},
+	{Key: "Reset Content", Value: 205},
+	{Key: "See Other", Value: 303},
+	{Key: "Service Unavailable", Value: 503},
+	{Key: "Switching Protocols", Value: 101},
+	{Key: "Temporary Redirect", Value: 307},
+	{Key: "Too Early", Value: 425},
+	{Key: "Too Many Requests", Value: 429},
+	{Key: "Unauthorized", Value: 401},
+	{Key: "Unavailable For Legal Reasons", Value: 451},
+	{Key: "Unprocessable Entity", Value: 422},
+	{Key: "Unsupported Media Type", Value: 415},
+	{Key: "Upgrade Required", Value: 426},
+	{Key: "Use Proxy", Value: 305},
+	{Key: "Variant Also Negotiates", Value: 506},
+})
@@ -0,0 +1,195 @@
+// Package gen generates the source files of the presets package.
+package gen
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"go/token"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"alon.kr/x/faststringmap"
+)
+
+//go:embed data/*.tsv
+var data embed.FS
+
+// Files returns the contents of the generated files, by file name.
+func Files() (map[string][]byte, error) {
+	files := map[string][]byte{}
+	add := func(name string, write func(*bytes.Buffer) error) error {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		files[name] = buf.Bytes()
+		return nil
+	}
+
+	countries, err := readTSV("countries.tsv", 3)
+	if err != nil {
+		return nil, err
+	}
+	currencies, err := readTSV("currencies.tsv", 2)
+	if err != nil {
+		return nil, err
+	}
+	languages, err := readTSV("languages.tsv", 2)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := readTSV("http_status.tsv", 2)
+	if err != nil {
+		return nil, err
+	}
+	statusCodes, err := httpStatusCodes(statuses)
+	if err != nil {
+		return nil, err
+	}
+	mimeTypes, err := readTSV("mime_types.tsv", 2)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, err := range []error{
+		add("http_methods_gen.go", func(buf *bytes.Buffer) error {
+			return faststringmap.WriteGoSource(buf, faststringmap.GoSource[bool]{
+				Package: "presets",
+				Var:     "HTTPMethods",
+				Doc: "HTTPMethods maps the HTTP request methods defined in RFC 9110 and RFC 5789\n" +
+					"to whether they are idempotent.",
+			}, httpMethods())
+		}),
+		add("http_status_codes_gen.go", func(buf *bytes.Buffer) error {
+			return faststringmap.WriteGoSource(buf, faststringmap.GoSource[int]{
+				Package: "presets",
+				Var:     "HTTPStatusCodes",
+				Doc:     "HTTPStatusCodes maps HTTP status reason phrases to their status codes.",
+			}, statusCodes)
+		}),
+		add("country_names_gen.go", func(buf *bytes.Buffer) error {
+			return faststringmap.WriteGoSource(buf, faststringmap.GoSource[string]{
+				Package: "presets",
+				Var:     "CountryNames",
+				Doc:     "CountryNames maps ISO 3166-1 alpha-2 country codes to English country names.",
+			}, columns(countries, 0, 2))
+		}),
+		add("country_alpha3_gen.go", func(buf *bytes.Buffer) error {
+			return faststringmap.WriteGoSource(buf, faststringmap.GoSource[string]{
+				Package: "presets",
+				Var:     "CountryAlpha3",
+				Doc:     "CountryAlpha3 maps ISO 3166-1 alpha-3 country codes to alpha-2 country codes.",
+			}, columns(countries, 1, 0))
+		}),
+		add("currency_names_gen.go", func(buf *bytes.Buffer) error {
+			return faststringmap.WriteGoSource(buf, faststringmap.GoSource[string]{
+				Package: "presets",
+				Var:     "CurrencyNames",
+				Doc:     "CurrencyNames maps ISO 4217 currency codes to English currency names.",
+			}, columns(currencies, 0, 1))
+		}),
+		add("language_names_gen.go", func(buf *bytes.Buffer) error {
+			return faststringmap.WriteGoSource(buf, faststringmap.GoSource[string]{
+				Package: "presets",
+				Var:     "LanguageNames",
+				Doc:     "LanguageNames maps ISO 639-1 language codes to English language names.",
+			}, columns(languages, 0, 1))
+		}),
+		add("mime_types_gen.go", func(buf *bytes.Buffer) error {
+			return faststringmap.WriteGoSource(buf, faststringmap.GoSource[string]{
+				Package: "presets",
+				Var:     "MIMETypes",
+				Doc: "MIMETypes maps lower case file extensions, including multi-part extensions\n" +
+					"such as \".tar.gz\", to MIME types. Use LongestSuffixMatch to find the\n" +
+					"MIME type of a file name.",
+				Constructor: "NewSuffixMap",
+			}, columns(mimeTypes, 0, 1))
+		}),
+		add("go_keywords_gen.go", func(buf *bytes.Buffer) error {
+			return faststringmap.WriteGoSource(buf, faststringmap.GoSource[token.Token]{
+				Package:     "presets",
+				Imports:     []string{"go/token"},
+				Var:         "GoKeywords",
+				Doc:         "GoKeywords maps the keywords of the Go programming language to their tokens.",
+				ValueType:   "token.Token",
+				FormatValue: func(tok token.Token) string { return "token." + strings.ToUpper(tok.String()) },
+			}, goKeywords())
+		}),
+	} {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+func httpMethods() []faststringmap.MapEntry[bool] {
+	return []faststringmap.MapEntry[bool]{
+		{Key: http.MethodGet, Value: true},
+		{Key: http.MethodHead, Value: true},
+		{Key: http.MethodPost, Value: false},
+		{Key: http.MethodPut, Value: true},
+		{Key: http.MethodPatch, Value: false},
+		{Key: http.MethodDelete, Value: true},
+		{Key: http.MethodConnect, Value: false},
+		{Key: http.MethodOptions, Value: true},
+		{Key: http.MethodTrace, Value: true},
+	}
+}
+
+func httpStatusCodes(rows [][]string) ([]faststringmap.MapEntry[int], error) {
+	entries := make([]faststringmap.MapEntry[int], len(rows))
+	for i, row := range rows {
+		code, err := strconv.Atoi(row[0])
+		if err != nil {
+			return nil, fmt.Errorf("http_status.tsv: %w", err)
+		}
+		entries[i] = faststringmap.MapEntry[int]{Key: row[1], Value: code}
+	}
+	return entries, nil
+}
+
+func goKeywords() []faststringmap.MapEntry[token.Token] {
+	var entries []faststringmap.MapEntry[token.Token]
+	for tok := token.BREAK; tok <= token.VAR; tok++ {
+		if tok.IsKeyword() {
+			entries = append(entries, faststringmap.MapEntry[token.Token]{Key: tok.String(), Value: tok})
+		}
+	}
+	return entries
+}
+
+// readTSV reads the named data file, skipping comment lines starting with #.
+func readTSV(name string, nColumns int) ([][]string, error) {
+	f, err := data.Open("data/" + name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rows [][]string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if strings.HasPrefix(sc.Text(), "#") {
+			continue
+		}
+		row := strings.Split(sc.Text(), "\t")
+		if len(row) != nColumns {
+			return nil, fmt.Errorf("%s: %q has %d columns want %d", name, sc.Text(), len(row), nColumns)
+		}
+		rows = append(rows, row)
+	}
+	return rows, sc.Err()
+}
+
+func columns(rows [][]string, key, value int) []faststringmap.MapEntry[string] {
+	entries := make([]faststringmap.MapEntry[string], len(rows))
+	for i, row := range rows {
+		entries[i] = faststringmap.MapEntry[string]{Key: row[key], Value: row[value]}
+	}
+	return entries
+}
@@ -0,0 +1,195 @@
+// Code generated by faststringmap.WriteGoSource; DO NOT EDIT.
+
+package presets
+
+import (
+	"alon.kr/x/faststringmap"
+)
+
+// LanguageNames maps ISO 639-1 language codes to English language names.
+var LanguageNames = faststringmap.NewMap([]faststringmap.MapEntry[string]{
+	{Key: "aa", Value: "Afar"},
+	{Key: "ab", Value: "Abkhazian"},
+	{Key: "ae", Value: "Avestan"},
+	{Key: "af", Value: "Afrikaans"},
+	{Key: "ak", Value: "Akan"},
+	{Key: "am", Value: "Amharic"},
+	{Key: "an", Value: "Aragonese"},
+	{Key: "ar", Value: "Arabic"},
+	{Key: "as", Value: "Assamese"},
+	{Key: "av", Value: "Avaric"},
+	{Key: "ay", Value: "Aymara"},
+	{Key: "az", Value: "Azerbaijani"},
+	{Key: "ba", Value: "Bashkir"},
+	{Key: "be", Value: "Belarusian"},
+	{Key: "bg", Value: "Bulgarian"},
+	{Key: "bh", Value: "Bihari languages"},
+	{Key: "bi", Value: "Bislama"},
+	{Key: "bm", Value: "Bambara"},
+	{Key: "bn", Value: "Bengali"},
+	{Key: "bo", Value: "Tibetan"},
+	{Key: "br", Value: "Breton"},
+	{Key: "bs", Value: "Bosnian"},
+	{Key: "ca", Value: "Catalan; Valencian"},
+	{Key: "ce", Value: "Chechen"},
+	{Key: "ch", Value: "Chamorro"},
+	{Key: "co", Value: "Corsican"},
+	{Key: "cr", Value: "Cree"},
+	{Key: "cs", Value: "Czech"},
+	{Key: "cu", Value: "Church Slavic; Old Slavonic; Church Slavonic; Old Bulgarian; Old Church Slavonic"},
+	{Key: "cv", Value: "Chuvash"},
+	{Key: "cy", Value: "Welsh"},
+	{Key: "da", Value: "Danish"},
+	{Key: "de", Value: "German"},
+	{Key: "dv", Value: "Divehi; Dhivehi; Maldivian"},
+	{Key: "dz", Value: "Dzongkha"},
+	{Key: "ee", Value: "Ewe"},
+	{Key: "el", Value: "Greek, Modern (1453-)"},
+	{Key: "en", Value: "English"},
+	{Key: "eo", Value: "Esperanto"},
+	{Key: "es", Value: "Spanish; Castilian"},
+	{Key: "et", Value: "Estonian"},
+	{Key: "eu", Value: "Basque"},
+	{Key: "fa", Value: "Persian"},
+	{Key: "ff", Value: "Fulah"},
+	{Key: "fi", Value: "Finnish"},
+	{Key: "fj", Value: "Fijian"},
+	{Key: "fo", Value: "Faroese"},
+	{Key: "fr", Value: "French"},
+	{Key: "fy", Value: "Western Frisian"},
+	{Key: "ga", Value: "Irish"},
+	{Key: "gd", Value: "Gaelic; Scottish Gaelic"},
+	{Key: "gl", Value: "Galician"},
+	{Key: "gn", Value: "Guarani"},
+	{Key: "gu", Value: "Gujarati"},
+	{Key: "gv", Value: "Manx"},
+	{Key: "ha", Value: "Hausa"},
+	{Key: "he", Value: "Hebrew"},
+	{Key: "hi", Value: "Hindi"},
+	{Key: "ho", Value: "Hiri Motu"},
+	{Key: "hr", Value: "Croatian"},
+	{Key: "ht", Value: "Haitian; Haitian Creole"},
+	{Key: "hu", Value: "Hungarian"},
+	{Key: "hy", Value: "Armenian"},
+	{Key: "hz", Value: "Herero"},
+	{Key: "ia", Value: "Interlingua (International Auxiliary Language Association)"},
+	{Key: "id", Value: "Indonesian"},
+	{Key: "ie", Value: "Interlingue; Occidental"},
+	{Key: "ig", Value: "Igbo"},
+	{Key: "ii", Value: "Sichuan Yi; Nuosu"},
+	{Key: "ik", Value: "Inupiaq"},
+	{Key: "io", Value: "Ido"},
+	{Key: "is", Value: "Icelandic"},
+	{Key: "it", Value: "Italian"},
+	{Key: "iu", Value: "Inuktitut"},
+	{Key: "ja", Value: "Japanese"},
+	{Key: "jv", Value: "Javanese"},
+	{Key: "ka", Value: "Georgian"},
+	{Key: "kg", Value: "Kongo"},
+	{Key: "ki", Value: "Kikuyu; Gikuyu"},
+	{Key: "kj", Value: "Kuanyama; Kwanyama"},
+	{Key: "kk", Value: "Kazakh"},
+	{Key: "kl", Value: "Kalaallisut; Greenlandic"},
+	{Key: "km", Value: "Central Khmer"},
+	{Key: "kn", Value: "Kannada"},
+	{Key: "ko", Value: "Korean"},
+	{Key: "kr", Value: "Kanuri"},
+	{Key: "ks", Value: "Kashmiri"},
+	{Key: "ku", Value: "Kurdish"},
+	{Key: "kv", Value: "Komi"},
+	{Key: "kw", Value: "Cornish"},
+	{Key: "ky", Value: "Kirghiz; Kyrgyz"},
+	{Key: "la", Value: "Latin"},
+	{Key: "lb", Value: "Luxembourgish; Letzeburgesch"},
+	{Key: "lg", Value: "Ganda"},
+	{Key: "li", Value: "Limburgan; Limburger; Limburgish"},
+	{Key: "ln", Value: "Lingala"},
+	{Key: "lo", Value: "Lao"},
+	{Key: "lt", Value: "Lithuanian"},
+	{Key: "lu", Value: "Luba-Katanga"},
+	{Key: "lv", Value: "Latvian"},
+	{Key: "mg", Value: "Malagasy"},
+	{Key: "mh", Value: "Marshallese"},
+	{Key: "mi", Value: "Maori"},
+	{Key: "mk", Value: "Macedonian"},
+	{Key: "ml", Value: "Malayalam"},
+	{Key: "mn", Value: "Mongolian"},
+	{Key: "mr", Value: "Marathi"},
+	{Key: "ms", Value: "Malay"},
+	{Key: "mt", Value: "Maltese"},
+	{Key: "my", Value: "Burmese"},
+	{Key: "na", Value: "Nauru"},
+	{Key: "nb", Value: "Bokmål, Norwegian; Norwegian Bokmål"},
+	{Key: "nd", Value: "Ndebele, North; North Ndebele"},
+	{Key: "ne", Value: "Nepali"},
+	{Key: "ng", Value: "Ndonga"},
+	{Key: "nl", Value: "Dutch; Flemish"},
+	{Key: "nn", Value: "Norwegian Nynorsk; Nynorsk, Norwegian"},
+	{Key: "no", Value: "Norwegian"},
+	{Key: "nr", Value: "Ndebele, South; South Ndebele"},
+	{Key: "nv", Value: "Navajo; Navaho"},
+	{Key: "ny", Value: "Chichewa; Chewa; Nyanja"},
+	{Key: "oc", Value: "Occitan (post 1500); Provençal"},
+	{Key: "oj", Value: "Ojibwa"},
+	{Key: "om", Value: "Oromo"},
+	{Key: "or", Value: "Oriya"},
+	{Key: "os", Value: "Ossetian; Ossetic"},
+	{Key: "pa", Value: "Panjabi; Punjabi"},
+	{Key: "pi", Value: "Pali"},
+	{Key: "pl", Value: "Polish"},
+	{Key: "ps", Value: "Pushto; Pashto"},
+	{Key: "pt", Value: "Portuguese"},
+	{Key: "qu", Value: "Quechua"},
+	{Key: "rm", Value: "Romansh"},
+	{Key: "rn", Value: "Rundi"},
+	{Key: "ro", Value: "Romanian; Moldavian; Moldovan"},
+	{Key: "ru", Value: "Russian"},
+	{Key: "rw", Value: "Kinyarwanda"},
+	{Key: "sa", Value: "Sanskrit"},
+	{Key: "sc", Value: "Sardinian"},
+	{Key: "sd", Value: "Sindhi"},
+	{Key: "se", Value: "Northern Sami"},
+	{Key: "sg", Value: "Sango"},
+	{Key: "si", Value: "Sinhala; Sinhalese"},
+	{Key: "sk", Value: "Slovak"},
+	{Key: "sl", Value: "Slovenian"},
+	{Key: "sm", Value: "Samoan"},
+	{Key: "sn", Value: "Shona"},
+	{Key: "so", Value: "Somali"},
+	{Key: "sq", Value: "Albanian"},
+	{Key: "sr", Value: "Serbian"},
+	{Key: "ss", Value: "Swati"},
+	{Key: "st", Value: "Sotho, Southern"},
+	{Key: "su", Value: "Sundanese"},
+	{Key: "sv", Value: "Swedish"},
+	{Key: "sw", Value: "Swahili"},
+	{Key: "ta", Value: "Tamil"},
+	{Key: "te", Value: "Telugu"},
+	{Key: "tg", Value: "Tajik"},
+	{Key: "th", Value: "Thai"},
+	{Key: "ti", Value: "Tigrinya"},
+	{Key: "tk", Value: "Turkmen"},
+	{Key: "tl", Value: "Tagalog"},
+	{Key: "tn", Value: "Tswana"},
+	{Key: "to", Value: "Tonga (Tonga Islands)"},
+	{Key: "tr", Value: "Turkish"},
+	{Key: "ts", Value: "Tsonga"},
+	{Key: "tt", Value: "Tatar"},
+	{Key: "tw", Value: "Twi"},
+	{Key: "ty", Value: "Tahitian"},
+	{Key: "ug", Value: "Uighur; Uyghur"},
+	{Key: "uk", Value: "Ukrainian"},
+	{Key: "ur", Value: "Urdu"},
+	{Key: "uz", Value: "Uzbek"},
+	{Key: "ve", Value: "Venda"},
+	{Key: "vi", Value: "Vietnamese"},
+	{Key: "vo", Value: "Volapük"},
+	{Key: "wa", Value: "Walloon"},
+	{Key: "wo", Value: "Wolof"},
+	{Key: "xh", Value: "Xhosa"},
+	{Key: "yi", Value: "Yiddish"},
+	{Key: "yo", Value: "Yoruba"},
+	{Key: "za", Value: "Zhuang; Chuang"},
+	{Key: "zh", Value: "Chinese"},
+	{Key: "zu", Value: "Zulu"},
+})
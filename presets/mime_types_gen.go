@@ -0,0 +1,106 @@
+// Code generated by faststringmap.WriteGoSource; DO NOT EDIT.
+
+package presets
+
+import (
+	"alon.kr/x/faststringmap"
+)
+
+// MIMETypes maps lower case file extensions, including multi-part extensions
+// such as ".tar.gz", to MIME types. Use LongestSuffixMatch to find the
+// MIME type of a file name.
+var MIMETypes = faststringmap.NewSuffixMap([]faststringmap.MapEntry[string]{
+	{Key: ".7z", Value: "application/x-7z-compressed"},
+	{Key: ".aac", Value: "audio/aac"},
+	{Key: ".apng", Value: "image/apng"},
+	{Key: ".avi", Value: "video/x-msvideo"},
+	{Key: ".avif", Value: "image/avif"},
+	{Key: ".bin", Value: "application/octet-stream"},
+	{Key: ".bmp", Value: "image/bmp"},
+	{Key: ".bz2", Value: "application/x-bzip2"},
+	{Key: ".c", Value: "text/x-c"},
+	{Key: ".cpp", Value: "text/x-c++"},
+	{Key: ".css", Value: "text/css"},
+	{Key: ".csv", Value: "text/csv"},
+	{Key: ".deb", Value: "application/vnd.debian.binary-package"},
+	{Key: ".doc", Value: "application/msword"},
+	{Key: ".docx", Value: "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+	{Key: ".eot", Value: "application/vnd.ms-fontobject"},
+	{Key: ".epub", Value: "application/epub+zip"},
+	{Key: ".flac", Value: "audio/flac"},
+	{Key: ".gif", Value: "image/gif"},
+	{Key: ".go", Value: "text/x-go"},
+	{Key: ".gz", Value: "application/gzip"},
+	{Key: ".h", Value: "text/x-c"},
+	{Key: ".heic", Value: "image/heic"},
+	{Key: ".htm", Value: "text/html"},
+	{Key: ".html", Value: "text/html"},
+	{Key: ".ico", Value: "image/vnd.microsoft.icon"},
+	{Key: ".ics", Value: "text/calendar"},
+	{Key: ".jar", Value: "application/java-archive"},
+	{Key: ".java", Value: "text/x-java"},
+	{Key: ".jpeg", Value: "image/jpeg"},
+	{Key: ".jpg", Value: "image/jpeg"},
+	{Key: ".js", Value: "text/javascript"},
+	{Key: ".json", Value: "application/json"},
+	{Key: ".jsonld", Value: "application/ld+json"},
+	{Key: ".md", Value: "text/markdown"},
+	{Key: ".mid", Value: "audio/midi"},
+	{Key: ".midi", Value: "audio/midi"},
+	{Key: ".mjs", Value: "text/javascript"},
+	{Key: ".mkv", Value: "video/x-matroska"},
+	{Key: ".mov", Value: "video/quicktime"},
+	{Key: ".mp3", Value: "audio/mpeg"},
+	{Key: ".mp4", Value: "video/mp4"},
+	{Key: ".mpeg", Value: "video/mpeg"},
+	{Key: ".odp", Value: "application/vnd.oasis.opendocument.presentation"},
+	{Key: ".ods", Value: "application/vnd.oasis.opendocument.spreadsheet"},
+	{Key: ".odt", Value: "application/vnd.oasis.opendocument.text"},
+	{Key: ".oga", Value: "audio/ogg"},
+	{Key: ".ogv", Value: "video/ogg"},
+	{Key: ".ogx", Value: "application/ogg"},
+	{Key: ".opus", Value: "audio/opus"},
+	{Key: ".otf", Value: "font/otf"},
+	{Key: ".pdf", Value: "application/pdf"},
+	{Key: ".png", Value: "image/png"},
+	{Key: ".ppt", Value: "application/vnd.ms-powerpoint"},
+	{Key: ".pptx", Value: "application/vnd.openxmlformats-officedocument.presentationml.presentation"},
+	{Key: ".py", Value: "text/x-python"},
+	{Key: ".rar", Value: "application/vnd.rar"},
+	{Key: ".rpm", Value: "application/x-rpm"},
+	{Key: ".rs", Value: "text/rust"},
+	{Key: ".rtf", Value: "application/rtf"},
+	{Key: ".sh", Value: "application/x-sh"},
+	{Key: ".svg", Value: "image/svg+xml"},
+	{Key: ".tar", Value: "application/x-tar"},
+	{Key: ".tar.bz2", Value: "application/x-bzip-compressed-tar"},
+	{Key: ".tar.gz", Value: "application/x-compressed-tar"},
+	{Key: ".tar.xz", Value: "application/x-xz-compressed-tar"},
+	{Key: ".tar.zst", Value: "application/x-zstd-compressed-tar"},
+	{Key: ".tbz2", Value: "application/x-bzip-compressed-tar"},
+	{Key: ".tgz", Value: "application/x-compressed-tar"},
+	{Key: ".tif", Value: "image/tiff"},
+	{Key: ".tiff", Value: "image/tiff"},
+	{Key: ".toml", Value: "application/toml"},
+	{Key: ".ts", Value: "video/mp2t"},
+	{Key: ".ttf", Value: "font/ttf"},
+	{Key: ".txt", Value: "text/plain"},
+	{Key: ".txz", Value: "application/x-xz-compressed-tar"},
+	{Key: ".wasm", Value: "application/wasm"},
+	{Key: ".wav", Value: "audio/wav"},
+	{Key: ".weba", Value: "audio/webm"},
+	{Key: ".webm", Value: "video/webm"},
+	{Key: ".webmanifest", Value: "application/manifest+json"},
+	{Key: ".webp", Value: "image/webp"},
+	{Key: ".woff", Value: "font/woff"},
+	{Key: ".woff2", Value: "font/woff2"},
+	{Key: ".xhtml", Value: "application/xhtml+xml"},
+	{Key: ".xls", Value: "application/vnd.ms-excel"},
+	{Key: ".xlsx", Value: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	{Key: ".xml", Value: "application/xml"},
+	{Key: ".xz", Value: "application/x-xz"},
+	{Key: ".yaml", Value: "application/yaml"},
+	{Key: ".yml", Value: "application/yaml"},
+	{Key: ".zip", Value: "application/zip"},
+	{Key: ".zst", Value: "application/zstd"},
+})
@@ -0,0 +1,7 @@
+// Package presets provides ready-made maps for common lookup tables.
+//
+// The maps are generated using faststringmap.WriteGoSource, from the data
+// in internal/gen. Run go generate to regenerate them.
+package presets
+
+//go:generate go run generate.go
@@ -0,0 +1,79 @@
+package presets_test
+
+import (
+	"bytes"
+	"go/token"
+	"net/http"
+	"os"
+	"testing"
+
+	"alon.kr/x/faststringmap/presets"
+	"alon.kr/x/faststringmap/presets/internal/gen"
+)
+
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	files, err := gen.Files()
+	if err != nil {
+		t.Fatalf("gen.Files() error = %v", err)
+	}
+
+	for name, want := range files {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date, run go generate", name)
+		}
+	}
+}
+
+func TestPresets(t *testing.T) {
+	if idempotent, ok := presets.HTTPMethods.LookupString(http.MethodPut); !ok || !idempotent {
+		t.Errorf("HTTPMethods[PUT] = %v, %v want true, true", idempotent, ok)
+	}
+	if idempotent, ok := presets.HTTPMethods.LookupString(http.MethodPost); !ok || idempotent {
+		t.Errorf("HTTPMethods[POST] = %v, %v want false, true", idempotent, ok)
+	}
+	if code, ok := presets.HTTPStatusCodes.LookupString("Not Found"); !ok || code != http.StatusNotFound {
+		t.Errorf("HTTPStatusCodes[Not Found] = %v, %v want 404, true", code, ok)
+	}
+	if name, ok := presets.CountryNames.LookupString("FR"); !ok || name != "France" {
+		t.Errorf("CountryNames[FR] = %q, %v want France, true", name, ok)
+	}
+	if code, ok := presets.CountryAlpha3.LookupString("DEU"); !ok || code != "DE" {
+		t.Errorf("CountryAlpha3[DEU] = %q, %v want DE, true", code, ok)
+	}
+	if name, ok := presets.CurrencyNames.LookupString("EUR"); !ok || name != "Euro" {
+		t.Errorf("CurrencyNames[EUR] = %q, %v want Euro, true", name, ok)
+	}
+	if name, ok := presets.LanguageNames.LookupString("en"); !ok || name != "English" {
+		t.Errorf("LanguageNames[en] = %q, %v want English, true", name, ok)
+	}
+	if tok, ok := presets.GoKeywords.LookupString("func"); !ok || tok != token.FUNC {
+		t.Errorf("GoKeywords[func] = %v, %v want func, true", tok, ok)
+	}
+	if tok, ok := presets.GoKeywords.LookupString("function"); ok {
+		t.Errorf("GoKeywords[function] = %v, expected not to be present", tok)
+	}
+}
+
+func TestMIMETypes(t *testing.T) {
+	for name, want := range map[string]string{
+		"index.html":              "text/html",
+		"photo.jpg":               "image/jpeg",
+		"release-1.2.tar.gz":      "application/x-compressed-tar",
+		"backup.gz":               "application/gzip",
+		"dist/app.tar.zst":        "application/x-zstd-compressed-tar",
+		"site.webmanifest":        "application/manifest+json",
+		"archive.tar.gz.bak.json": "application/json",
+	} {
+		if _, mimeType, ok := presets.MIMETypes.LongestSuffixMatch(name); !ok || mimeType != want {
+			t.Errorf("MIMETypes.LongestSuffixMatch(%s) = %q, %v want %q, true", name, mimeType, ok, want)
+		}
+	}
+	if n, mimeType, ok := presets.MIMETypes.LongestSuffixMatch("README"); ok {
+		t.Errorf("MIMETypes.LongestSuffixMatch(README) = %d, %q, expected not to be present", n, mimeType)
+	}
+}
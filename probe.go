@@ -0,0 +1,105 @@
+package faststringmap
+
+// ProbeBuffer[T] assembles a key byte by byte, such as
+// "service.region.metric" built from its parts, and looks it up without
+// re-walking the trie from the root or allocating. It keeps its position in
+// the trie of the map it last looked up in, and advances it only over the
+// bytes written since, so lookups of a key as it grows cost one step per
+// byte overall. The zero value is an empty buffer ready to use; a buffer
+// reused after Reset keeps its capacity.
+//
+// A ProbeBuffer is not safe for concurrent use.
+type ProbeBuffer[T any] struct {
+	buf []byte
+
+	root   *mapInternalNode // root of the map node is in, or nil if none
+	node   *mapInternalNode // node reached by buf[:walked], or nil if no key starts with it
+	walked int              // number of bytes of buf walked from root
+	h      uint32           // fingerprint state of buf[:walked]
+}
+
+// Reset empties the buffer.
+func (pb *ProbeBuffer[T]) Reset() {
+	pb.buf = pb.buf[:0]
+	pb.root = nil
+}
+
+// WriteByte appends c to the buffer. It always returns nil.
+func (pb *ProbeBuffer[T]) WriteByte(c byte) error {
+	pb.buf = append(pb.buf, c)
+	return nil
+}
+
+// WriteString appends s to the buffer. It always returns len(s), nil.
+func (pb *ProbeBuffer[T]) WriteString(s string) (int, error) {
+	pb.buf = append(pb.buf, s...)
+	return len(s), nil
+}
+
+// Write appends p to the buffer. It always returns len(p), nil.
+func (pb *ProbeBuffer[T]) Write(p []byte) (int, error) {
+	pb.buf = append(pb.buf, p...)
+	return len(p), nil
+}
+
+// Bytes returns the contents of the buffer, which are valid until the next
+// write or Reset.
+func (pb *ProbeBuffer[T]) Bytes() []byte {
+	return pb.buf
+}
+
+// Len returns the number of bytes in the buffer.
+func (pb *ProbeBuffer[T]) Len() int {
+	return len(pb.buf)
+}
+
+// Lookup looks up the contents of the buffer in m, like LookupBytes. Keys
+// of maps with a key transform are transformed as a whole, so for such maps
+// Lookup walks the trie from the root every time.
+func (pb *ProbeBuffer[T]) Lookup(m *Map[T]) (t T, ok bool) {
+	return m.AtIndex(pb.Index(m))
+}
+
+// Index returns the index of the value in m for the contents of the buffer,
+// like IndexBytes.
+func (pb *ProbeBuffer[T]) Index(m *Map[T]) Uint {
+	if m == nil || len(m.store) == 0 {
+		return 0
+	}
+	if m.keyTransform != nil {
+		return m.IndexBytes(pb.buf)
+	}
+
+	if pb.root != &m.store[0] {
+		pb.root, pb.node = &m.store[0], &m.store[0]
+		pb.walked = 0
+		pb.h = m.fingerprintSeed
+	}
+
+	bv, h := pb.node, pb.h
+	for ; bv != nil && pb.walked < len(pb.buf); pb.walked++ {
+		b := pb.buf[pb.walked]
+		if m.fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		h = (h ^ uint32(b)) * 16777619
+
+		ni := b - bv.nextOffset // bytes below nextOffset wrap around past nextLen
+		if ni >= bv.nextLen {
+			bv = nil
+			break
+		}
+		bv = &m.store[bv.nextLo+uint32(ni)]
+	}
+	pb.node, pb.h = bv, h
+
+	if bv == nil || bv.valueOffset == 0 {
+		return 0
+	}
+
+	if m.fingerprints != nil {
+		return m.verifyFingerprint(bv.valueOffset, byte(h^h>>8^h>>16^h>>24))
+	}
+
+	return bv.valueOffset
+}
@@ -0,0 +1,92 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestProbeBuffer(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{
+		{"api", 1},
+		{"api.eu", 2},
+		{"api.eu.latency", 3},
+		{"api.us.latency", 4},
+		{"", 5},
+	}
+	for name, opts := range map[string][]faststringmap.Option{
+		"default":      nil,
+		"fingerprints": {faststringmap.WithFingerprints()},
+		"fold":         {faststringmap.WithFold()},
+	} {
+		m, err := faststringmap.New(entries, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var pb faststringmap.ProbeBuffer[int]
+		steps := []struct {
+			write string
+			want  int
+			ok    bool
+		}{
+			{"", 5, true},
+			{"api", 1, true},
+			{".", 0, false},
+			{"eu", 2, true},
+			{".latency", 3, true},
+			{"x", 0, false},
+			{".more", 0, false},
+		}
+		for _, s := range steps {
+			pb.WriteString(s.write)
+			if v, ok := pb.Lookup(&m); v != s.want || ok != s.ok {
+				t.Errorf("%s: Lookup() of %q = %v, %v want %v, %v", name, pb.Bytes(), v, ok, s.want, s.ok)
+			}
+		}
+
+		pb.Reset()
+		for _, b := range []byte("api.us.latency") {
+			pb.WriteByte(b)
+		}
+		if v, ok := pb.Lookup(&m); !ok || v != 4 {
+			t.Errorf("%s: Lookup() of %q = %v, %v want 4, true", name, pb.Bytes(), v, ok)
+		}
+	}
+}
+
+func TestProbeBufferSwitchMaps(t *testing.T) {
+	a := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"ab", 1}})
+	b := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"abc", 2}})
+
+	var pb faststringmap.ProbeBuffer[int]
+	pb.WriteString("ab")
+	if v, ok := pb.Lookup(&a); !ok || v != 1 {
+		t.Errorf("Lookup(a) = %v, %v want 1, true", v, ok)
+	}
+	pb.WriteByte('c')
+	if v, ok := pb.Lookup(&b); !ok || v != 2 {
+		t.Errorf("Lookup(b) = %v, %v want 2, true", v, ok)
+	}
+	if v, ok := pb.Lookup(&a); ok {
+		t.Errorf("Lookup(a) = %v, true want false", v)
+	}
+}
+
+func TestProbeBufferAllocations(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"service.region.metric", 1}})
+	var pb faststringmap.ProbeBuffer[int]
+	pb.WriteString("service.region.metric") // grow the buffer once
+	allocs := testing.AllocsPerRun(100, func() {
+		pb.Reset()
+		pb.WriteString("service")
+		pb.WriteByte('.')
+		pb.WriteString("region")
+		pb.Lookup(&m)
+		pb.WriteString(".metric")
+		pb.Lookup(&m)
+	})
+	if allocs != 0 {
+		t.Errorf("ProbeBuffer allocates %v times per run want 0", allocs)
+	}
+}
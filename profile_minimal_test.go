@@ -0,0 +1,5 @@
+//go:build faststringmap_minimal || tinygo
+
+package faststringmap_test
+
+const minimalProfile = true
@@ -0,0 +1,7 @@
+//go:build !faststringmap_minimal && !tinygo
+
+package faststringmap_test
+
+// minimalProfile is whether the package is built in the minimal profile,
+// without unsafe, mmap and net/http.
+const minimalProfile = false
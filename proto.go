@@ -0,0 +1,100 @@
+package faststringmap
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Field numbers of the SerializedMap message in
+// proto/faststringmap/v1/map.proto.
+const (
+	protoFieldData       = 1
+	protoFieldValueCodec = 2
+
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+)
+
+// AppendProto appends the map to dst as a protobuf encoded SerializedMap
+// message (see proto/faststringmap/v1/map.proto), using codec to encode its
+// values, and returns the extended slice. codecName is recorded in the
+// message, and may be empty.
+func (m *Map[T]) AppendProto(dst []byte, codec ValueCodec[T], codecName string) ([]byte, error) {
+	data, err := m.AppendBinary(nil, codec)
+	if err != nil {
+		return dst, err
+	}
+
+	dst = appendProtoBytes(dst, protoFieldData, data)
+	if codecName != "" {
+		dst = appendProtoBytes(dst, protoFieldValueCodec, []byte(codecName))
+	}
+	return dst, nil
+}
+
+// UnmarshalProto[T] constructs a Map from a protobuf encoded SerializedMap
+// message, using codec to decode its values, and also returns the codec name
+// recorded in the message. Unknown fields are skipped, so messages written by
+// newer versions of the schema can be read. Like UnmarshalMap, the Map may
+// refer to msg, which must then not be modified while the Map is in use.
+func UnmarshalProto[T any](msg []byte, codec ValueCodec[T]) (m Map[T], codecName string, err error) {
+	var data []byte
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return Map[T]{}, "", ErrInvalidEncoding
+		}
+		msg = msg[n:]
+
+		field, wire := tag>>3, tag&7
+		var value []byte
+		switch wire {
+		case protoWireVarint:
+			_, n = binary.Uvarint(msg)
+		case protoWireFixed64:
+			n = 8
+		case protoWireFixed32:
+			n = 4
+		case protoWireBytes:
+			var size uint64
+			size, n = binary.Uvarint(msg)
+			if n > 0 && size <= uint64(len(msg)-n) {
+				value = msg[n : n+int(size)]
+				n += int(size)
+			} else {
+				n = -1
+			}
+		default:
+			return Map[T]{}, "", fmt.Errorf("faststringmap: unsupported protobuf wire type %d: %w", wire, ErrInvalidEncoding)
+		}
+		if n <= 0 || n > len(msg) {
+			return Map[T]{}, "", ErrInvalidEncoding
+		}
+		msg = msg[n:]
+
+		if wire != protoWireBytes {
+			continue
+		}
+		switch field {
+		case protoFieldData:
+			data = value
+		case protoFieldValueCodec:
+			codecName = string(value)
+		}
+	}
+
+	if m, err = UnmarshalMap(data, codec); err != nil {
+		return Map[T]{}, "", err
+	}
+	return m, codecName, nil
+}
+
+func appendProtoBytes(dst []byte, field int, b []byte) []byte {
+	var buf [2 * binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(field)<<3|protoWireBytes)
+	n += binary.PutUvarint(buf[n:], uint64(len(b)))
+	dst = append(dst, buf[:n]...)
+	return append(dst, b...)
+}
@@ -0,0 +1,74 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	m := faststringmap.NewMap(goldenEntries)
+	msg, err := m.AppendProto(nil, faststringmap.IntCodec[uint32]{}, "int")
+	if err != nil {
+		t.Fatalf("AppendProto() error = %v", err)
+	}
+
+	// field 1 (data), length delimited, holding the serialized map
+	data := serialize(t, m)
+	if msg[0] != 0x0a || !bytes.Contains(msg, data) {
+		t.Errorf("AppendProto() does not start with the data field")
+	}
+
+	got, codecName, err := faststringmap.UnmarshalProto[uint32](msg, faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatalf("UnmarshalProto() error = %v", err)
+	}
+	if codecName != "int" {
+		t.Errorf("UnmarshalProto() codec = %q want int", codecName)
+	}
+	checkEntries(t, &got, goldenEntries)
+}
+
+func TestUnmarshalProtoSkipsUnknownFields(t *testing.T) {
+	m := faststringmap.NewMap(goldenEntries)
+	unknown := []byte{
+		3<<3 | 0, 0x96, 0x01, // field 3, varint 150
+		4<<3 | 1, 1, 2, 3, 4, 5, 6, 7, 8, // field 4, fixed64
+		5<<3 | 2, 2, 'h', 'i', // field 5, bytes
+		6<<3 | 5, 1, 2, 3, 4, // field 6, fixed32
+	}
+	msg, err := m.AppendProto(append([]byte{}, unknown...), faststringmap.IntCodec[uint32]{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, codecName, err := faststringmap.UnmarshalProto[uint32](msg, faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatalf("UnmarshalProto() error = %v", err)
+	}
+	if codecName != "" {
+		t.Errorf("UnmarshalProto() codec = %q want empty", codecName)
+	}
+	checkEntries(t, &got, goldenEntries)
+}
+
+func TestUnmarshalProtoInvalid(t *testing.T) {
+	m := faststringmap.NewMap(goldenEntries)
+	msg, err := m.AppendProto(nil, faststringmap.IntCodec[uint32]{}, "int")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, msg := range map[string][]byte{
+		"empty":     nil,
+		"truncated": msg[:len(msg)/2],
+		"group":     {1<<3 | 3},
+	} {
+		_, _, err := faststringmap.UnmarshalProto[uint32](msg, faststringmap.IntCodec[uint32]{})
+		if !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+			t.Errorf("%s: UnmarshalProto() error = %v want ErrInvalidEncoding", name, err)
+		}
+	}
+}
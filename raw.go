@@ -0,0 +1,18 @@
+package faststringmap
+
+import "alon.kr/x/faststringmap/raw"
+
+// Raw returns a read only view of the trie underlying the map, sharing its
+// nodes, for custom matchers, serializers and FFI bridges. See package raw
+// for the compatibility caveats. Value indices in the trie are those of
+// AtIndex. In the minimal profile, the nodes are a copy.
+func (m *Map[T]) Raw() raw.Trie {
+	if m == nil {
+		return raw.Trie{}
+	}
+	return raw.Trie{
+		Nodes:       rawNodes(m.store),
+		Fold:        m.fold,
+		Transformed: m.keyTransform != nil,
+	}
+}
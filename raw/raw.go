@@ -0,0 +1,92 @@
+// Package raw gives read only access to the trie underlying a
+// faststringmap.Map, for building custom matchers, serializers or FFI
+// bridges on top of it. Get the trie of a map with its Raw method.
+//
+// The layout of the trie is an implementation detail of faststringmap, and
+// unlike the rest of its API, this package may change in incompatible ways
+// in any release that changes the layout. Code using it should be pinned
+// to a version of the module, and tested against each upgrade.
+package raw
+
+import "iter"
+
+// Node is a node of the trie, with the same layout as the nodes of a Map.
+// The children of a node are NextLen consecutive nodes starting at index
+// NextLo, reached by the bytes from NextOffset on. Bytes without a key
+// continuing with them lead to nodes that are not Valid.
+type Node struct {
+	NextLo      uint32 // index in Trie.Nodes of the first child
+	NextLen     byte   // number of children
+	NextOffset  byte   // byte leading to the first child
+	ValueOffset uint32 // index+1 of the value of the key ending at the node, or 0 if none does
+}
+
+// Accepting reports whether a key ends at the node.
+func (n *Node) Accepting() bool {
+	return n.ValueOffset != 0
+}
+
+// Valid reports whether a key ends at the node or continues past it.
+// Nodes that are not valid only fill gaps in ranges of children.
+func (n *Node) Valid() bool {
+	return n.ValueOffset != 0 || n.NextLen != 0
+}
+
+// Child returns the index of the child of the node reached by byte b. ok
+// is false if b is out of the range of children; the child may still not
+// be Valid.
+func (n *Node) Child(b byte) (index uint32, ok bool) {
+	ni := b - n.NextOffset // bytes below NextOffset wrap around past NextLen
+	if ni >= n.NextLen {
+		return 0, false
+	}
+	return n.NextLo + uint32(ni), true
+}
+
+// Trie is a read only view of the trie of a Map. It shares the nodes of the
+// map, which must not be modified.
+type Trie struct {
+	Nodes       []Node // Nodes[0] is the root, if there are any nodes
+	Fold        bool   // whether probes are folded to lower case ASCII, as by WithFold
+	Transformed bool   // whether probes are transformed by a function, as by WithKeyTransform
+}
+
+// Lookup returns the index+1 of the value of key s, as used by the AtIndex
+// method of the map, or 0 if s is not a key. Upper case letters are folded
+// if t.Fold is set, but key transforms are not applied: keys of a
+// Transformed trie must be transformed by the caller.
+func (t Trie) Lookup(s []byte) uint32 {
+	if len(t.Nodes) == 0 {
+		return 0
+	}
+
+	node := &t.Nodes[0]
+	for _, b := range s {
+		if t.Fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		i, ok := node.Child(b)
+		if !ok {
+			return 0
+		}
+		node = &t.Nodes[i]
+	}
+	return node.ValueOffset
+}
+
+// Transitions returns an iterator over the bytes leading from the node at
+// index to its valid children, and the indices of the children, in
+// ascending byte order.
+func (t Trie) Transitions(index uint32) iter.Seq2[byte, uint32] {
+	return func(yield func(byte, uint32) bool) {
+		node := &t.Nodes[index]
+		for i := uint32(0); i < uint32(node.NextLen); i++ {
+			if !t.Nodes[node.NextLo+i].Valid() {
+				continue
+			}
+			if !yield(node.NextOffset+byte(i), node.NextLo+i) {
+				return
+			}
+		}
+	}
+}
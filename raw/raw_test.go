@@ -0,0 +1,61 @@
+package raw_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestTrie(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[int]{
+		{Key: "", Value: 0}, {Key: "ab", Value: 1}, {Key: "abd", Value: 2}, {Key: "ax", Value: 3}, {Key: "b", Value: 4},
+	})
+	trie := m.Raw()
+
+	for _, s := range []string{"", "a", "ab", "abc", "abd", "ax", "b", "bb", "c", "\x00"} {
+		if got, want := trie.Lookup([]byte(s)), m.IndexString(s); got != want {
+			t.Errorf("Lookup(%q) = %d want %d", s, got, want)
+		}
+	}
+
+	type transition struct {
+		from, to int
+		b        byte
+	}
+	var want, got []transition
+	m.ExportTransitions(func(from, to int, b byte, _ bool, _ faststringmap.Uint) {
+		want = append(want, transition{from, to, b})
+	})
+	for from := range trie.Nodes {
+		for b, to := range trie.Transitions(uint32(from)) {
+			got = append(got, transition{from, int(to), b})
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Transitions gives %d transitions want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("transition %d = %v want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTrieFold(t *testing.T) {
+	m, err := faststringmap.New([]faststringmap.MapEntry[int]{{Key: "Key", Value: 1}}, faststringmap.WithFold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	trie := m.Raw()
+	if !trie.Fold || trie.Transformed {
+		t.Errorf("Fold, Transformed = %v, %v want true, false", trie.Fold, trie.Transformed)
+	}
+	if got := trie.Lookup([]byte("KEY")); got != 1 {
+		t.Errorf("Lookup(KEY) = %d want 1", got)
+	}
+
+	var nilMap *faststringmap.Map[int]
+	if got := nilMap.Raw().Lookup(nil); got != 0 {
+		t.Errorf("nil map: Lookup(nil) = %d want 0", got)
+	}
+}
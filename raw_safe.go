@@ -0,0 +1,14 @@
+//go:build faststringmap_minimal || tinygo
+
+package faststringmap
+
+import "alon.kr/x/faststringmap/raw"
+
+// rawNodes returns a copy of store as raw nodes.
+func rawNodes(store []mapInternalNode) []raw.Node {
+	nodes := make([]raw.Node, len(store))
+	for i, n := range store {
+		nodes[i] = raw.Node{NextLo: n.nextLo, NextLen: n.nextLen, NextOffset: n.nextOffset, ValueOffset: n.valueOffset}
+	}
+	return nodes
+}
@@ -0,0 +1,26 @@
+//go:build !faststringmap_minimal && !tinygo
+
+package faststringmap
+
+import (
+	"unsafe"
+
+	"alon.kr/x/faststringmap/raw"
+)
+
+// the nodes of a Map are shared with package raw, so the layouts must match
+var (
+	_ [unsafe.Sizeof(raw.Node{}) - unsafe.Sizeof(mapInternalNode{})]struct{}
+	_ [unsafe.Sizeof(mapInternalNode{}) - unsafe.Sizeof(raw.Node{})]struct{}
+	_ [unsafe.Offsetof(raw.Node{}.NextLen) - unsafe.Offsetof(mapInternalNode{}.nextLen)]struct{}
+	_ [unsafe.Offsetof(mapInternalNode{}.nextLen) - unsafe.Offsetof(raw.Node{}.NextLen)]struct{}
+	_ [unsafe.Offsetof(raw.Node{}.NextOffset) - unsafe.Offsetof(mapInternalNode{}.nextOffset)]struct{}
+	_ [unsafe.Offsetof(mapInternalNode{}.nextOffset) - unsafe.Offsetof(raw.Node{}.NextOffset)]struct{}
+	_ [unsafe.Offsetof(raw.Node{}.ValueOffset) - unsafe.Offsetof(mapInternalNode{}.valueOffset)]struct{}
+	_ [unsafe.Offsetof(mapInternalNode{}.valueOffset) - unsafe.Offsetof(raw.Node{}.ValueOffset)]struct{}
+)
+
+// rawNodes returns store as raw nodes, without copying it.
+func rawNodes(store []mapInternalNode) []raw.Node {
+	return unsafe.Slice((*raw.Node)(unsafe.Pointer(unsafe.SliceData(store))), len(store))
+}
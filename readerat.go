@@ -0,0 +1,139 @@
+package faststringmap
+
+import (
+	"io"
+)
+
+// OpenReaderAt[T] constructs a Map from its serialized form like
+// UnmarshalMapLazy, but reads it from r, which holds size bytes of serialized
+// data. Only the header and the node store are read eagerly. Each value is
+// read from r and decoded using codec on its first access, and memoized from
+// then on, so maps whose values do not fit in memory can still be queried
+// from a local disk or a blob store. r must not change while the Map is in
+// use, and must be safe for concurrent use if the Map is.
+//
+// The checksum is not verified, as that would require reading all of the
+// data. A value that fails to be read or decoded is reported as not present,
+// and AtIndexErr reports why. Errors reading a value are not memoized, so
+// the next access to it reads it again.
+func OpenReaderAt[T any](r io.ReaderAt, size int64, codec ValueCodec[T]) (Map[T], error) {
+	header := make([]byte, serialHeaderSize(serialVersion))
+	if size < int64(len(header)) {
+		header = header[:size]
+	}
+	if err := readFullAt(r, header, 0); err != nil {
+		return Map[T]{}, err
+	}
+
+	h, err := parseSerialHeader(header)
+	if err != nil {
+		return Map[T]{}, err
+	}
+
+	valuesStart := h.valuesStart()
+	if valuesStart > uint64(size) {
+		return Map[T]{}, ErrInvalidEncoding
+	}
+
+	// the node store is followed by the fingerprints, if any
+	nodes := make([]byte, h.offsetsStart()-h.size)
+	if err := readFullAt(r, nodes, int64(h.size)); err != nil {
+		return Map[T]{}, err
+	}
+	var fingerprints []byte
+	if h.flags&serialFlagFingerprints != 0 {
+		nodes, fingerprints = nodes[:h.nNodes*nodeSize], nodes[h.nNodes*nodeSize:]
+	}
+	store, err := loadNodes(nodes, int(h.nValues))
+	if err != nil {
+		return Map[T]{}, err
+	}
+
+	source := &readerAtValues{
+		r:          r,
+		nValues:    int(h.nValues),
+		offsets:    int64(h.offsetsStart()),
+		offsetSize: int(h.offsetSize),
+		values:     int64(valuesStart),
+		size:       uint64(size) - valuesStart,
+	}
+
+	total := make([]byte, source.offsetSize)
+	if err := readFullAt(r, total, source.offsets+int64(source.nValues*source.offsetSize)); err != nil {
+		return Map[T]{}, err
+	}
+	end := decodeOffset(total, source.offsetSize)
+	var metadata map[string]string
+	if h.flags&serialFlagMetadata != 0 && end <= source.size {
+		// the metadata follows the value data
+		b := make([]byte, source.size-end)
+		if err := readFullAt(r, b, source.values+int64(end)); err != nil {
+			return Map[T]{}, err
+		}
+		if metadata, err = decodeMetadata(b); err != nil {
+			return Map[T]{}, err
+		}
+		source.size = end
+	}
+	if end != source.size {
+		return Map[T]{}, ErrInvalidEncoding
+	}
+
+	m := Map[T]{
+		store:         store,
+		lazy:          newLazyValues[T](source, codec),
+		maxKeyLen:     maxKeyLen(store),
+		formatVersion: h.version,
+		fold:          h.flags&serialFlagFold != 0,
+		metadata:      metadata,
+	}
+	m.setFingerprints(fingerprints, h.salt)
+	return m, nil
+}
+
+// readerAtValues reads the encoded values of a serialized map from an
+// io.ReaderAt.
+type readerAtValues struct {
+	r          io.ReaderAt
+	nValues    int
+	offsets    int64 // position of the value offsets in r
+	offsetSize int
+	values     int64  // position of the value data in r
+	size       uint64 // length of the value data
+}
+
+func (s *readerAtValues) len() int {
+	return s.nValues
+}
+
+// value reads the encoded bytes of the value at index i.
+func (s *readerAtValues) value(i int) ([]byte, error) {
+	var buf [16]byte
+	offsets := buf[:2*s.offsetSize]
+	if err := readFullAt(s.r, offsets, s.offsets+int64(i*s.offsetSize)); err != nil {
+		return nil, err
+	}
+
+	lo, hi := decodeOffset(offsets, s.offsetSize), decodeOffset(offsets[s.offsetSize:], s.offsetSize)
+	if lo > hi || hi > s.size {
+		return nil, ErrInvalidEncoding
+	}
+
+	v := make([]byte, hi-lo)
+	if err := readFullAt(s.r, v, s.values+int64(lo)); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// readFullAt reads exactly len(b) bytes from r at offset off.
+func readFullAt(r io.ReaderAt, b []byte, off int64) error {
+	n, err := r.ReadAt(b, off)
+	if n == len(b) {
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		err = ErrInvalidEncoding
+	}
+	return err
+}
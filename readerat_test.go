@@ -0,0 +1,90 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+// countingReaderAt counts the bytes read from it, and fails reads once
+// fail is set.
+type countingReaderAt struct {
+	r    *bytes.Reader
+	read int64
+	fail uint32
+}
+
+func (c *countingReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	if atomic.LoadUint32(&c.fail) != 0 {
+		return 0, errors.New("read failed")
+	}
+	n, err := c.r.ReadAt(b, off)
+	atomic.AddInt64(&c.read, int64(n))
+	return n, err
+}
+
+func TestOpenReaderAt(t *testing.T) {
+	entries := randomSmallStrings(4096, 8)
+	data := serialize(t, faststringmap.NewMap(entries))
+
+	r := &countingReaderAt{r: bytes.NewReader(data)}
+	m, err := faststringmap.OpenReaderAt[uint32](r, int64(len(data)), faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatalf("OpenReaderAt() error = %v", err)
+	}
+	if r.read >= int64(len(data)) {
+		t.Errorf("OpenReaderAt() read %d of %d bytes eagerly", r.read, len(data))
+	}
+
+	checkEntries(t, &m, entries[:100])
+
+	// memoized values no longer need the reader
+	atomic.StoreUint32(&r.fail, 1)
+	checkEntries(t, &m, entries[:100])
+	if _, ok := m.LookupString(entries[100].Key); ok {
+		t.Errorf("LookupString() of a value that failed to be read reported it present")
+	}
+	index := m.IndexString(entries[100].Key)
+	if _, ok, err := m.AtIndexErr(index); ok || err == nil {
+		t.Errorf("AtIndexErr() of a value that failed to be read = %v, %v want false, error", ok, err)
+	}
+
+	// read errors are not memoized
+	atomic.StoreUint32(&r.fail, 0)
+	if v, ok, err := m.AtIndexErr(index); !ok || err != nil || v != entries[100].Value {
+		t.Errorf("AtIndexErr() after the reader recovered = %v, %v, %v want %v, true, nil", v, ok, err, entries[100].Value)
+	}
+	checkEntries(t, &m, entries[100:200])
+}
+
+func TestOpenReaderAtV1(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "methods_v1.fstm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := faststringmap.OpenReaderAt[uint32](bytes.NewReader(data), int64(len(data)), faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatalf("OpenReaderAt() error = %v", err)
+	}
+	if got := m.FormatVersion(); got != 1 {
+		t.Errorf("FormatVersion() = %d want 1", got)
+	}
+	checkEntries(t, &m, goldenEntries)
+}
+
+func TestOpenReaderAtInvalid(t *testing.T) {
+	data := serialize(t, faststringmap.NewMap(goldenEntries))
+
+	for _, n := range []int{0, 10, 24, len(data) - 1} {
+		_, err := faststringmap.OpenReaderAt[uint32](bytes.NewReader(data[:n]), int64(n), faststringmap.IntCodec[uint32]{})
+		if err == nil {
+			t.Errorf("OpenReaderAt() of %d of %d bytes succeeded", n, len(data))
+		}
+	}
+}
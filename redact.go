@@ -0,0 +1,82 @@
+package faststringmap
+
+// Span[T] is the position of a denylisted term in scanned text, with the
+// term's value, such as the category of a secret.
+type Span[T any] struct {
+	Start, End int // text[Start:End] is the term
+	Category   T
+}
+
+// Redactor[T] finds the terms of a denylist in text, for scanning requests
+// or logs for secrets and personal data. It reports every occurrence of
+// every term, including overlapping ones, so that masking them leaves none
+// of them readable. A Redactor is safe for concurrent use.
+type Redactor[T any] struct {
+	m  Map[T]
+	sc Scanner[T]
+}
+
+// NewRedactor[T] returns a Redactor for the terms of denylist, with the
+// categories of the terms as values. opts configure the map of terms, for
+// example WithFold to find terms regardless of case.
+func NewRedactor[T any](denylist []MapEntry[T], opts ...Option) (*Redactor[T], error) {
+	m, err := New(denylist, opts...)
+	if err != nil {
+		return nil, err
+	}
+	r := &Redactor[T]{m: m}
+	r.sc = NewScanner(&r.m, AllOverlapping)
+	return r, nil
+}
+
+// Spans returns the spans of all the terms occurring in s, ordered by start
+// and then by end.
+func (r *Redactor[T]) Spans(s []byte) []Span[T] {
+	return r.AppendSpans(nil, s)
+}
+
+// SpansString returns the spans of all the terms occurring in s, like Spans.
+func (r *Redactor[T]) SpansString(s string) []Span[T] {
+	var spans []Span[T]
+	r.sc.ScanString(s, func(m Match) bool {
+		spans = r.appendSpan(spans, m)
+		return true
+	})
+	return spans
+}
+
+// AppendSpans appends the spans of all the terms occurring in s to dst, for
+// callers reusing a slice across scans.
+func (r *Redactor[T]) AppendSpans(dst []Span[T], s []byte) []Span[T] {
+	r.sc.ScanBytes(s, func(m Match) bool {
+		dst = r.appendSpan(dst, m)
+		return true
+	})
+	return dst
+}
+
+func (r *Redactor[T]) appendSpan(dst []Span[T], m Match) []Span[T] {
+	category, _ := r.m.AtIndex(m.Index)
+	return append(dst, Span[T]{m.Start, m.End, category})
+}
+
+// Redact returns s with every byte of every term occurring in it replaced
+// by mask, and the spans of the terms.
+func (r *Redactor[T]) Redact(s string, mask byte) (string, []Span[T]) {
+	spans := r.SpansString(s)
+	if len(spans) == 0 {
+		return s, nil
+	}
+	b := []byte(s)
+	MaskSpans(b, spans, mask)
+	return string(b), spans
+}
+
+// MaskSpans replaces every byte of s covered by spans with mask, in place.
+func MaskSpans[T any](s []byte, spans []Span[T], mask byte) {
+	for _, sp := range spans {
+		for i := sp.Start; i < sp.End; i++ {
+			s[i] = mask
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestRedactor(t *testing.T) {
+	r, err := faststringmap.NewRedactor([]faststringmap.MapEntry[string]{
+		{"akia1234", "aws-key"},
+		{"1234-5678", "card"},
+		{"hunter2", "password"},
+	}, faststringmap.WithFold())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := "key=AKIA1234-5678 pw=hunter2"
+	want := []faststringmap.Span[string]{
+		{4, 12, "aws-key"},
+		{8, 17, "card"},
+		{21, 28, "password"},
+	}
+	if got := r.SpansString(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("SpansString() = %v want %v", got, want)
+	}
+	if got := r.Spans([]byte(text)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Spans() = %v want %v", got, want)
+	}
+
+	redacted, spans := r.Redact(text, '*')
+	if wantText := "key=************* pw=*******"; redacted != wantText {
+		t.Errorf("Redact() = %q want %q", redacted, wantText)
+	}
+	if !reflect.DeepEqual(spans, want) {
+		t.Errorf("Redact() spans = %v want %v", spans, want)
+	}
+
+	if clean, spans := r.Redact("nothing to see", '*'); clean != "nothing to see" || spans != nil {
+		t.Errorf("Redact() of clean text = %q, %v", clean, spans)
+	}
+
+	if _, err := faststringmap.NewRedactor([]faststringmap.MapEntry[string]{{"a", ""}, {"A", ""}}, faststringmap.WithFold()); err == nil {
+		t.Error("NewRedactor() accepted duplicate terms")
+	}
+}
@@ -0,0 +1,104 @@
+package faststringmap
+
+import (
+	"sync"
+)
+
+// Registry[T] holds many named maps, each made of a base map shared by all
+// of them, plus a small per-tenant overlay. The base is stored once no
+// matter how many tenants use it. A Registry is safe for concurrent use.
+type Registry[T any] struct {
+	base *Map[T]
+
+	mu      sync.RWMutex
+	tenants map[string]*Tenant[T]
+}
+
+// Tenant[T] is a read only view of the base map of a Registry with a
+// tenant's overlay applied. Entries in the overlay take precedence over
+// entries in the base.
+type Tenant[T any] struct {
+	base    *Map[T]
+	overlay Map[overlayValue[T]]
+}
+
+type overlayValue[T any] struct {
+	value   T
+	deleted bool // masks the base entry with the same key
+}
+
+// NewRegistry[T] constructs a Registry whose tenants share the supplied base.
+func NewRegistry[T any](base Map[T]) *Registry[T] {
+	return &Registry[T]{base: &base, tenants: map[string]*Tenant[T]{}}
+}
+
+// Set creates or replaces the named tenant, whose view is the base map with
+// the entries in set added or replaced, and the keys in deleted removed.
+// The overlay is built with the key transform and folding of the base, so
+// its keys and probes are canonicalized the same way. It returns an error
+// wrapping ErrDuplicateKey, and leaves the registry unchanged, if a key
+// appears more than once in set and deleted together.
+func (r *Registry[T]) Set(name string, set []MapEntry[T], deleted []string) (*Tenant[T], error) {
+	entries := make([]MapEntry[overlayValue[T]], 0, len(set)+len(deleted))
+	for _, e := range set {
+		entries = append(entries, MapEntry[overlayValue[T]]{e.Key, overlayValue[T]{value: e.Value}})
+	}
+	for _, k := range deleted {
+		entries = append(entries, MapEntry[overlayValue[T]]{k, overlayValue[T]{deleted: true}})
+	}
+
+	opts := []Option{WithKeyTransform(r.base.keyTransform)}
+	if r.base.fold {
+		opts = append(opts, WithFold())
+	}
+	overlay, err := New(entries, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tenant[T]{base: r.base, overlay: overlay}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tenants[name] = t
+	return t, nil
+}
+
+// Get returns the named tenant.
+func (r *Registry[T]) Get(name string) (t *Tenant[T], ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok = r.tenants[name]
+	return t, ok
+}
+
+// Remove removes the named tenant from the registry.
+func (r *Registry[T]) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tenants, name)
+}
+
+// Len returns the number of tenants in the registry.
+func (r *Registry[T]) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.tenants)
+}
+
+// LookupString looks up the supplied string in the tenant's overlay, and
+// then in the shared base map.
+func (t *Tenant[T]) LookupString(s string) (v T, ok bool) {
+	if o, found := t.overlay.LookupString(s); found {
+		return o.value, !o.deleted
+	}
+	return t.base.LookupString(s)
+}
+
+// LookupBytes looks up the supplied byte slice in the tenant's overlay, and
+// then in the shared base map.
+func (t *Tenant[T]) LookupBytes(s []byte) (v T, ok bool) {
+	if o, found := t.overlay.LookupBytes(s); found {
+		return o.value, !o.deleted
+	}
+	return t.base.LookupBytes(s)
+}
@@ -0,0 +1,95 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestRegistry(t *testing.T) {
+	base := faststringmap.NewMap([]faststringmap.MapEntry[int]{{"a", 1}, {"b", 2}, {"c", 3}})
+	r := faststringmap.NewRegistry(base)
+
+	if _, err := r.Set("acme", []faststringmap.MapEntry[int]{{"b", 20}, {"d", 40}}, []string{"c"}); err != nil {
+		t.Fatalf("Set(acme) error = %v", err)
+	}
+	if _, err := r.Set("other", nil, nil); err != nil {
+		t.Fatalf("Set(other) error = %v", err)
+	}
+
+	acme, ok := r.Get("acme")
+	if !ok {
+		t.Fatalf("Get(acme) not found")
+	}
+	other, _ := r.Get("other")
+
+	for _, tc := range []struct {
+		tenant *faststringmap.Tenant[int]
+		key    string
+		want   int
+		ok     bool
+	}{
+		{acme, "a", 1, true},
+		{acme, "b", 20, true},
+		{acme, "c", 0, false},
+		{acme, "d", 40, true},
+		{other, "b", 2, true},
+		{other, "c", 3, true},
+		{other, "d", 0, false},
+	} {
+		if v, ok := tc.tenant.LookupString(tc.key); v != tc.want || ok != tc.ok {
+			t.Errorf("LookupString(%q) = %v, %v want %v, %v", tc.key, v, ok, tc.want, tc.ok)
+		}
+		if v, ok := tc.tenant.LookupBytes([]byte(tc.key)); v != tc.want || ok != tc.ok {
+			t.Errorf("LookupBytes(%q) = %v, %v want %v, %v", tc.key, v, ok, tc.want, tc.ok)
+		}
+	}
+
+	if _, err := r.Set("acme", []faststringmap.MapEntry[int]{{"b", 21}}, []string{"b"}); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("Set() of a key both set and deleted error = %v want %v", err, faststringmap.ErrDuplicateKey)
+	}
+	if cur, _ := r.Get("acme"); cur != acme {
+		t.Errorf("failed Set() replaced the tenant")
+	}
+
+	r.Remove("other")
+	if _, ok := r.Get("other"); ok || r.Len() != 1 {
+		t.Errorf("Get(other) after Remove = %v, Len() = %d want false, 1", ok, r.Len())
+	}
+}
+
+func TestRegistryFold(t *testing.T) {
+	base, err := faststringmap.New([]faststringmap.MapEntry[int]{{"abc", 1}, {"Def", 2}, {"ghi", 3}}, faststringmap.WithFold())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r := faststringmap.NewRegistry(base)
+	acme, err := r.Set("acme", []faststringmap.MapEntry[int]{{"DEF", 20}}, []string{"abc"})
+	if err != nil {
+		t.Fatalf("Set(acme) error = %v", err)
+	}
+
+	for _, tc := range []struct {
+		key  string
+		want int
+		ok   bool
+	}{
+		{"abc", 0, false},
+		{"ABC", 0, false},
+		{"def", 20, true},
+		{"dEf", 20, true},
+		{"GHI", 3, true},
+	} {
+		if v, ok := acme.LookupString(tc.key); v != tc.want || ok != tc.ok {
+			t.Errorf("LookupString(%q) = %v, %v want %v, %v", tc.key, v, ok, tc.want, tc.ok)
+		}
+		if v, ok := acme.LookupBytes([]byte(tc.key)); v != tc.want || ok != tc.ok {
+			t.Errorf("LookupBytes(%q) = %v, %v want %v, %v", tc.key, v, ok, tc.want, tc.ok)
+		}
+	}
+
+	if _, err := r.Set("acme", []faststringmap.MapEntry[int]{{"ABC", 10}}, []string{"abc"}); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("Set() of a key both set and deleted after folding error = %v want %v", err, faststringmap.ErrDuplicateKey)
+	}
+}
@@ -0,0 +1,104 @@
+package faststringmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrNotModified is returned by a FetchFunc when the data has not changed
+// since the version identified by the supplied ETag.
+var ErrNotModified = errors.New("faststringmap: not modified")
+
+// FetchFunc fetches the serialized form of a map from a remote source. etag
+// identifies the version of the data that is currently loaded, or is empty if
+// none is. It returns the data together with an ETag identifying its version,
+// or ErrNotModified if the data is unchanged. An empty ETag means the version
+// of the data is unknown, in which case it is always loaded.
+type FetchFunc func(ctx context.Context, etag string) (body io.ReadCloser, newETag string, err error)
+
+// Loader[T] keeps an AtomicMap up to date with a serialized map fetched from
+// a remote source. New versions of the map are validated in full before they
+// replace the current map, so a corrupt download never affects lookups.
+// A Loader is safe for concurrent use.
+type Loader[T any] struct {
+	fetch  FetchFunc
+	codec  ValueCodec[T]
+	target *AtomicMap[T]
+
+	mu   sync.Mutex // serializes refreshes
+	etag string     // ETag of the currently loaded version
+}
+
+// NewLoader[T] constructs a Loader that fetches maps using fetch, decodes
+// their values using codec, and stores them in target.
+func NewLoader[T any](fetch FetchFunc, codec ValueCodec[T], target *AtomicMap[T]) *Loader[T] {
+	return &Loader[T]{fetch: fetch, codec: codec, target: target}
+}
+
+// Refresh fetches the map, and replaces the map in the target if it has
+// changed since it was last loaded. changed reports whether it was replaced.
+func (l *Loader[T]) Refresh(ctx context.Context) (changed bool, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	body, etag, err := l.fetch(ctx, l.etag)
+	if errors.Is(err, ErrNotModified) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+
+	if etag != "" && etag == l.etag {
+		return false, nil
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return false, err
+	}
+	m, err := UnmarshalMap(data, l.codec)
+	if err != nil {
+		return false, fmt.Errorf("faststringmap: loading version %q: %w", etag, err)
+	}
+
+	l.target.Store(m)
+	l.etag = etag
+	return true, nil
+}
+
+// ETag returns the ETag of the currently loaded version, which is empty if
+// no version was loaded, or its version is unknown.
+func (l *Loader[T]) ETag() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.etag
+}
+
+// Run calls Refresh every interval until ctx is done, and returns ctx.Err().
+// Errors returned by Refresh are passed to onError, if it is not nil, and
+// otherwise ignored, leaving the current map in place until the next
+// successful refresh. Errors caused by ctx being done are not reported.
+// Run does not refresh before the first interval has passed, so callers
+// typically call Refresh once at startup.
+func (l *Loader[T]) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			_, err := l.Refresh(ctx)
+			if err != nil && ctx.Err() == nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !faststringmap_minimal && !tinygo
+
+package faststringmap
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTPFetcher returns a FetchFunc that fetches the data from url using
+// client, making a conditional request when a version is already loaded.
+// A nil client means http.DefaultClient. This works with any HTTP server or
+// object store that supports ETags, including S3 and GCS.
+func HTTPFetcher(client *http.Client, url string) FetchFunc {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(ctx context.Context, etag string) (io.ReadCloser, string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return resp.Body, resp.Header.Get("ETag"), nil
+		case http.StatusNotModified:
+			resp.Body.Close()
+			return nil, "", ErrNotModified
+		default:
+			resp.Body.Close()
+			return nil, "", fmt.Errorf("faststringmap: fetching %s: %s", url, resp.Status)
+		}
+	}
+}
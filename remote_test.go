@@ -0,0 +1,122 @@
+//go:build !faststringmap_minimal && !tinygo
+
+package faststringmap_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"alon.kr/x/faststringmap"
+)
+
+// versionedServer serves a serialized map, with an ETag identifying its
+// version, and counts the full responses it sends.
+type versionedServer struct {
+	mu      sync.Mutex
+	data    []byte
+	etag    string
+	fetches int
+}
+
+func (s *versionedServer) set(data []byte, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data, s.etag = data, etag
+}
+
+func (s *versionedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("If-None-Match") == s.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.fetches++
+	w.Header().Set("ETag", s.etag)
+	w.Write(s.data)
+}
+
+func TestLoader(t *testing.T) {
+	replacement := []faststringmap.MapEntry[uint32]{{"CONNECT", 8}, {"TRACE", 9}}
+
+	s := &versionedServer{}
+	s.set(serialize(t, faststringmap.NewMap(goldenEntries)), `"v1"`)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	var a faststringmap.AtomicMap[uint32]
+	l := faststringmap.NewLoader[uint32](faststringmap.HTTPFetcher(srv.Client(), srv.URL), faststringmap.IntCodec[uint32]{}, &a)
+	ctx := context.Background()
+
+	refresh := func(wantChanged bool) {
+		t.Helper()
+		changed, err := l.Refresh(ctx)
+		if err != nil {
+			t.Fatalf("Refresh() error = %v", err)
+		}
+		if changed != wantChanged {
+			t.Errorf("Refresh() changed = %v want %v", changed, wantChanged)
+		}
+	}
+
+	refresh(true)
+	checkEntries(t, a.Load(), goldenEntries)
+	refresh(false)
+	if s.fetches != 1 {
+		t.Errorf("fetched %d times want 1", s.fetches)
+	}
+
+	s.set([]byte("FSTM garbage"), `"v2"`)
+	if _, err := l.Refresh(ctx); !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+		t.Errorf("Refresh() of corrupt data error = %v want ErrInvalidEncoding", err)
+	}
+	checkEntries(t, a.Load(), goldenEntries)
+	if got := l.ETag(); got != `"v1"` {
+		t.Errorf("ETag() = %s want \"v1\"", got)
+	}
+
+	s.set(serialize(t, faststringmap.NewMap(replacement)), `"v3"`)
+	refresh(true)
+	checkEntries(t, a.Load(), replacement)
+}
+
+func TestLoaderRun(t *testing.T) {
+	s := &versionedServer{}
+	s.set(serialize(t, faststringmap.NewMap(goldenEntries)), `"v1"`)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	var a faststringmap.AtomicMap[uint32]
+	l := faststringmap.NewLoader[uint32](faststringmap.HTTPFetcher(srv.Client(), srv.URL), faststringmap.IntCodec[uint32]{}, &a)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- l.Run(ctx, time.Millisecond, func(err error) { t.Error(err) }) }()
+
+	for l.ETag() != `"v1"` && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v want context.Canceled", err)
+	}
+	checkEntries(t, a.Load(), goldenEntries)
+}
+
+func TestHTTPFetcherStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	var a faststringmap.AtomicMap[uint32]
+	l := faststringmap.NewLoader[uint32](faststringmap.HTTPFetcher(srv.Client(), srv.URL), faststringmap.IntCodec[uint32]{}, &a)
+	if _, err := l.Refresh(context.Background()); err == nil {
+		t.Errorf("Refresh() of missing map succeeded")
+	}
+}
@@ -0,0 +1,81 @@
+package faststringmap
+
+import "io"
+
+// ReplacingWriter is an io.Writer rewriting the keys of a map occurring in
+// the data written to it with their values, before writing it on to an
+// underlying writer. Keys are found with the LeftmostLongest policy, also
+// when they span several writes, so redaction or substitution can be added
+// to existing writer pipelines. Data that could still be the start of a key
+// is held back until more data is written or Close is called.
+type ReplacingWriter struct {
+	w       io.Writer
+	m       Map[[]byte]
+	sc      Scanner[[]byte]
+	keyLen  int    // length of the longest key
+	pending []byte // data written but not yet passed on
+	out     []byte // scratch space for the rewritten data
+	err     error  // first error writing to w
+}
+
+// NewReplacingWriter returns a ReplacingWriter writing to w, replacing the
+// keys of m with their values. Close must be called after the last write to
+// pass on the data held back.
+func NewReplacingWriter(w io.Writer, m Map[[]byte]) *ReplacingWriter {
+	rw := &ReplacingWriter{w: w, m: m, keyLen: m.MaxKeyLen()}
+	rw.sc = NewScanner(&rw.m, LeftmostLongest)
+	return rw
+}
+
+// Write rewrites p and writes the result to the underlying writer, except
+// for a tail of less than the length of the longest key, which is held back
+// until it is known whether a key starts in it.
+func (rw *ReplacingWriter) Write(p []byte) (n int, err error) {
+	if rw.err != nil {
+		return 0, rw.err
+	}
+	rw.pending = append(rw.pending, p...)
+	if err := rw.flush(len(rw.pending) - rw.keyLen); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close rewrites and writes the data held back to the underlying writer.
+// It does not close the underlying writer.
+func (rw *ReplacingWriter) Close() error {
+	if rw.err != nil {
+		return rw.err
+	}
+	return rw.flush(len(rw.pending))
+}
+
+// flush rewrites and writes the pending data up to the position scanning
+// for keys starting before to leaves off.
+func (rw *ReplacingWriter) flush(to int) error {
+	if to <= 0 {
+		return nil
+	}
+
+	out := rw.out[:0]
+	last := 0 // end of the last match
+	next, _ := scan(rw.sc, rw.pending, 0, to, func(mt Match) bool {
+		v, _ := rw.m.AtIndex(mt.Index)
+		out = append(out, rw.pending[last:mt.Start]...)
+		out = append(out, v...)
+		last = mt.End
+		return true
+	})
+	out = append(out, rw.pending[last:next]...)
+	rw.out = out
+	rw.pending = rw.pending[:copy(rw.pending, rw.pending[next:])]
+
+	if len(out) == 0 {
+		return nil
+	}
+	if _, err := rw.w.Write(out); err != nil {
+		rw.err = err
+		return err
+	}
+	return nil
+}
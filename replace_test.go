@@ -0,0 +1,64 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestReplacingWriter(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[[]byte]{
+		{"password", []byte("********")},
+		{"pass", []byte("[p]")},
+		{"secret-token", []byte("[redacted]")},
+		{"x", nil},
+	})
+
+	tests := []struct {
+		in, want string
+	}{
+		{"user=bob password=hunter2", "user=bob ********=hunter2"},
+		{"pass passwor password", "[p] [p]wor ********"},
+		{"a secret-token and a secret-toke", "a [redacted] and a secret-toke"},
+		{"xoxo", "oo"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		for _, size := range []int{1, 3, len(tt.in) + 1} {
+			var sb strings.Builder
+			rw := faststringmap.NewReplacingWriter(&sb, m)
+			for in := tt.in; len(in) > 0; {
+				n := min(size, len(in))
+				if _, err := rw.Write([]byte(in[:n])); err != nil {
+					t.Fatal(err)
+				}
+				in = in[n:]
+			}
+			if err := rw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("writing %q in pieces of %d gives %q want %q", tt.in, size, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestReplacingWriterError(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[[]byte]{{"a", []byte("b")}})
+	errWrite := errors.New("write failed")
+	rw := faststringmap.NewReplacingWriter(errorWriter{errWrite}, m)
+
+	if _, err := rw.Write([]byte("aaa")); !errors.Is(err, errWrite) {
+		t.Errorf("Write() error = %v want %v", err, errWrite)
+	}
+	if err := rw.Close(); !errors.Is(err, errWrite) {
+		t.Errorf("Close() error = %v want %v", err, errWrite)
+	}
+}
+
+type errorWriter struct{ err error }
+
+func (w errorWriter) Write(p []byte) (int, error) { return 0, w.err }
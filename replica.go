@@ -0,0 +1,97 @@
+package faststringmap
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ReplicatedMap[T] holds copies of a map, such as one per NUMA node, and
+// routes lookups to the copy local to the caller, for services where
+// cross-socket memory traffic on a single shared map limits throughput.
+// Which copy is local is decided by a function supplied by the caller, for
+// example one calling getcpu(2) on Linux. A ReplicatedMap is read only,
+// and safe for concurrent use.
+//
+// Replicas are created on their first use, by the goroutine first using
+// them, so that under the default first-touch memory policy of Linux their
+// memory is allocated on the node running that goroutine. To control
+// placement fully, create each replica ahead of use with Replica, from a
+// goroutine locked to a thread bound to its node. Replicas copy the node
+// store, values, fingerprints and retained keys of the map; the values of
+// maps decoding values lazily are shared.
+type ReplicatedMap[T any] struct {
+	src      *Map[T]
+	locate   func() int
+	replicas []replica[T]
+}
+
+type replica[T any] struct {
+	m  atomic.Pointer[Map[T]]
+	mu sync.Mutex // serializes the creation of the replica
+}
+
+// NewReplicatedMap[T] returns a ReplicatedMap with n replicas of m, which
+// must not be modified while the ReplicatedMap is in use. locate returns
+// the replica local to the calling goroutine; results outside [0, n) are
+// mapped into it.
+func NewReplicatedMap[T any](m *Map[T], n int, locate func() int) *ReplicatedMap[T] {
+	if n < 1 {
+		n = 1
+	}
+	return &ReplicatedMap[T]{src: m, locate: locate, replicas: make([]replica[T], n)}
+}
+
+// Len returns the number of replicas.
+func (r *ReplicatedMap[T]) Len() int {
+	return len(r.replicas)
+}
+
+// Local returns the replica local to the calling goroutine, creating it if
+// it is not yet created. As goroutines may move between threads at any
+// time, it is only a hint; as it calls the locate function, lookups in
+// batches are faster made on a single replica returned by Local.
+func (r *ReplicatedMap[T]) Local() *Map[T] {
+	i := uint(r.locate()) % uint(len(r.replicas))
+	return r.Replica(int(i))
+}
+
+// Replica returns replica i, creating it if it is not yet created. i must
+// be in [0, Len()).
+func (r *ReplicatedMap[T]) Replica(i int) *Map[T] {
+	rep := &r.replicas[i]
+	if m := rep.m.Load(); m != nil {
+		return m
+	}
+
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	if m := rep.m.Load(); m != nil {
+		return m
+	}
+	m := r.src.clone()
+	rep.m.Store(&m)
+	return &m
+}
+
+// LookupString looks up the supplied string in the local replica.
+func (r *ReplicatedMap[T]) LookupString(s string) (t T, ok bool) {
+	return r.Local().LookupString(s)
+}
+
+// LookupBytes looks up the supplied byte slice in the local replica. Like
+// Map.LookupBytes, it does not allocate.
+func (r *ReplicatedMap[T]) LookupBytes(s []byte) (t T, ok bool) {
+	return r.Local().LookupBytes(s)
+}
+
+// clone returns a copy of the map that shares no memory with it, except for
+// the bytes of retained keys, lazily decoded values and metadata.
+func (m *Map[T]) clone() Map[T] {
+	c := *m
+	c.store = slices.Clone(m.store)
+	c.values = slices.Clone(m.values)
+	c.fingerprints = slices.Clone(m.fingerprints)
+	c.keys = slices.Clone(m.keys)
+	return c
+}
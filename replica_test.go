@@ -0,0 +1,65 @@
+package faststringmap_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestReplicatedMap(t *testing.T) {
+	entries := randomSmallStrings(2000, 8)
+	m, err := faststringmap.New(entries, faststringmap.WithFingerprints(), faststringmap.WithRetainKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var node atomic.Int64
+	r := faststringmap.NewReplicatedMap(&m, 4, func() int { return int(node.Load()) })
+	if r.Len() != 4 {
+		t.Fatalf("Len() = %d want 4", r.Len())
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, e := range entries {
+				node.Store(int64(g + i))
+				if v, ok := r.LookupString(e.Key); !ok || v != e.Value {
+					t.Errorf("LookupString(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := map[*faststringmap.Map[uint32]]bool{&m: true}
+	for i := 0; i < r.Len(); i++ {
+		rep := r.Replica(i)
+		if seen[rep] {
+			t.Errorf("Replica(%d) is shared", i)
+		}
+		seen[rep] = true
+		if r.Replica(i) != rep {
+			t.Errorf("Replica(%d) changed", i)
+		}
+		checkEntries(t, rep, entries)
+		if key, _, ok := rep.LookupKey(entries[0].Key); !ok || key != entries[0].Key {
+			t.Errorf("Replica(%d).LookupKey(%q) = %q, %v", i, entries[0].Key, key, ok)
+		}
+	}
+
+	node.Store(6)
+	if r.Local() != r.Replica(2) {
+		t.Errorf("Local() with locate() = 6 is not Replica(2)")
+	}
+
+	key := []byte(entries[0].Key)
+	if n := testing.AllocsPerRun(100, func() { r.LookupBytes(key) }); n != 0 {
+		t.Errorf("LookupBytes allocates %v times", n)
+	}
+}
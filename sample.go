@@ -0,0 +1,74 @@
+package faststringmap
+
+import "math/rand"
+
+// Sampler[T] samples keys of a map at random, in proportion to weights of
+// their values, such as request frequencies, for generating realistic test
+// traffic from real dictionaries. It stores the total weight under every
+// prefix, so a sample costs a walk down to the sampled key.
+type Sampler[T any] struct {
+	weights PrefixAggregate[T, float64]
+	weight  func(T) float64
+}
+
+// NewSampler[T] returns a Sampler for the keys of m, weighted by the weight
+// of their values. Weights must not be negative, and keys of zero weight
+// are never sampled. A nil weight samples all keys uniformly. The map must
+// not change while the sampler is in use.
+func NewSampler[T any](m *Map[T], weight func(T) float64) Sampler[T] {
+	if weight == nil {
+		weight = func(T) float64 { return 1 }
+	}
+	return Sampler[T]{
+		weights: NewPrefixAggregate(m, 0, func(a float64, t T) float64 { return a + weight(t) }),
+		weight:  weight,
+	}
+}
+
+// SampleUnderPrefix returns a key starting with prefix, including prefix
+// itself, and its value, sampled with a probability proportional to its
+// weight using rng. ok is false if no key of positive weight starts with
+// prefix. Like IndicesUnderPrefix, prefix is folded or transformed like a
+// probe, and keys are returned as stored.
+func (s *Sampler[T]) SampleUnderPrefix(prefix string, rng *rand.Rand) (key string, t T, ok bool) {
+	i, ok := s.weights.nodeIndex(prefix)
+	if !ok || s.weights.aggs[i] <= 0 {
+		return "", t, false
+	}
+
+	m, aggs := s.weights.m, s.weights.aggs
+	k := []byte(m.canonicalKey(prefix))
+	r := rng.Float64() * aggs[i]
+	for {
+		node := &m.store[i]
+		if node.valueOffset != 0 {
+			t, _ = m.AtIndex(node.valueOffset)
+			w := s.weight(t)
+			if r < w || node.nextLen == 0 {
+				return string(k), t, true
+			}
+			r -= w
+		}
+
+		// choose a child by the weights under it, falling back to the last
+		// child of positive weight if rounding leaves r past all of them
+		next, nextByte := Uint(0), byte(0)
+		for c := Uint(0); c < Uint(node.nextLen); c++ {
+			ci := node.nextLo + c
+			if aggs[ci] <= 0 {
+				continue // not a valid byte, or no weight
+			}
+			next, nextByte = ci, node.nextOffset+byte(c)
+			if r < aggs[ci] {
+				break
+			}
+			r -= aggs[ci]
+		}
+		if next == 0 {
+			// only the key at node has weight, and rounding skipped it
+			return string(k), t, true
+		}
+		i = next
+		k = append(k, nextByte)
+	}
+}
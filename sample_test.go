@@ -0,0 +1,67 @@
+package faststringmap_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestSampleUnderPrefix(t *testing.T) {
+	m := faststringmap.FromMap(map[string]int{
+		"a": 1, "ab": 2, "abc": 0, "b": 3, "ba": 4,
+	})
+	s := faststringmap.NewSampler(&m, func(v int) float64 { return float64(v) })
+	rng := rand.New(rand.NewSource(1))
+
+	const n = 100000
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		key, v, ok := s.SampleUnderPrefix("", rng)
+		if !ok {
+			t.Fatal("SampleUnderPrefix(\"\") found no key")
+		}
+		if want, _ := m.LookupString(key); v != want {
+			t.Fatalf("SampleUnderPrefix(\"\") = %q, %d want value %d", key, v, want)
+		}
+		counts[key]++
+	}
+	if counts["abc"] != 0 {
+		t.Errorf("sampled key of zero weight %d times", counts["abc"])
+	}
+	for key, w := range map[string]float64{"a": 1, "ab": 2, "b": 3, "ba": 4} {
+		got, want := float64(counts[key])/n, w/10
+		if math.Abs(got-want) > 0.01 {
+			t.Errorf("key %q sampled with frequency %.3f want %.3f", key, got, want)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		if key, _, ok := s.SampleUnderPrefix("b", rng); !ok || (key != "b" && key != "ba") {
+			t.Fatalf("SampleUnderPrefix(b) = %q, %v", key, ok)
+		}
+		if key, _, ok := s.SampleUnderPrefix("ab", rng); !ok || key != "ab" {
+			t.Fatalf("SampleUnderPrefix(ab) = %q, %v want ab, true", key, ok)
+		}
+	}
+	for _, p := range []string{"abc", "c", "bb"} {
+		if key, _, ok := s.SampleUnderPrefix(p, rng); ok {
+			t.Errorf("SampleUnderPrefix(%q) = %q, true want false", p, key)
+		}
+	}
+
+	folded, err := faststringmap.New([]faststringmap.MapEntry[int]{{"Ab", 1}, {"b", 2}}, faststringmap.WithFold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := faststringmap.NewSampler(&folded, nil)
+	if key, v, ok := fs.SampleUnderPrefix("AB", rng); key != "ab" || v != 1 || !ok {
+		t.Errorf("SampleUnderPrefix(AB) of a folded map = %q, %v, %v want ab, 1, true", key, v, ok)
+	}
+
+	uniform := faststringmap.NewSampler(&m, nil)
+	if _, _, ok := uniform.SampleUnderPrefix("abc", rng); !ok {
+		t.Error("uniform SampleUnderPrefix(abc) found no key")
+	}
+}
@@ -0,0 +1,188 @@
+package faststringmap
+
+import (
+	"errors"
+	"io"
+)
+
+// MatchPolicy selects which matches a Scanner reports when keys overlap in
+// the scanned text.
+type MatchPolicy uint8
+
+const (
+	// LeftmostLongest reports the longest key starting at the leftmost
+	// position with a match, and resumes scanning after it. This is the
+	// policy of tokenizers.
+	LeftmostLongest MatchPolicy = iota
+	// LeftmostFirst reports the first key found starting at the leftmost
+	// position with a match, which is the shortest one, and resumes
+	// scanning after it.
+	LeftmostFirst
+	// AllOverlapping reports every occurrence of every key, including
+	// occurrences overlapping or nested in others, ordered by start and
+	// then by end. This is the policy of security scanning.
+	AllOverlapping
+)
+
+// Match is an occurrence of a key in scanned text.
+type Match struct {
+	Start, End int  // text[Start:End] is the key
+	Index      Uint // index of the key's value, for AtIndex
+}
+
+// Scanner[T] finds the keys of a map occurring in text, such as dictionary
+// terms in log lines. The empty key never matches. A Scanner is a small
+// value; scans with a different policy use another Scanner for the same
+// map, which is safe for concurrent use.
+type Scanner[T any] struct {
+	m      *Map[T]
+	policy MatchPolicy
+}
+
+// NewScanner[T] returns a Scanner finding the keys of m with the supplied
+// policy. Upper case letters of the text are folded if m was built with
+// WithFold; key transforms are not applied.
+func NewScanner[T any](m *Map[T], policy MatchPolicy) Scanner[T] {
+	return Scanner[T]{m: m, policy: policy}
+}
+
+// WithPolicy returns a Scanner for the same map with another policy.
+func (sc Scanner[T]) WithPolicy(policy MatchPolicy) Scanner[T] {
+	sc.policy = policy
+	return sc
+}
+
+// ScanString calls fn for every match in s, as selected by the policy, in
+// order. Scanning stops early if fn returns false.
+func (sc Scanner[T]) ScanString(s string, fn func(Match) bool) {
+	scan(sc, s, 0, len(s), fn)
+}
+
+// ScanBytes calls fn for every match in s, like ScanString. It does not
+// retain s.
+func (sc Scanner[T]) ScanBytes(s []byte, fn func(Match) bool) {
+	scan(sc, s, 0, len(s), fn)
+}
+
+// ScanReader calls fn for every match in the text read from r, like
+// ScanString, with the positions of matches counted from the start of the
+// stream. It reads r in chunks, and holds at most a chunk and the length of
+// the longest key in memory, so matches spanning chunks are found in input
+// of any size. Scanning stops early if fn returns false. ScanReader returns
+// the first error reading r other than io.EOF.
+func (sc Scanner[T]) ScanReader(r io.Reader, fn func(Match) bool) error {
+	if sc.m == nil || len(sc.m.store) == 0 {
+		return nil
+	}
+	keyLen := sc.m.MaxKeyLen()
+	if keyLen == noKeyLenLimit {
+		return errUnboundedScan
+	}
+
+	buf := make([]byte, 0, scanChunkSize+keyLen)
+	base := 0 // position of buf[0] in the stream
+	for {
+		n, err := io.ReadFull(r, buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		eof := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !eof {
+			return err
+		}
+
+		// matches starting before to are entirely in buf
+		to := len(buf)
+		if !eof {
+			to -= keyLen
+		}
+		next, ok := scan(sc, buf, 0, to, func(m Match) bool {
+			m.Start += base
+			m.End += base
+			return fn(m)
+		})
+		if !ok || eof {
+			return nil
+		}
+
+		base += next
+		buf = buf[:copy(buf, buf[next:])]
+	}
+}
+
+// scanChunkSize is the size of the chunks ScanReader reads.
+const scanChunkSize = 64 << 10
+
+var errUnboundedScan = errors.New("faststringmap: can not scan a stream for keys of unknown length")
+
+// scan reports the matches starting in s[from:to], as selected by the
+// policy, and returns false if fn did. Matches may extend past to, up to
+// the end of s. next is the position scanning resumes from, which is past
+// to if a reported match extends past it.
+func scan[T any, S string | []byte](sc Scanner[T], s S, from, to int, fn func(Match) bool) (next int, ok bool) {
+	m := sc.m
+	if m == nil || len(m.store) == 0 {
+		return to, true
+	}
+
+	i := from
+	for i < to {
+		if sc.policy == AllOverlapping {
+			cont := true
+			matchesAt(m, s, i, func(end int, index Uint) bool {
+				cont = fn(Match{i, end, index})
+				return cont
+			})
+			if !cont {
+				return i, false
+			}
+			i++
+			continue
+		}
+
+		var match Match
+		matchesAt(m, s, i, func(end int, index Uint) bool {
+			match = Match{i, end, index}
+			return sc.policy == LeftmostLongest
+		})
+		if match.Index == 0 {
+			i++
+			continue
+		}
+		if !fn(match) {
+			return match.End, false
+		}
+		i = match.End
+	}
+	return i, true
+}
+
+// matchesAt calls fn with the end and value index of every non-empty key
+// that s[start:] begins with, from the shortest to the longest key, until fn
+// returns false.
+func matchesAt[T any, S string | []byte](m *Map[T], s S, start int, fn func(end int, index Uint) bool) {
+	bv := &m.store[0]
+	h := m.fingerprintSeed // fingerprint of the match, computed on the way
+	for i, n := start, len(s); i < n; i++ {
+		b := s[i]
+		if m.fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		h = (h ^ uint32(b)) * 16777619
+
+		if b < bv.nextOffset {
+			return
+		}
+		ni := b - bv.nextOffset
+		if ni >= bv.nextLen {
+			return
+		}
+		bv = &m.store[bv.nextLo+uint32(ni)]
+
+		index := bv.valueOffset
+		if index != 0 && m.fingerprints != nil {
+			index = m.verifyFingerprint(index, byte(h^h>>8^h>>16^h>>24))
+		}
+		if index != 0 && !fn(i+1, index) {
+			return
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"alon.kr/x/faststringmap"
+)
+
+var scanKeys = []faststringmap.MapEntry[string]{
+	{"he", "HE"},
+	{"hers", "HERS"},
+	{"his", "HIS"},
+	{"she", "SHE"},
+	{"her", "HER"},
+}
+
+// scanCases are shared by the tests of all the ways of scanning, each
+// giving the matches as text[Start:End]@Start.
+var scanCases = []struct {
+	text   string
+	policy faststringmap.MatchPolicy
+	want   []string
+}{
+	{"ushers", faststringmap.LeftmostLongest, []string{"she@1"}},
+	{"ushers", faststringmap.LeftmostFirst, []string{"she@1"}},
+	{"ushers", faststringmap.AllOverlapping, []string{"she@1", "he@2", "her@2", "hers@2"}},
+	{"his hers", faststringmap.LeftmostLongest, []string{"his@0", "hers@4"}},
+	{"his hers", faststringmap.LeftmostFirst, []string{"his@0", "he@4"}},
+	{"hehe", faststringmap.AllOverlapping, []string{"he@0", "he@2"}},
+	{"", faststringmap.AllOverlapping, nil},
+	{"nothing", faststringmap.LeftmostLongest, nil},
+}
+
+// formatMatches returns the matches found in text as text[Start:End]@Start.
+func formatMatches[T any](m *faststringmap.Map[T], text string, matches []faststringmap.Match) []string {
+	var out []string
+	for _, mt := range matches {
+		if _, ok := m.AtIndex(mt.Index); !ok {
+			out = append(out, fmt.Sprintf("bad index %d", mt.Index))
+		}
+		out = append(out, fmt.Sprintf("%s@%d", text[mt.Start:mt.End], mt.Start))
+	}
+	return out
+}
+
+func TestScanner(t *testing.T) {
+	m := faststringmap.NewMap(scanKeys)
+	for _, tt := range scanCases {
+		sc := faststringmap.NewScanner(&m, tt.policy)
+
+		var matches []faststringmap.Match
+		sc.ScanString(tt.text, func(mt faststringmap.Match) bool {
+			matches = append(matches, mt)
+			return true
+		})
+		if got := formatMatches(&m, tt.text, matches); !slices.Equal(got, tt.want) {
+			t.Errorf("policy %d: ScanString(%q) = %q want %q", tt.policy, tt.text, got, tt.want)
+		}
+
+		matches = matches[:0]
+		sc.ScanBytes([]byte(tt.text), func(mt faststringmap.Match) bool {
+			matches = append(matches, mt)
+			return true
+		})
+		if got := formatMatches(&m, tt.text, matches); !slices.Equal(got, tt.want) {
+			t.Errorf("policy %d: ScanBytes(%q) = %q want %q", tt.policy, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestScannerStop(t *testing.T) {
+	m := faststringmap.NewMap(scanKeys)
+	for _, policy := range []faststringmap.MatchPolicy{faststringmap.LeftmostLongest, faststringmap.LeftmostFirst, faststringmap.AllOverlapping} {
+		n := 0
+		faststringmap.NewScanner(&m, policy).ScanString("he he he", func(faststringmap.Match) bool {
+			n++
+			return n < 2
+		})
+		if n != 2 {
+			t.Errorf("policy %d: scanning continued after fn returned false: %d calls", policy, n)
+		}
+	}
+}
+
+func TestScannerFold(t *testing.T) {
+	m, err := faststringmap.New(scanKeys, faststringmap.WithFold(), faststringmap.WithFingerprints())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	faststringmap.NewScanner(&m, faststringmap.LeftmostLongest).ScanString("HIS Hers", func(mt faststringmap.Match) bool {
+		v, _ := m.AtIndex(mt.Index)
+		got = append(got, v)
+		return true
+	})
+	if want := []string{"HIS", "HERS"}; !slices.Equal(got, want) {
+		t.Errorf("fold scan = %q want %q", got, want)
+	}
+}
+
+func TestScanReader(t *testing.T) {
+	m := faststringmap.NewMap(scanKeys)
+	for _, tt := range scanCases {
+		sc := faststringmap.NewScanner(&m, tt.policy)
+
+		var matches []faststringmap.Match
+		err := sc.ScanReader(strings.NewReader(tt.text), func(mt faststringmap.Match) bool {
+			matches = append(matches, mt)
+			return true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := formatMatches(&m, tt.text, matches); !slices.Equal(got, tt.want) {
+			t.Errorf("policy %d: ScanReader(%q) = %q want %q", tt.policy, tt.text, got, tt.want)
+		}
+
+		// place the text across the boundaries of the chunks read, and
+		// compare with scanning the whole text at once
+		for _, pad := range []int{64<<10 - 3, 64<<10 - 1, 128<<10 - 2} {
+			text := strings.Repeat("h", pad) + tt.text + strings.Repeat(tt.text, 3)
+
+			var want, got []faststringmap.Match
+			sc.ScanString(text, func(mt faststringmap.Match) bool {
+				want = append(want, mt)
+				return true
+			})
+			err := sc.ScanReader(iotest.HalfReader(strings.NewReader(text)), func(mt faststringmap.Match) bool {
+				got = append(got, mt)
+				return true
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("policy %d: ScanReader of %q padded by %d found %d matches want %d", tt.policy, tt.text, pad, len(got), len(want))
+			}
+		}
+	}
+}
+
+func TestScanReaderErrors(t *testing.T) {
+	m := faststringmap.NewMap(scanKeys)
+	sc := faststringmap.NewScanner(&m, faststringmap.AllOverlapping)
+
+	errRead := errors.New("read failed")
+	if err := sc.ScanReader(iotest.ErrReader(errRead), func(faststringmap.Match) bool { return true }); !errors.Is(err, errRead) {
+		t.Errorf("ScanReader() error = %v want %v", err, errRead)
+	}
+
+	n := 0
+	err := sc.ScanReader(strings.NewReader(strings.Repeat("she ", 1<<16)), func(faststringmap.Match) bool {
+		n++
+		return n < 3
+	})
+	if err != nil || n != 3 {
+		t.Errorf("ScanReader() stopping early = %v after %d calls want nil after 3", err, n)
+	}
+}
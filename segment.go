@@ -0,0 +1,245 @@
+package faststringmap
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SegmentMap[T] is a fast read only map from string to generic type T, for
+// hierarchical keys such as paths or metric names. Instead of branching on
+// every byte like Map, it branches on whole segments delimited by a
+// separator byte, finding the child for a segment by its hash. A key of
+// hundreds of bytes with a handful of segments is found in a handful of
+// steps.
+//
+// A key has one more segment than separators, so "a/b" and "a/b/" are
+// different keys, and the empty key is a single empty segment.
+type SegmentMap[T any] struct {
+	sep    byte
+	nodes  []segmentNode // nodes[0] is the root
+	edges  []segmentEdge // children of each node, sorted by hash
+	labels string        // segments of all edges, concatenated
+	values []T
+}
+
+type segmentNode struct {
+	valueOffset Uint // index+1 in values for keys ending at the node. 0 if not valid
+	edgesLo     Uint // index in edges of the first child
+	edgesLen    Uint // number of children
+}
+
+type segmentEdge struct {
+	hash     uint32 // FNV-1a hash of the segment
+	labelLo  Uint   // offset of the segment in labels
+	labelLen Uint   // length of the segment
+	node     Uint   // index in nodes of the child
+}
+
+// NewSegmentMap[T] constructs a new SegmentMap from the provided map
+// entries, splitting keys into segments at every sep byte. It returns an
+// error wrapping ErrDuplicateKey if a key appears more than once. The
+// entries slice is not modified.
+func NewSegmentMap[T any](entries []MapEntry[T], sep byte) (SegmentMap[T], error) {
+	type buildNode struct {
+		entry    int // index in entries of the key ending at the node, or -1
+		children map[string]int
+	}
+
+	tree := []buildNode{{entry: -1}}
+	for i, e := range entries {
+		n := 0
+		for key, more := e.Key, true; more; {
+			var seg string
+			seg, key, more = cutByte(key, sep)
+			c, ok := tree[n].children[seg]
+			if !ok {
+				if tree[n].children == nil {
+					tree[n].children = map[string]int{}
+				}
+				c = len(tree)
+				tree[n].children[seg] = c
+				tree = append(tree, buildNode{entry: -1})
+			}
+			n = c
+		}
+		if tree[n].entry >= 0 {
+			return SegmentMap[T]{}, fmt.Errorf("%w %q", ErrDuplicateKey, e.Key)
+		}
+		tree[n].entry = i
+	}
+
+	sm := SegmentMap[T]{sep: sep, nodes: make([]segmentNode, len(tree))}
+	var labels []byte
+	var segs []string
+	for i, bn := range tree {
+		node := &sm.nodes[i]
+		if bn.entry >= 0 {
+			sm.values = append(sm.values, entries[bn.entry].Value)
+			node.valueOffset = Uint(len(sm.values))
+		}
+
+		segs = segs[:0]
+		for seg := range bn.children {
+			segs = append(segs, seg)
+		}
+		sort.Strings(segs) // for a reproducible layout
+
+		node.edgesLo = Uint(len(sm.edges))
+		node.edgesLen = Uint(len(segs))
+		for _, seg := range segs {
+			sm.edges = append(sm.edges, segmentEdge{
+				hash:     segmentHash(seg),
+				labelLo:  Uint(len(labels)),
+				labelLen: Uint(len(seg)),
+				node:     Uint(bn.children[seg]),
+			})
+			labels = append(labels, seg...)
+		}
+		edges := sm.edges[node.edgesLo:]
+		sort.SliceStable(edges, func(a, b int) bool { return edges[a].hash < edges[b].hash })
+	}
+	sm.labels = string(labels)
+
+	return sm, nil
+}
+
+// countSegments returns the number of nodes, and the total length of the
+// labels of the edges, of a SegmentMap of the sorted, distinct entries in
+// b.order, split at every sep byte, without building it.
+func (b *Builder[T]) countSegments(sep byte) (nodes, labelBytes int) {
+	nodes = 1
+	prev := ""
+	for i, ei := range b.order {
+		key := b.key(ei)
+		shared := -1 // keys sorted before key share no bytes
+		if i > 0 {
+			shared = commonPrefixLen(prev, key)
+		}
+
+		start := 0
+		for j := 0; j <= len(key); j++ {
+			if j < len(key) && key[j] != sep {
+				continue
+			}
+			// key[:j] is the path of a node, made by a previous key if that
+			// continues it with sep, or is equal to it
+			seen := j < len(key) && (shared > j || b.hasKey(key[:j], i))
+			if !seen {
+				nodes++
+				labelBytes += j - start
+			}
+			start = j + 1
+		}
+		prev = key
+	}
+	return nodes, labelBytes
+}
+
+// hasKey reports whether key is among the first n sorted, distinct entries
+// in b.order.
+func (b *Builder[T]) hasKey(key string, n int) bool {
+	i := sort.Search(n, func(i int) bool { return b.key(b.order[i]) >= key })
+	return i < n && b.key(b.order[i]) == key
+}
+
+// LookupString looks up the supplied string in the map.
+func (sm *SegmentMap[T]) LookupString(s string) (t T, ok bool) {
+	return sm.atIndex(segmentIndex(sm, s))
+}
+
+// LookupBytes looks up the supplied byte slice in the map. It never
+// allocates and does not retain s.
+func (sm *SegmentMap[T]) LookupBytes(s []byte) (t T, ok bool) {
+	return sm.atIndex(segmentIndex(sm, s))
+}
+
+// Len returns the number of keys in the map.
+func (sm *SegmentMap[T]) Len() int {
+	return len(sm.values)
+}
+
+func (sm *SegmentMap[T]) atIndex(index Uint) (t T, ok bool) {
+	if index == 0 {
+		return t, false
+	}
+	return sm.values[index-1], true
+}
+
+func segmentIndex[T any, S string | []byte](sm *SegmentMap[T], s S) Uint {
+	if len(sm.nodes) == 0 {
+		return 0
+	}
+
+	node := &sm.nodes[0]
+	h := uint32(fnvOffsetBasis) // hash of the current segment, computed on the way
+	start := 0
+	for i, n := 0, len(s); i <= n; i++ {
+		if i < n && s[i] != sm.sep {
+			h = (h ^ uint32(s[i])) * 16777619
+			continue
+		}
+
+		node = segmentChild(sm, node, h, s[start:i])
+		if node == nil {
+			return 0
+		}
+		h = fnvOffsetBasis
+		start = i + 1
+	}
+
+	return node.valueOffset
+}
+
+// segmentChild returns the child of node for the segment seg with hash h,
+// or nil if there is none.
+func segmentChild[T any, S string | []byte](sm *SegmentMap[T], node *segmentNode, h uint32, seg S) *segmentNode {
+	edges := sm.edges[node.edgesLo : node.edgesLo+node.edgesLen]
+
+	// binary search for the first edge with hash h
+	lo, hi := 0, len(edges)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if edges[mid].hash < h {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	for ; lo < len(edges) && edges[lo].hash == h; lo++ {
+		e := &edges[lo]
+		if int(e.labelLen) == len(seg) && equalSegment(sm.labels[e.labelLo:e.labelLo+e.labelLen], seg) {
+			return &sm.nodes[e.node]
+		}
+	}
+	return nil
+}
+
+func equalSegment[S string | []byte](label string, seg S) bool {
+	for i := 0; i < len(label); i++ {
+		if label[i] != seg[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentHash returns the 32-bit FNV-1a hash of a segment.
+func segmentHash(seg string) uint32 {
+	h := uint32(fnvOffsetBasis)
+	for i := 0; i < len(seg); i++ {
+		h = (h ^ uint32(seg[i])) * 16777619
+	}
+	return h
+}
+
+// cutByte slices s around the first instance of sep, returning the text
+// before and after it. more is false if sep does not appear in s.
+func cutByte(s string, sep byte) (before, after string, more bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == sep {
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
@@ -0,0 +1,102 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestSegmentMap(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{
+		{"usr/local/bin", 1},
+		{"usr/local", 2},
+		{"usr/lib", 3},
+		{"usr/local/bin/", 4},
+		{"", 5},
+		{"etc//hosts", 6},
+	}
+	sm, err := faststringmap.NewSegmentMap(entries, '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sm.Len() != len(entries) {
+		t.Errorf("Len() = %d want %d", sm.Len(), len(entries))
+	}
+
+	for _, e := range entries {
+		if v, ok := sm.LookupString(e.Key); !ok || v != e.Value {
+			t.Errorf("LookupString(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+		if v, ok := sm.LookupBytes([]byte(e.Key)); !ok || v != e.Value {
+			t.Errorf("LookupBytes(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+	}
+	for _, k := range []string{"usr", "usr/", "usr/local/bi", "usr/local/bin//", "etc/hosts", "/", "usr/lib/x"} {
+		if v, ok := sm.LookupString(k); ok {
+			t.Errorf("LookupString(%q) = %v, true want false", k, v)
+		}
+	}
+
+	bs := []byte("usr/local/bin")
+	if allocs := testing.AllocsPerRun(100, func() { sm.LookupBytes(bs) }); allocs != 0 {
+		t.Errorf("LookupBytes allocates %v times", allocs)
+	}
+
+	var empty faststringmap.SegmentMap[int]
+	if _, ok := empty.LookupString(""); ok {
+		t.Error("zero SegmentMap found the empty key")
+	}
+
+	if _, err := faststringmap.NewSegmentMap(append(entries, entries[0]), '/'); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("NewSegmentMap() of duplicate keys error = %v want ErrDuplicateKey", err)
+	}
+}
+
+// metricNames returns n distinct dot separated names of deep hierarchies
+// with long segments, like metric names.
+func metricNames(n int) []faststringmap.MapEntry[int] {
+	entries := make([]faststringmap.MapEntry[int], n)
+	for i := range entries {
+		entries[i] = faststringmap.MapEntry[int]{
+			Key: strings.Join([]string{
+				"production-cluster-" + fmt.Sprint(i%7),
+				"application-service-" + fmt.Sprint(i%31),
+				"http-server-requests-" + fmt.Sprint(i%13),
+				"latency-milliseconds-histogram-bucket-" + fmt.Sprint(i),
+			}, "."),
+			Value: i,
+		}
+	}
+	return entries
+}
+
+func BenchmarkSegmentMap(b *testing.B) {
+	entries := metricNames(1000)
+	m := faststringmap.NewMap(entries)
+	sm, err := faststringmap.NewSegmentMap(entries, '.')
+	if err != nil {
+		b.Fatal(err)
+	}
+	keys := make([][]byte, len(entries))
+	for i, e := range entries {
+		keys[i] = []byte(e.Key)
+	}
+
+	b.Run("Map", func(b *testing.B) {
+		for bi := 0; bi < b.N; bi++ {
+			for _, k := range keys {
+				m.LookupBytes(k)
+			}
+		}
+	})
+	b.Run("SegmentMap", func(b *testing.B) {
+		for bi := 0; bi < b.N; bi++ {
+			for _, k := range keys {
+				sm.LookupBytes(k)
+			}
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package faststringmap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSelfTest is wrapped by the errors of SelfTest and Map.SelfTestFunc.
+var ErrSelfTest = errors.New("faststringmap: self test failed")
+
+// SelfTest looks up the keys of probes, known good entries, in m and
+// reports an error wrapping ErrSelfTest for every key that is missing or has
+// another value. It is meant for health checks and deploy-time validation:
+// canary entries checked after a map is loaded, together with its Metadata,
+// show that the intended artifact was loaded intact. See Map.SelfTestFunc
+// for values that are not comparable.
+func SelfTest[T comparable](m *Map[T], probes []MapEntry[T]) error {
+	return m.SelfTestFunc(probes, func(a, b T) bool { return a == b })
+}
+
+// SelfTestFunc is like SelfTest, comparing values with equal.
+func (m *Map[T]) SelfTestFunc(probes []MapEntry[T], equal func(a, b T) bool) error {
+	var errs []error
+	for _, p := range probes {
+		v, ok := m.LookupString(p.Key)
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("%w: key %q is missing", ErrSelfTest, p.Key))
+		case !equal(v, p.Value):
+			errs = append(errs, fmt.Errorf("%w: key %q has value %v want %v", ErrSelfTest, p.Key, v, p.Value))
+		}
+	}
+	return errors.Join(errs...)
+}
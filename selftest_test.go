@@ -0,0 +1,41 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestSelfTest(t *testing.T) {
+	m := faststringmap.NewMap(goldenEntries)
+
+	if err := faststringmap.SelfTest(&m, goldenEntries[:3]); err != nil {
+		t.Errorf("SelfTest() of known entries error = %v", err)
+	}
+	if err := faststringmap.SelfTest(&m, nil); err != nil {
+		t.Errorf("SelfTest() without probes error = %v", err)
+	}
+
+	err := faststringmap.SelfTest(&m, []faststringmap.MapEntry[uint32]{{"GET", 1}, {"HEAD", 9}, {"TRACE", 8}})
+	if !errors.Is(err, faststringmap.ErrSelfTest) {
+		t.Fatalf("SelfTest() error = %v want ErrSelfTest", err)
+	}
+	for _, want := range []string{`"HEAD" has value 2 want 9`, `"TRACE" is missing`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("SelfTest() error = %q does not contain %q", err, want)
+		}
+	}
+
+	var nilMap *faststringmap.Map[uint32]
+	if err := faststringmap.SelfTest(nilMap, goldenEntries[:1]); !errors.Is(err, faststringmap.ErrSelfTest) {
+		t.Errorf("SelfTest() of nil map error = %v want ErrSelfTest", err)
+	}
+
+	sm := faststringmap.NewMap([]faststringmap.MapEntry[[]string]{{"colors", []string{"red", "green"}}})
+	equal := func(a, b []string) bool { return strings.Join(a, ",") == strings.Join(b, ",") }
+	if err := sm.SelfTestFunc([]faststringmap.MapEntry[[]string]{{"colors", []string{"red", "green"}}}, equal); err != nil {
+		t.Errorf("SelfTestFunc() error = %v", err)
+	}
+}
@@ -0,0 +1,319 @@
+package faststringmap
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// A serialized map consists of a fixed size header, followed by the node
+// store in the encoding documented in encoding.go, followed by the values.
+// All integers are little-endian. The current format version is 3:
+//
+//	offset  size   field
+//	0       4      magic "FSTM"
+//	4       2      format version
+//	6       2      flags: bit 0 is set if key fingerprints are present,
+//	               bit 1 if metadata is present, bit 2 if the map folds
+//	               ASCII case (see WithFold)
+//	8       4      number of nodes (n)
+//	12      4      number of values (v)
+//	16      4      CRC-32 (Castagnoli) of everything following the header
+//	20      4      reserved, always zero
+//	24      8      fingerprint salt, zero if there are no fingerprints
+//	32      12*n   node store
+//	...     v      key fingerprints in value order, if present
+//	...     8*v+8  value offsets: start of each value in the value data,
+//	               followed by the total length of the value data
+//	...            value data, each value encoded by a ValueCodec
+//	...            metadata, if present, as documented in metadata.go
+//
+// Version 2 has a 24 byte header without the flags and salt, and never holds
+// fingerprints. Version 1 additionally has a 16 byte header without the
+// checksum, and 4 byte value offsets. Both can still be read, and maps loaded
+// from them are migrated to the current version when serialized again.
+//
+// The indices of values, as returned by IndexString and taken by AtIndex,
+// are part of the format: the node store holds them, and values are stored
+// in their order. A map loaded by UnmarshalMap, UnmarshalMapLazy,
+// OpenReaderAt or OpenMapped therefore has the same indices as the map it
+// was serialized from, as does a map migrated from an older format version,
+// so indices can be persisted alongside the serialized map.
+//
+// Building a map from the same set of entries always produces the same node
+// store and value order, regardless of the order the entries were supplied
+// in, so serializing it with a deterministic ValueCodec always produces
+// byte-identical output. Maps with fingerprints are only reproducible if
+// their salt is fixed using Builder.SetFingerprintSalt.
+
+const (
+	serialMagic   = "FSTM"
+	serialVersion = 3
+
+	serialFlagFingerprints = 1 << 0
+	serialFlagFold         = 1 << 2
+)
+
+var serialChecksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// serialLayout holds the sections of a serialized map, for any format version.
+type serialLayout struct {
+	version      uint16
+	fold         bool
+	salt         uint64
+	fingerprints []byte // nil if not present
+	nodes        []byte
+	nValues      int
+	offsets      []byte
+	offsetSize   int
+	values       []byte
+	metadata     map[string]string // nil if not present
+}
+
+// AppendBinary appends the serialized form of the map to dst, using codec to
+// encode its values, and returns the extended slice.
+func (m *Map[T]) AppendBinary(dst []byte, codec ValueCodec[T]) ([]byte, error) {
+	store := []mapInternalNode{{}} // an empty map has just a root node
+	nValues := 0
+	if m != nil && len(m.store) > 0 {
+		store, nValues = m.store, m.len()
+	}
+
+	start := len(dst)
+	dst = append(dst, make([]byte, serialHeaderSize(serialVersion))...)
+	header := dst[start:]
+	copy(header[0:], serialMagic)
+	binary.LittleEndian.PutUint16(header[4:], serialVersion)
+	binary.LittleEndian.PutUint32(header[8:], uint32(len(store)))
+	binary.LittleEndian.PutUint32(header[12:], uint32(nValues))
+
+	nodesStart := len(dst)
+	dst = append(dst, make([]byte, len(store)*nodeSize)...)
+	encodeNodes(dst[nodesStart:], store)
+
+	var flags uint16
+	if m != nil && m.fold {
+		flags |= serialFlagFold
+	}
+	if m != nil && m.fingerprints != nil && len(m.fingerprints) == nValues {
+		flags |= serialFlagFingerprints
+		binary.LittleEndian.PutUint64(dst[start+24:], m.fingerprintSalt)
+		dst = append(dst, m.fingerprints...)
+	}
+
+	offsetsStart := len(dst)
+	dst = append(dst, make([]byte, 8*(nValues+1))...)
+	dataStart := len(dst)
+
+	for i := 0; i < nValues; i++ {
+		binary.LittleEndian.PutUint64(dst[offsetsStart+8*i:], uint64(len(dst)-dataStart))
+
+		v, ok := m.AtIndex(Uint(i + 1))
+		if !ok {
+			return dst[:start], ErrInvalidEncoding // lazily loaded value failed to decode
+		}
+
+		var err error
+		if dst, err = codec.AppendValue(dst, v); err != nil {
+			return dst[:start], err
+		}
+	}
+	binary.LittleEndian.PutUint64(dst[offsetsStart+8*nValues:], uint64(len(dst)-dataStart))
+
+	if m != nil && len(m.metadata) > 0 {
+		flags |= serialFlagMetadata
+		dst = appendMetadata(dst, m.metadata)
+	}
+	binary.LittleEndian.PutUint16(dst[start+6:], flags)
+
+	checksum := crc32.Checksum(dst[nodesStart:], serialChecksumTable)
+	binary.LittleEndian.PutUint32(dst[start+16:], checksum)
+	return dst, nil
+}
+
+// UnmarshalMap[T] constructs a Map from its serialized form, using codec to
+// decode its values. Data in any supported format version is accepted. The
+// data is validated, so lookups in the resulting Map never panic. Where the
+// platform allows it, the Map uses the node store in data directly instead of
+// copying it, in which case data must not be modified while the Map is in use.
+func UnmarshalMap[T any](data []byte, codec ValueCodec[T]) (Map[T], error) {
+	layout, err := parseSerialized(data, true)
+	if err != nil {
+		return Map[T]{}, err
+	}
+
+	values := make([]T, layout.nValues)
+	for i := range values {
+		src, err := layout.value(i)
+		if err != nil {
+			return Map[T]{}, err
+		}
+		if values[i], err = codec.DecodeValue(src); err != nil {
+			return Map[T]{}, err
+		}
+	}
+
+	m, err := FromEncodedNodes(layout.nodes, values)
+	if err != nil {
+		return Map[T]{}, err
+	}
+
+	m.formatVersion = layout.version
+	m.fold = layout.fold
+	m.metadata = layout.metadata
+	m.setFingerprints(layout.fingerprints, layout.salt)
+	return m, nil
+}
+
+// FormatVersion returns the serialization format version the map was loaded
+// from, or 0 if the map was not loaded from serialized data.
+func (m *Map[T]) FormatVersion() int {
+	if m == nil {
+		return 0
+	}
+	return int(m.formatVersion)
+}
+
+func serialHeaderSize(version uint16) int {
+	switch version {
+	case 1:
+		return 16
+	case 2:
+		return 24
+	}
+	return 32
+}
+
+// serialHeader holds the fields of the header of a serialized map.
+type serialHeader struct {
+	version    uint16
+	size       uint64 // size of the header itself
+	nNodes     uint64
+	nValues    uint64
+	offsetSize uint64
+	checksum   uint32 // only present from version 2
+	flags      uint16 // only present from version 3
+	salt       uint64 // only present from version 3
+}
+
+// parseSerialHeader parses the header at the start of data.
+func parseSerialHeader(data []byte) (serialHeader, error) {
+	if len(data) < 16 || string(data[:4]) != serialMagic {
+		return serialHeader{}, ErrInvalidEncoding
+	}
+
+	h := serialHeader{version: binary.LittleEndian.Uint16(data[4:])}
+	switch h.version {
+	case 1:
+		h.offsetSize = 4
+	case 2, 3:
+		h.offsetSize = 8
+	default:
+		return serialHeader{}, ErrUnsupportedVersion
+	}
+
+	h.size = uint64(serialHeaderSize(h.version))
+	if uint64(len(data)) < h.size {
+		return serialHeader{}, ErrInvalidEncoding
+	}
+	h.nNodes = uint64(binary.LittleEndian.Uint32(data[8:]))
+	h.nValues = uint64(binary.LittleEndian.Uint32(data[12:]))
+	if h.version >= 2 {
+		h.checksum = binary.LittleEndian.Uint32(data[16:])
+	}
+	if h.version >= 3 {
+		h.flags = binary.LittleEndian.Uint16(data[6:])
+		h.salt = binary.LittleEndian.Uint64(data[24:])
+	}
+
+	if h.nNodes == 0 || h.flags&^(serialFlagFingerprints|serialFlagMetadata|serialFlagFold) != 0 {
+		return serialHeader{}, ErrInvalidEncoding
+	}
+	return h, nil
+}
+
+func (h *serialHeader) fingerprintsStart() uint64 {
+	return h.size + h.nNodes*nodeSize
+}
+
+func (h *serialHeader) offsetsStart() uint64 {
+	if h.flags&serialFlagFingerprints != 0 {
+		return h.fingerprintsStart() + h.nValues
+	}
+	return h.fingerprintsStart()
+}
+
+func (h *serialHeader) valuesStart() uint64 {
+	return h.offsetsStart() + h.offsetSize*(h.nValues+1)
+}
+
+// parseSerialized splits serialized data into its sections, checking that
+// they are consistent with each other, and if verify is set, with the
+// checksum if present.
+func parseSerialized(data []byte, verify bool) (serialLayout, error) {
+	h, err := parseSerialHeader(data)
+	if err != nil {
+		return serialLayout{}, err
+	}
+
+	offsetsStart, valuesStart := h.offsetsStart(), h.valuesStart()
+	if valuesStart > uint64(len(data)) {
+		return serialLayout{}, ErrInvalidEncoding
+	}
+
+	if verify && h.version >= 2 && crc32.Checksum(data[h.size:], serialChecksumTable) != h.checksum {
+		return serialLayout{}, ErrInvalidEncoding
+	}
+
+	l := serialLayout{
+		version:    h.version,
+		fold:       h.flags&serialFlagFold != 0,
+		salt:       h.salt,
+		nodes:      data[h.size:h.fingerprintsStart()],
+		nValues:    int(h.nValues),
+		offsets:    data[offsetsStart:valuesStart],
+		offsetSize: int(h.offsetSize),
+		values:     data[valuesStart:],
+	}
+	if h.flags&serialFlagFingerprints != 0 {
+		l.fingerprints = data[h.fingerprintsStart():offsetsStart]
+	}
+	end := l.offset(l.nValues)
+	if h.flags&serialFlagMetadata != 0 && end <= uint64(len(l.values)) {
+		// the metadata follows the value data
+		if l.metadata, err = decodeMetadata(l.values[end:]); err != nil {
+			return serialLayout{}, err
+		}
+		l.values = l.values[:end]
+	}
+	if end != uint64(len(l.values)) {
+		return serialLayout{}, ErrInvalidEncoding
+	}
+
+	return l, nil
+}
+
+func (l *serialLayout) offset(i int) uint64 {
+	return decodeOffset(l.offsets[l.offsetSize*i:], l.offsetSize)
+}
+
+// decodeOffset decodes a value offset of the given size from the start of b.
+func decodeOffset(b []byte, size int) uint64 {
+	if size == 4 {
+		return uint64(binary.LittleEndian.Uint32(b))
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+// value returns the encoded bytes of the value at index i.
+func (l *serialLayout) value(i int) ([]byte, error) {
+	lo, hi := l.offset(i), l.offset(i+1)
+	if lo > hi || hi > uint64(len(l.values)) {
+		return nil, ErrInvalidEncoding
+	}
+	return l.values[lo:hi], nil
+}
+
+// len returns the number of values.
+func (l *serialLayout) len() int {
+	return l.nValues
+}
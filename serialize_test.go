@@ -0,0 +1,317 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// goldenEntries are the entries of the map serialized in the golden files.
+var goldenEntries = []faststringmap.MapEntry[uint32]{
+	{"", 0},
+	{"GET", 1},
+	{"HEAD", 2},
+	{"POST", 3},
+	{"PUT", 4},
+	{"PATCH", 5},
+	{"DELETE", 6},
+	{"OPTIONS", 7},
+	{"ß", 300},
+}
+
+func TestSerializeRoundTrip(t *testing.T) {
+	entries := randomSmallStrings(2048, 8)
+	m := faststringmap.NewMap(entries)
+
+	data, err := m.AppendBinary(nil, faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatalf("AppendBinary() error = %v", err)
+	}
+
+	loaded, err := faststringmap.UnmarshalMap[uint32](data, faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatalf("UnmarshalMap() error = %v", err)
+	}
+	checkEntries(t, &loaded, entries)
+}
+
+func TestSerializeCodecs(t *testing.T) {
+	type point struct{ X, Y int }
+	testCodecRoundTrip[int8](t, faststringmap.IntCodec[int8]{}, []int8{-128, -1, 0, 1, 127})
+	testCodecRoundTrip[uint64](t, faststringmap.IntCodec[uint64]{}, []uint64{0, 1, 1 << 63})
+	testCodecRoundTrip[string](t, faststringmap.StringCodec{}, []string{"", "x", "hello world"})
+	testCodecRoundTrip[point](t, faststringmap.JSONCodec[point]{}, []point{{}, {1, -2}})
+}
+
+func testCodecRoundTrip[T comparable](t *testing.T, codec faststringmap.ValueCodec[T], values []T) {
+	t.Helper()
+	keys := []string{"a", "b", "c", "d", "e"}
+	entries := make([]faststringmap.MapEntry[T], len(values))
+	for i, v := range values {
+		entries[i] = faststringmap.MapEntry[T]{keys[i], v}
+	}
+
+	m := faststringmap.NewMap(entries)
+	data, err := m.AppendBinary(nil, codec)
+	if err != nil {
+		t.Fatalf("AppendBinary() error = %v", err)
+	}
+	loaded, err := faststringmap.UnmarshalMap[T](data, codec)
+	if err != nil {
+		t.Fatalf("UnmarshalMap() error = %v", err)
+	}
+	checkEntries(t, &loaded, entries)
+}
+
+func TestSerializeEmptyMap(t *testing.T) {
+	for _, m := range []*faststringmap.Map[uint32]{nil, {}} {
+		data, err := m.AppendBinary(nil, faststringmap.IntCodec[uint32]{})
+		if err != nil {
+			t.Fatalf("AppendBinary() error = %v", err)
+		}
+		loaded, err := faststringmap.UnmarshalMap[uint32](data, faststringmap.IntCodec[uint32]{})
+		if err != nil {
+			t.Fatalf("UnmarshalMap() error = %v", err)
+		}
+		if v, ok := loaded.LookupString(""); ok {
+			t.Errorf("LookupString(\"\") = %v, expected not to be present", v)
+		}
+	}
+}
+
+func TestSerializeDeterministic(t *testing.T) {
+	entries := randomSmallStrings(2048, 8)
+	want := serialize(t, faststringmap.NewMap(entries))
+
+	for i := 0; i < 4; i++ {
+		rand.Shuffle(len(entries), func(i, j int) { entries[i], entries[j] = entries[j], entries[i] })
+		if got := serialize(t, faststringmap.NewMap(entries)); !bytes.Equal(got, want) {
+			t.Fatalf("serialized map differs after shuffling the entries")
+		}
+	}
+}
+
+func TestSerializeGolden(t *testing.T) {
+	got := serialize(t, faststringmap.NewMap(goldenEntries))
+
+	path := filepath.Join("testdata", "methods_v3.fstm")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("serialized map differs from %s, run with -update if this is intended", path)
+	}
+}
+
+func TestUnmarshalMapMigrates(t *testing.T) {
+	for _, version := range []int{1, 2} {
+		data, err := os.ReadFile(filepath.Join("testdata", fmt.Sprintf("methods_v%d.fstm", version)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := faststringmap.UnmarshalMap[uint32](data, faststringmap.IntCodec[uint32]{})
+		if err != nil {
+			t.Fatalf("UnmarshalMap(v%d) error = %v", version, err)
+		}
+		if v := m.FormatVersion(); v != version {
+			t.Errorf("FormatVersion() = %d want %d", v, version)
+		}
+		checkEntries(t, &m, goldenEntries)
+
+		migrated := serialize(t, m)
+		if want := serialize(t, faststringmap.NewMap(goldenEntries)); !bytes.Equal(migrated, want) {
+			t.Errorf("re-serialized v%d map differs from a freshly serialized map", version)
+		}
+
+		m, err = faststringmap.UnmarshalMap[uint32](migrated, faststringmap.IntCodec[uint32]{})
+		if err != nil || m.FormatVersion() != 3 {
+			t.Errorf("UnmarshalMap(migrated v%d) = version %d, %v want 3, nil", version, m.FormatVersion(), err)
+		}
+	}
+}
+
+func TestSerializeFingerprints(t *testing.T) {
+	build := func(salt uint64, fixed bool) faststringmap.Map[uint32] {
+		var b faststringmap.Builder[uint32]
+		b.SetFingerprints(true)
+		if fixed {
+			b.SetFingerprintSalt(salt)
+		}
+		for _, e := range goldenEntries {
+			b.Add(e.Key, e.Value)
+		}
+		return b.Build()
+	}
+
+	m := build(0, false)
+	if m.FingerprintSalt() == 0 {
+		t.Errorf("FingerprintSalt() = 0 want a random salt")
+	}
+	if other := build(0, false); other.FingerprintSalt() == m.FingerprintSalt() {
+		t.Errorf("two builds chose the same salt %#x", m.FingerprintSalt())
+	}
+
+	data := serialize(t, m)
+	for name, unmarshal := range map[string]func([]byte, faststringmap.ValueCodec[uint32]) (faststringmap.Map[uint32], error){
+		"UnmarshalMap":     faststringmap.UnmarshalMap[uint32],
+		"UnmarshalMapLazy": faststringmap.UnmarshalMapLazy[uint32],
+		"OpenReaderAt": func(data []byte, codec faststringmap.ValueCodec[uint32]) (faststringmap.Map[uint32], error) {
+			return faststringmap.OpenReaderAt(bytes.NewReader(data), int64(len(data)), codec)
+		},
+	} {
+		loaded, err := unmarshal(data, faststringmap.IntCodec[uint32]{})
+		if err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+		if got := loaded.FingerprintSalt(); got != m.FingerprintSalt() {
+			t.Errorf("%s() salt = %#x want %#x", name, got, m.FingerprintSalt())
+		}
+		checkEntries(t, &loaded, goldenEntries)
+		if !bytes.Equal(serialize(t, loaded), data) {
+			t.Errorf("re-serialized map loaded by %s() differs from the original", name)
+		}
+	}
+
+	if !bytes.Equal(serialize(t, build(42, true)), serialize(t, build(42, true))) {
+		t.Errorf("maps built with the same fixed salt serialize differently")
+	}
+	if unsalted := build(0, true); unsalted.FingerprintSalt() != 0 {
+		t.Errorf("FingerprintSalt() = %#x want 0", unsalted.FingerprintSalt())
+	}
+
+	// corrupting the fingerprint of the first key "", which directly follows
+	// the node store, and fixing up the checksum, hides the key
+	nNodes := int(binary.LittleEndian.Uint32(data[8:]))
+	data[32+12*nNodes] ^= 0xff
+	binary.LittleEndian.PutUint32(data[16:], crc32.Checksum(data[32:], crc32.MakeTable(crc32.Castagnoli)))
+	loaded, err := faststringmap.UnmarshalMap[uint32](data, faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loaded.LookupString(""); ok {
+		t.Errorf("LookupString() of a key with a corrupted fingerprint reported present")
+	}
+}
+
+func TestUnmarshalMapInvalid(t *testing.T) {
+	data := serialize(t, faststringmap.NewMap(goldenEntries))
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"bad magic", append([]byte("XXXX"), data[4:]...)},
+		{"truncated", data[:len(data)-1]},
+		{"corrupted", append(append([]byte{}, data[:len(data)-1]...), data[len(data)-1]^1)},
+	} {
+		_, err := faststringmap.UnmarshalMap[uint32](tc.data, faststringmap.IntCodec[uint32]{})
+		if !errors.Is(err, faststringmap.ErrInvalidEncoding) {
+			t.Errorf("%s: UnmarshalMap() error = %v want ErrInvalidEncoding", tc.name, err)
+		}
+	}
+
+	future := append([]byte{}, data...)
+	future[4] = 99
+	_, err := faststringmap.UnmarshalMap[uint32](future, faststringmap.IntCodec[uint32]{})
+	if !errors.Is(err, faststringmap.ErrUnsupportedVersion) {
+		t.Errorf("UnmarshalMap(version 99) error = %v want ErrUnsupportedVersion", err)
+	}
+}
+
+func serialize(t *testing.T, m faststringmap.Map[uint32]) []byte {
+	t.Helper()
+	data, err := m.AppendBinary(nil, faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatalf("AppendBinary() error = %v", err)
+	}
+	return data
+}
+
+// Indices are persisted by users alongside serialized maps, so every way of
+// loading a map must give the indices of the map it was serialized from.
+func TestIndicesStableAcrossLoads(t *testing.T) {
+	entries := randomSmallStrings(2048, 8)
+	codec := faststringmap.IntCodec[uint32]{}
+	for optName, opts := range map[string][]faststringmap.Option{
+		"default":      nil,
+		"fingerprints": {faststringmap.WithFingerprints()},
+		"metadata":     {faststringmap.WithMetadata(map[string]string{"version": "1"})},
+	} {
+		m, err := faststringmap.New(entries, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := serialize(t, m)
+
+		path := filepath.Join(t.TempDir(), "map.fstm")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mapped, err := faststringmap.OpenMapped(path, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer mapped.Close()
+
+		loaded := map[string]*faststringmap.Map[uint32]{"OpenMapped": &mapped.Map}
+		for name, unmarshal := range map[string]func([]byte, faststringmap.ValueCodec[uint32]) (faststringmap.Map[uint32], error){
+			"UnmarshalMap":     faststringmap.UnmarshalMap[uint32],
+			"UnmarshalMapLazy": faststringmap.UnmarshalMapLazy[uint32],
+			"OpenReaderAt": func(data []byte, codec faststringmap.ValueCodec[uint32]) (faststringmap.Map[uint32], error) {
+				return faststringmap.OpenReaderAt(bytes.NewReader(data), int64(len(data)), codec)
+			},
+		} {
+			lm, err := unmarshal(data, codec)
+			if err != nil {
+				t.Fatalf("%s: %s() error = %v", optName, name, err)
+			}
+			loaded[name] = &lm
+		}
+
+		for name, lm := range loaded {
+			for _, e := range entries {
+				if got, want := lm.IndexString(e.Key), m.IndexString(e.Key); got != want {
+					t.Fatalf("%s: %s: IndexString(%q) = %d want %d", optName, name, e.Key, got, want)
+				}
+			}
+		}
+	}
+
+	// maps migrated from older format versions keep their indices too
+	want := faststringmap.NewMap(goldenEntries)
+	for _, version := range []int{1, 2, 3} {
+		data, err := os.ReadFile(filepath.Join("testdata", fmt.Sprintf("methods_v%d.fstm", version)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := faststringmap.UnmarshalMap(data, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range goldenEntries {
+			if got, want := m.IndexString(e.Key), want.IndexString(e.Key); got != want {
+				t.Errorf("v%d: IndexString(%q) = %d want %d", version, e.Key, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package faststringmap
+
+// ShardRouter[S] routes keys to shards, such as the backends of a routing
+// layer. Keys starting with a pinned prefix go to the shard of the longest
+// such prefix, and all other keys to the shard a fallback function picks
+// from the hash of the key, typically by consistent hashing with JumpHash,
+// or by splitting the range of hashes among shards. A ShardRouter is read
+// only, and safe for concurrent use.
+type ShardRouter[S any] struct {
+	pins     Map[S]
+	fallback func(hash uint64) S
+}
+
+// NewShardRouter[S] returns a ShardRouter routing keys starting with the
+// prefixes of pins to their shards, and other keys to fallback(KeyHash(key)).
+// The empty prefix pins all keys not matching a longer prefix, so fallback
+// is never called.
+func NewShardRouter[S any](pins map[string]S, fallback func(hash uint64) S) ShardRouter[S] {
+	return ShardRouter[S]{pins: FromMap(pins), fallback: fallback}
+}
+
+// ShardString returns the shard of the supplied string, and whether it was
+// pinned by a prefix.
+func (r *ShardRouter[S]) ShardString(s string) (shard S, pinned bool) {
+	return routeShard(r, s)
+}
+
+// ShardBytes returns the shard of the supplied byte slice, like
+// ShardString. It does not allocate.
+func (r *ShardRouter[S]) ShardBytes(s []byte) (shard S, pinned bool) {
+	return routeShard(r, s)
+}
+
+func routeShard[S any, K string | []byte](r *ShardRouter[S], s K) (shard S, pinned bool) {
+	if index, _ := longestPrefix(&r.pins, s); index != 0 {
+		return r.pins.AtIndex(index)
+	}
+	return r.fallback(KeyHash(s)), false
+}
+
+// KeyHash returns the 64-bit FNV-1a hash of key, which ShardRouter passes to
+// its fallback function. It is stable across processes and releases, so
+// that every router instance agrees on the shard of a key.
+func KeyHash[K string | []byte](key K) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		h = (h ^ uint64(key[i])) * 1099511628211
+	}
+	return h
+}
+
+// JumpHash returns the shard in [0, shards) of a key with the supplied hash,
+// using the jump consistent hash of Lamping and Veach. When the number of
+// shards grows from n to n+1, only the keys moving to the new shard change
+// shards. shards must be positive.
+func JumpHash(hash uint64, shards int) int {
+	b, j := int64(-1), int64(0)
+	for j < int64(shards) {
+		b = j
+		hash = hash*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((hash>>33)+1)))
+	}
+	return int(b)
+}
@@ -0,0 +1,86 @@
+package faststringmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestShardRouter(t *testing.T) {
+	fallback := func(hash uint64) int { return 100 + faststringmap.JumpHash(hash, 8) }
+	r := faststringmap.NewShardRouter(map[string]int{"tenant/acme/": 1, "tenant/acme/eu/": 2, "admin": 3}, fallback)
+
+	for _, tc := range []struct {
+		key    string
+		want   int
+		pinned bool
+	}{
+		{"tenant/acme/users", 1, true},
+		{"tenant/acme/eu/users", 2, true},
+		{"tenant/acme/e", 1, true},
+		{"admin", 3, true},
+		{"administrators", 3, true},
+		{"tenant/other/users", fallback(faststringmap.KeyHash("tenant/other/users")), false},
+		{"", fallback(faststringmap.KeyHash("")), false},
+	} {
+		if got, pinned := r.ShardString(tc.key); got != tc.want || pinned != tc.pinned {
+			t.Errorf("ShardString(%q) = %v, %v want %v, %v", tc.key, got, pinned, tc.want, tc.pinned)
+		}
+		if got, pinned := r.ShardBytes([]byte(tc.key)); got != tc.want || pinned != tc.pinned {
+			t.Errorf("ShardBytes(%q) = %v, %v want %v, %v", tc.key, got, pinned, tc.want, tc.pinned)
+		}
+	}
+
+	catchAll := faststringmap.NewShardRouter(map[string]int{"": 7}, func(uint64) int { panic("fallback called") })
+	if got, pinned := catchAll.ShardString("anything"); got != 7 || !pinned {
+		t.Errorf("ShardString(anything) with an empty pin = %v, %v want 7, true", got, pinned)
+	}
+
+	key := []byte("tenant/other/users")
+	if allocs := testing.AllocsPerRun(100, func() { r.ShardBytes(key) }); allocs != 0 {
+		t.Errorf("ShardBytes() allocs = %v want 0", allocs)
+	}
+}
+
+func TestKeyHash(t *testing.T) {
+	// FNV-1a test vectors
+	for key, want := range map[string]uint64{"": 0xcbf29ce484222325, "a": 0xaf63dc4c8601ec8c, "foobar": 0x85944171f73967e8} {
+		if got := faststringmap.KeyHash(key); got != want {
+			t.Errorf("KeyHash(%q) = %#x want %#x", key, got, want)
+		}
+	}
+}
+
+func TestJumpHash(t *testing.T) {
+	const keys = 10000
+	counts := make([]int, 10)
+	moved := 0
+	for i := 0; i < keys; i++ {
+		h := faststringmap.KeyHash(fmt.Sprint("key", i))
+		s := faststringmap.JumpHash(h, 10)
+		if s < 0 || s >= 10 {
+			t.Fatalf("JumpHash(%#x, 10) = %d out of range", h, s)
+		}
+		counts[s]++
+
+		// growing to 11 shards only moves keys to the new shard
+		if s11 := faststringmap.JumpHash(h, 11); s11 != s {
+			if s11 != 10 {
+				t.Errorf("JumpHash(%#x, 11) = %d, moved from %d to an old shard", h, s11, s)
+			}
+			moved++
+		}
+	}
+	for s, n := range counts {
+		if n < keys/10*8/10 || n > keys/10*12/10 {
+			t.Errorf("shard %d holds %d of %d keys", s, n, keys)
+		}
+	}
+	if moved < keys/11*8/10 || moved > keys/11*12/10 {
+		t.Errorf("%d of %d keys moved to an 11th shard", moved, keys)
+	}
+	if got := faststringmap.JumpHash(12345, 1); got != 0 {
+		t.Errorf("JumpHash(12345, 1) = %d want 0", got)
+	}
+}
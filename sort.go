@@ -0,0 +1,111 @@
+package faststringmap
+
+// radixInsertionThreshold is the size below which ranges are sorted using
+// insertion sort instead of another radix pass.
+const radixInsertionThreshold = 32
+
+// sortEntries appends the indices of the supplied entries to order, sorted by
+// ascending key, and returns the extended slice. It uses an MSD radix sort over
+// the key bytes, and only moves indices around, so neither the entries nor
+// their values are copied. buf is used as scratch space, and must have a
+// capacity of at least len(entries).
+func sortEntries[T any](entries []MapEntry[T], order, buf []Uint) []Uint {
+	for i := range entries {
+		order = append(order, Uint(i))
+	}
+
+	s := radixSorter[T]{entries: entries, buf: buf[:len(entries)]}
+	s.sort(order)
+	return order
+}
+
+type radixSorter[T any] struct {
+	entries []MapEntry[T]
+	buf     []Uint // scratch space for distributing indices into buckets
+}
+
+// bucket returns the radix bucket of the key at the supplied depth. Keys with
+// no more bytes go to bucket 0, so they sort before all their extensions.
+func (s *radixSorter[T]) bucket(i Uint, depth int) int {
+	key := s.entries[i].Key
+	if depth < len(key) {
+		return int(key[depth]) + 1
+	}
+	return 0
+}
+
+// radixRange is a range of order still to be sorted, whose keys all share
+// the same first depth bytes. It is the state a recursive sort would keep on
+// the goroutine stack.
+type radixRange struct {
+	lo, hi int
+	depth  int
+}
+
+// sort sorts order by key. It sorts the buckets of each radix pass with an
+// explicit stack of ranges instead of recursing, so the bucket counts are
+// not kept once per branching level of the keys.
+func (s *radixSorter[T]) sort(order []Uint) {
+	var (
+		local  [64]radixRange // kept on the goroutine stack until it overflows
+		counts [257]int
+		starts [258]int
+	)
+	stack := append(local[:0], radixRange{hi: len(order)})
+
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		part := order[r.lo:r.hi]
+
+		if len(part) < radixInsertionThreshold {
+			s.insertionSort(part, r.depth)
+			continue
+		}
+
+		counts = [257]int{}
+		for _, i := range part {
+			counts[s.bucket(i, r.depth)]++
+		}
+
+		// if all keys continue with the same byte, skip straight to the next one
+		if counts[0] == 0 && counts[s.bucket(part[0], r.depth)] == len(part) {
+			r.depth++
+			stack = append(stack, r)
+			continue
+		}
+
+		for b, c := range counts {
+			starts[b+1] = starts[b] + c
+		}
+
+		next := starts
+		buf := s.buf[:len(part)]
+		for _, i := range part {
+			b := s.bucket(i, r.depth)
+			buf[next[b]] = i
+			next[b]++
+		}
+		copy(part, buf)
+
+		// bucket 0 holds keys that ended, which are all equal. Buckets are
+		// pushed in descending order so they are sorted in ascending order.
+		for b := len(counts) - 1; b >= 1; b-- {
+			if counts[b] > 1 {
+				stack = append(stack, radixRange{r.lo + starts[b], r.lo + starts[b+1], r.depth + 1})
+			}
+		}
+	}
+}
+
+func (s *radixSorter[T]) insertionSort(order []Uint, depth int) {
+	for i := 1; i < len(order); i++ {
+		for j := i; j > 0 && s.less(order[j], order[j-1], depth); j-- {
+			order[j], order[j-1] = order[j-1], order[j]
+		}
+	}
+}
+
+func (s *radixSorter[T]) less(i, j Uint, depth int) bool {
+	return s.entries[i].Key[depth:] < s.entries[j].Key[depth:]
+}
@@ -0,0 +1,217 @@
+package faststringmap
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+	"math"
+	"strings"
+	"unsafe"
+)
+
+// Lookuper[T] is the lookup interface shared by *Map[T] and *SortedMap[T],
+// so that code can switch between them per dataset. Both number values in
+// ascending key order starting at 1, so indices of the same entries agree.
+//
+// LookupKey and LookupKeyBytes need the keys of the map. A SortedMap always
+// has them, but a Map only if it was built with WithRetainKeys, and
+// otherwise they report every key as not present, even where LookupString
+// finds it. Code that may be handed a Map should use LookupString, or build
+// its maps with WithRetainKeys.
+type Lookuper[T any] interface {
+	IndexString(s string) Uint
+	IndexBytes(s []byte) Uint
+	AtIndex(index Uint) (t T, ok bool)
+	LookupString(s string) (t T, ok bool)
+	LookupBytes(s []byte) (t T, ok bool)
+	LookupKey(s string) (key string, t T, ok bool)
+	LookupKeyBytes(s []byte) (key string, t T, ok bool)
+	All() iter.Seq2[string, T]
+}
+
+var (
+	_ Lookuper[int] = (*Map[int])(nil)
+	_ Lookuper[int] = (*SortedMap[int])(nil)
+)
+
+// SortedMap[T] is a read only map from string to generic type T, which
+// keeps its keys sorted in a single string and finds them by binary
+// search. Its memory is predictable: the bytes of the keys, 4 bytes per
+// key, and the values, with none of the nodes of a trie, in exchange for
+// lookups costing O(log n) key comparisons instead of O(len(key)) steps.
+// It implements Lookuper like Map, and always retains its keys.
+type SortedMap[T any] struct {
+	keys   string // all keys, concatenated in ascending order
+	ends   []Uint // offset in keys of the end of each key
+	values []T
+
+	keyTransform func([]byte) []byte
+	fold         bool
+}
+
+var errKeysTooLong = errors.New("faststringmap: keys too long for a SortedMap")
+
+// NewSortedMap[T] constructs a new SortedMap from the provided map entries,
+// configured by opts like New. Fingerprints are never stored, and keys are
+// always retained, as if WithRetainKeys were set. The entries slice is not
+// modified.
+func NewSortedMap[T any](entries []MapEntry[T], opts ...Option) (SortedMap[T], error) {
+	var b Builder[T]
+	b.SetOptions(opts...)
+	if b.canonicalizes() {
+		entries = canonicalEntries(entries, &b.buildOptions)
+	}
+	b.entries = entries
+	b.sortEntries()
+	if err := b.removeDuplicates(); err != nil {
+		return SortedMap[T]{}, err
+	}
+
+	size := 0
+	for _, i := range b.order {
+		size += len(b.key(i))
+	}
+	if uint64(size) > math.MaxUint32 {
+		return SortedMap[T]{}, errKeysTooLong
+	}
+
+	var keys strings.Builder
+	keys.Grow(size)
+	sm := SortedMap[T]{
+		ends:         make([]Uint, len(b.order)),
+		values:       make([]T, len(b.order)),
+		keyTransform: b.keyTransform,
+		fold:         b.fold,
+	}
+	for j, i := range b.order {
+		keys.WriteString(b.key(i))
+		sm.ends[j] = Uint(keys.Len())
+		sm.values[j] = entries[i].Value
+	}
+	sm.keys = keys.String()
+	return sm, nil
+}
+
+// Len returns the number of keys in the map.
+func (sm *SortedMap[T]) Len() int {
+	return len(sm.values)
+}
+
+// Bytes returns the number of bytes used by the keys and values of the map.
+func (sm *SortedMap[T]) Bytes() int {
+	var zero T
+	return len(sm.keys) + len(sm.ends)*int(unsafe.Sizeof(Uint(0))) + len(sm.values)*int(unsafe.Sizeof(zero))
+}
+
+// IndexString returns the index of the value in the map for the supplied
+// string, or 0 if the value is not present in the map, like
+// Map.IndexString.
+func (sm *SortedMap[T]) IndexString(s string) Uint {
+	if sm.keyTransform != nil {
+		return sm.IndexBytes(stringBytes(s))
+	}
+	return sortedIndex(sm, s)
+}
+
+// IndexBytes returns the index of the value in the map for the supplied
+// byte slice, or 0 if the value is not present in the map, like
+// Map.IndexBytes. It never allocates and does not retain s, unless the map
+// has a key transform that does.
+func (sm *SortedMap[T]) IndexBytes(s []byte) Uint {
+	if sm.keyTransform != nil {
+		s = sm.keyTransform(s)
+	}
+	return sortedIndex(sm, s)
+}
+
+// AtIndex returns the value in the map at the supplied index.
+func (sm *SortedMap[T]) AtIndex(index Uint) (t T, ok bool) {
+	if index == 0 || index-1 >= Uint(len(sm.values)) {
+		return t, false
+	}
+	return sm.values[index-1], true
+}
+
+// LookupString looks up the supplied string in the map.
+func (sm *SortedMap[T]) LookupString(s string) (t T, ok bool) {
+	return sm.AtIndex(sm.IndexString(s))
+}
+
+// LookupBytes looks up the supplied byte slice in the map. Like
+// Map.LookupBytes, it does not allocate.
+func (sm *SortedMap[T]) LookupBytes(s []byte) (t T, ok bool) {
+	return sm.AtIndex(sm.IndexBytes(s))
+}
+
+// LookupKey looks up the supplied string in the map, and also returns the
+// key stored in the map that is equal to it, like Map.LookupKey.
+func (sm *SortedMap[T]) LookupKey(s string) (key string, t T, ok bool) {
+	return sm.keyAtIndex(sm.IndexString(s))
+}
+
+// LookupKeyBytes looks up the supplied byte slice in the map like
+// LookupKey. It does not allocate.
+func (sm *SortedMap[T]) LookupKeyBytes(s []byte) (key string, t T, ok bool) {
+	return sm.keyAtIndex(sm.IndexBytes(s))
+}
+
+// All returns an iterator over the keys and values of the map, in
+// ascending byte order of keys, like Map.All.
+func (sm *SortedMap[T]) All() iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		for i, t := range sm.values {
+			if !yield(sm.key(i), t) {
+				return
+			}
+		}
+	}
+}
+
+func (sm *SortedMap[T]) keyAtIndex(index Uint) (key string, t T, ok bool) {
+	if t, ok = sm.AtIndex(index); !ok {
+		return "", t, false
+	}
+	return sm.key(int(index - 1)), t, true
+}
+
+// key returns the key of the value at i in values.
+func (sm *SortedMap[T]) key(i int) string {
+	start := Uint(0)
+	if i > 0 {
+		start = sm.ends[i-1]
+	}
+	return sm.keys[start:sm.ends[i]]
+}
+
+// sortedIndex returns the index of the value of s, found by binary search.
+func sortedIndex[T any, S string | []byte](sm *SortedMap[T], s S) Uint {
+	lo, hi := 0, len(sm.ends)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if compareProbe(sm.key(mid), s, sm.fold) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo < len(sm.ends) && compareProbe(sm.key(lo), s, sm.fold) == 0 {
+		return Uint(lo + 1)
+	}
+	return 0
+}
+
+// compareProbe compares key with s, folding ASCII upper case letters of s
+// to lower case if fold is set.
+func compareProbe[S string | []byte](key string, s S, fold bool) int {
+	n := min(len(key), len(s))
+	for i := 0; i < n; i++ {
+		b := s[i]
+		if fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		if key[i] != b {
+			return cmp.Compare(key[i], b)
+		}
+	}
+	return cmp.Compare(len(key), len(s))
+}
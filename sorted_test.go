@@ -0,0 +1,120 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestSortedMap(t *testing.T) {
+	entries := randomSmallStrings(2000, 8)
+	m, err := faststringmap.New(entries, faststringmap.WithRetainKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm, err := faststringmap.NewSortedMap(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sm.Len() != len(entries) {
+		t.Errorf("Len() = %d want %d", sm.Len(), len(entries))
+	}
+
+	// both implement Lookuper with the same indices
+	for _, l := range []faststringmap.Lookuper[uint32]{&m, &sm} {
+		for _, e := range entries {
+			if index := l.IndexString(e.Key); index != m.IndexString(e.Key) {
+				t.Fatalf("%T.IndexString(%q) = %d want %d", l, e.Key, index, m.IndexString(e.Key))
+			}
+			if v, ok := l.LookupBytes([]byte(e.Key)); !ok || v != e.Value {
+				t.Fatalf("%T.LookupBytes(%q) = %v, %v want %v, true", l, e.Key, v, ok, e.Value)
+			}
+			if key, v, ok := l.LookupKey(e.Key); !ok || key != e.Key || v != e.Value {
+				t.Fatalf("%T.LookupKey(%q) = %q, %v, %v", l, e.Key, key, v, ok)
+			}
+		}
+		for _, probe := range []string{"\x00", "\xff\xff\xff\xff\xff\xff\xff\xff\xff", entries[1].Key + "\x00"} {
+			if v, ok := l.LookupString(probe); ok {
+				t.Errorf("%T.LookupString(%q) = %v, true", l, probe, v)
+			}
+		}
+	}
+
+	var keys, wantKeys []string
+	for k := range sm.All() {
+		keys = append(keys, k)
+	}
+	for k := range m.All() {
+		wantKeys = append(wantKeys, k)
+	}
+	if !slices.Equal(keys, wantKeys) {
+		t.Errorf("All() yields %d keys, not those of Map", len(keys))
+	}
+
+	// a Map without retained keys finds values, but not keys
+	pm := faststringmap.NewMap(entries[:10])
+	var plain faststringmap.Lookuper[uint32] = &pm
+	if _, _, ok := plain.LookupKey(entries[0].Key); ok {
+		t.Errorf("LookupKey() of a Map without retained keys reported present")
+	}
+	if _, ok := plain.LookupString(entries[0].Key); !ok {
+		t.Errorf("LookupString() of a Map without retained keys reported not present")
+	}
+
+	key := []byte(entries[0].Key)
+	if n := testing.AllocsPerRun(100, func() { sm.LookupKeyBytes(key) }); n != 0 {
+		t.Errorf("LookupKeyBytes allocates %v times", n)
+	}
+
+	var empty faststringmap.SortedMap[uint32]
+	if v, ok := empty.LookupString(""); ok {
+		t.Errorf("empty LookupString(\"\") = %v, true", v)
+	}
+}
+
+func TestSortedMapOptions(t *testing.T) {
+	sm, err := faststringmap.NewSortedMap([]faststringmap.MapEntry[int]{{"Alpha", 1}, {" beta", 2}},
+		faststringmap.WithFold(), faststringmap.WithKeyTransform(func(b []byte) []byte { return []byte(strings.TrimSpace(string(b))) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for probe, want := range map[string]int{"ALPHA": 1, " alpha ": 1, "Beta": 2} {
+		if v, ok := sm.LookupString(probe); !ok || v != want {
+			t.Errorf("LookupString(%q) = %v, %v want %v, true", probe, v, ok, want)
+		}
+	}
+
+	dups := []faststringmap.MapEntry[int]{{"a", 1}, {"a", 2}}
+	if _, err := faststringmap.NewSortedMap(dups); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("NewSortedMap(duplicates) error = %v want ErrDuplicateKey", err)
+	}
+	last, err := faststringmap.NewSortedMap(dups, faststringmap.WithDuplicates(faststringmap.DuplicatesKeepLast))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := last.LookupString("a"); !ok || v != 2 {
+		t.Errorf("LookupString(a) = %v, %v want 2, true", v, ok)
+	}
+}
+
+func BenchmarkSortedMap(b *testing.B) {
+	entries := randomSmallStrings(nStrsBench, 8)
+	m := faststringmap.NewMap(entries)
+	sm, err := faststringmap.NewSortedMap(entries)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for name, l := range map[string]faststringmap.Lookuper[uint32]{"Map": &m, "SortedMap": &sm} {
+		b.Run(name, func(b *testing.B) {
+			for bi := 0; bi < b.N; bi++ {
+				for _, e := range entries {
+					l.LookupString(e.Key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package faststringmap
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// SerializedMap[T] wraps a Map together with the codec used to serialize it,
+// and implements driver.Valuer and sql.Scanner, so maps can be stored in and
+// loaded from BLOB columns directly:
+//
+//	sm := faststringmap.SerializedMap[int]{Codec: faststringmap.IntCodec[int]{}}
+//	err := db.QueryRow("SELECT dict FROM dicts WHERE version = ?", v).Scan(&sm)
+type SerializedMap[T any] struct {
+	Map   Map[T]
+	Codec ValueCodec[T]
+}
+
+// Value implements driver.Valuer, returning the serialized map.
+func (sm SerializedMap[T]) Value() (driver.Value, error) {
+	if sm.Codec == nil {
+		return nil, fmt.Errorf("faststringmap: SerializedMap has no codec")
+	}
+	return sm.Map.AppendBinary(nil, sm.Codec)
+}
+
+// Scan implements sql.Scanner, loading the map from a serialized map stored
+// as []byte or string. The data is copied, as database drivers may reuse the
+// memory of scanned values.
+func (sm *SerializedMap[T]) Scan(src any) error {
+	if sm.Codec == nil {
+		return fmt.Errorf("faststringmap: SerializedMap has no codec")
+	}
+
+	var data []byte
+	switch src := src.(type) {
+	case []byte:
+		data = append([]byte(nil), src...)
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("faststringmap: can not scan %T into SerializedMap", src)
+	}
+
+	m, err := UnmarshalMap(data, sm.Codec)
+	if err != nil {
+		return err
+	}
+	sm.Map = m
+	return nil
+}
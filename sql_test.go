@@ -0,0 +1,43 @@
+package faststringmap_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+var (
+	_ driver.Valuer = faststringmap.SerializedMap[int]{}
+	_ sql.Scanner   = &faststringmap.SerializedMap[int]{}
+)
+
+func TestSerializedMapValueScan(t *testing.T) {
+	in := faststringmap.SerializedMap[uint32]{
+		Map:   faststringmap.NewMap(goldenEntries),
+		Codec: faststringmap.IntCodec[uint32]{},
+	}
+
+	v, err := in.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	blob, ok := v.([]byte)
+	if !ok {
+		t.Fatalf("Value() = %T want []byte", v)
+	}
+
+	for _, src := range []any{blob, string(blob)} {
+		out := faststringmap.SerializedMap[uint32]{Codec: faststringmap.IntCodec[uint32]{}}
+		if err := out.Scan(src); err != nil {
+			t.Fatalf("Scan(%T) error = %v", src, err)
+		}
+		checkEntries(t, &out.Map, goldenEntries)
+	}
+
+	out := faststringmap.SerializedMap[uint32]{Codec: faststringmap.IntCodec[uint32]{}}
+	if err := out.Scan(42); err == nil {
+		t.Errorf("Scan(42) error = nil want an error")
+	}
+}
@@ -0,0 +1,101 @@
+package faststringmap
+
+import (
+	"unsafe"
+)
+
+// Stats describes the memory layout of a Map.
+type Stats struct {
+	Keys           int // number of keys
+	Nodes          int // number of nodes in the node store
+	AcceptingNodes int // nodes at the end of a key
+	WastedNodes    int // nodes for bytes that do not continue any key
+	Bytes          int // bytes used by the node store, values and per-key extras
+
+	// Depths breaks the node counts down by trie depth, which is the number
+	// of key bytes leading to a node. Depths[0] describes the root.
+	Depths []DepthStats
+}
+
+// DepthStats describes the nodes at a single depth of the trie.
+type DepthStats struct {
+	Nodes          int     // live nodes, which end or continue at least one key
+	AcceptingNodes int     // nodes at the end of a key
+	WastedNodes    int     // nodes for bytes that do not continue any key
+	FanOut         float64 // average number of live children of nodes that have any
+}
+
+// size returns the bytes used by the node store, values and per-key extras
+// of the map.
+func (m *Map[T]) size() int {
+	var zero T
+	return len(m.store)*nodeSize + m.len()*int(unsafe.Sizeof(zero)) +
+		len(m.keys)*int(unsafe.Sizeof("")) + len(m.fingerprints)
+}
+
+// Stats returns statistics describing the layout of the map.
+func (m *Map[T]) Stats() Stats {
+	if m == nil || len(m.store) == 0 {
+		return Stats{}
+	}
+
+	s := Stats{
+		Keys:  m.len(),
+		Nodes: len(m.store),
+		Bytes: m.size(),
+	}
+
+	type item struct {
+		node  Uint
+		depth int
+	}
+	var parents []int // number of nodes with live children, per depth
+	var children []int
+	stack := []item{{0, 0}}
+	for len(stack) > 0 {
+		it := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for len(s.Depths) <= it.depth+1 {
+			s.Depths = append(s.Depths, DepthStats{})
+			parents = append(parents, 0)
+			children = append(children, 0)
+		}
+
+		node := &m.store[it.node]
+		d := &s.Depths[it.depth]
+		d.Nodes++
+		if node.valueOffset != 0 {
+			d.AcceptingNodes++
+			s.AcceptingNodes++
+		}
+
+		live := 0
+		for i := Uint(0); i < Uint(node.nextLen); i++ {
+			child := node.nextLo + i
+			if next := &m.store[child]; next.valueOffset == 0 && next.nextLen == 0 {
+				s.Depths[it.depth+1].WastedNodes++
+				s.WastedNodes++
+				continue
+			}
+			live++
+			stack = append(stack, item{child, it.depth + 1})
+		}
+		if live > 0 {
+			parents[it.depth]++
+			children[it.depth] += live
+		}
+	}
+
+	// the deepest level only ever holds wasted nodes, if any
+	if last := s.Depths[len(s.Depths)-1]; last.Nodes == 0 && last.WastedNodes == 0 {
+		s.Depths = s.Depths[:len(s.Depths)-1]
+	}
+	for i := range s.Depths {
+		if parents[i] > 0 {
+			s.Depths[i].FanOut = float64(children[i]) / float64(parents[i])
+		}
+	}
+
+	return s
+}
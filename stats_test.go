@@ -0,0 +1,43 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestStats(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[uint32]{
+		{"a", 1},
+		{"a1", 2},
+		{"a3", 3},
+		{"b", 4},
+	})
+
+	// root -> 'a', 'b'; 'a' -> '1', '2' (wasted), '3'
+	s := m.Stats()
+	if s.Keys != 4 || s.Nodes != 6 || s.AcceptingNodes != 4 || s.WastedNodes != 1 {
+		t.Errorf("Stats() = %+v want 4 keys, 6 nodes, 4 accepting, 1 wasted", s)
+	}
+	if s.Bytes <= 0 {
+		t.Errorf("Stats().Bytes = %d want > 0", s.Bytes)
+	}
+
+	want := []faststringmap.DepthStats{
+		{Nodes: 1, FanOut: 2},
+		{Nodes: 2, AcceptingNodes: 2, FanOut: 2},
+		{Nodes: 2, AcceptingNodes: 2, WastedNodes: 1},
+	}
+	if len(s.Depths) != len(want) {
+		t.Fatalf("Stats().Depths = %+v want %+v", s.Depths, want)
+	}
+	for i := range want {
+		if s.Depths[i] != want[i] {
+			t.Errorf("Stats().Depths[%d] = %+v want %+v", i, s.Depths[i], want[i])
+		}
+	}
+
+	if s := (*faststringmap.Map[uint32])(nil).Stats(); s.Nodes != 0 || len(s.Depths) != 0 {
+		t.Errorf("nil.Stats() = %+v want zero", s)
+	}
+}
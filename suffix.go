@@ -0,0 +1,84 @@
+package faststringmap
+
+// SuffixMap[T] is a fast read only map from string suffixes to generic type
+// T, for finding the longest key that is a suffix of a string, such as the
+// file extension of a file name. It stores its keys reversed in a Map, and
+// reads probes backwards.
+type SuffixMap[T any] struct {
+	m Map[T]
+}
+
+// NewSuffixMap[T] constructs a new SuffixMap from the provided map entries.
+// The entries slice is not modified.
+func NewSuffixMap[T any](entries []MapEntry[T]) SuffixMap[T] {
+	b := Builder[T]{entries: make([]MapEntry[T], len(entries))}
+	for i, e := range entries {
+		b.entries[i] = MapEntry[T]{reverseString(e.Key), e.Value}
+	}
+	return SuffixMap[T]{m: b.Build()}
+}
+
+// LookupString looks up the supplied string in the map.
+func (sm *SuffixMap[T]) LookupString(s string) (t T, ok bool) {
+	index, n := longestSuffix(&sm.m, s)
+	if n != len(s) {
+		return t, false
+	}
+	return sm.m.AtIndex(index)
+}
+
+// LongestSuffixMatch looks up the longest key in the map that is a suffix of
+// the supplied string, and returns its length and value.
+func (sm *SuffixMap[T]) LongestSuffixMatch(s string) (suffixLen int, t T, ok bool) {
+	index, suffixLen := longestSuffix(&sm.m, s)
+	t, ok = sm.m.AtIndex(index)
+	return suffixLen, t, ok
+}
+
+// LongestSuffixMatchBytes looks up the longest key in the map that is a
+// suffix of the supplied byte slice, like LongestSuffixMatch.
+func (sm *SuffixMap[T]) LongestSuffixMatchBytes(s []byte) (suffixLen int, t T, ok bool) {
+	index, suffixLen := longestSuffix(&sm.m, s)
+	t, ok = sm.m.AtIndex(index)
+	return suffixLen, t, ok
+}
+
+// Len returns the number of keys in the map.
+func (sm *SuffixMap[T]) Len() int {
+	return sm.m.lenOrZero()
+}
+
+// longestSuffix is longestPrefix for a map of reversed keys, reading s
+// backwards.
+func longestSuffix[T any, S string | []byte](m *Map[T], s S) (index Uint, suffixLen int) {
+	if m == nil || len(m.store) == 0 {
+		return 0, 0
+	}
+
+	bv := &m.store[0]
+	index = bv.valueOffset
+	for i := len(s) - 1; i >= 0; i-- {
+		b := s[i]
+		if b < bv.nextOffset {
+			break
+		}
+		ni := b - bv.nextOffset
+		if ni >= bv.nextLen {
+			break
+		}
+		bv = &m.store[bv.nextLo+uint32(ni)]
+		if bv.valueOffset != 0 {
+			index, suffixLen = bv.valueOffset, len(s)-i
+		}
+	}
+
+	return index, suffixLen
+}
+
+func reverseString(s string) string {
+	b := make([]byte, len(s))
+	for i := range b {
+		b[i] = s[len(s)-1-i]
+	}
+	return string(b)
+}
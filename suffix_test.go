@@ -0,0 +1,55 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestSuffixMap(t *testing.T) {
+	m := faststringmap.NewSuffixMap([]faststringmap.MapEntry[string]{
+		{".gz", "gzip"},
+		{".tar", "tar"},
+		{".tar.gz", "tar+gzip"},
+		{"Makefile", "make"},
+	})
+	if m.Len() != 4 {
+		t.Errorf("Len() = %d want 4", m.Len())
+	}
+
+	for _, tc := range []struct {
+		s       string
+		wantLen int
+		want    string
+	}{
+		{"a.gz", 3, "gzip"},
+		{"a.tar.gz", 7, "tar+gzip"},
+		{"a.tar", 4, "tar"},
+		{"a.tar.gz.gz", 3, "gzip"},
+		{"a.xtar.gz", 3, "gzip"},
+		{"Makefile", 8, "make"},
+		{"src/Makefile", 8, "make"},
+	} {
+		n, v, ok := m.LongestSuffixMatch(tc.s)
+		if !ok || n != tc.wantLen || v != tc.want {
+			t.Errorf("LongestSuffixMatch(%q) = %d, %q, %v want %d, %q, true", tc.s, n, v, ok, tc.wantLen, tc.want)
+		}
+		n, v, ok = m.LongestSuffixMatchBytes([]byte(tc.s))
+		if !ok || n != tc.wantLen || v != tc.want {
+			t.Errorf("LongestSuffixMatchBytes(%q) = %d, %q, %v want %d, %q, true", tc.s, n, v, ok, tc.wantLen, tc.want)
+		}
+	}
+
+	for _, s := range []string{"", "gz", "a.zip", "a.gzip"} {
+		if n, v, ok := m.LongestSuffixMatch(s); ok {
+			t.Errorf("LongestSuffixMatch(%q) = %d, %q, expected not to be present", s, n, v)
+		}
+	}
+
+	if v, ok := m.LookupString(".tar.gz"); !ok || v != "tar+gzip" {
+		t.Errorf("LookupString(.tar.gz) = %q, %v want tar+gzip, true", v, ok)
+	}
+	if v, ok := m.LookupString("a.tar.gz"); ok {
+		t.Errorf("LookupString(a.tar.gz) = %q, expected not to be present", v)
+	}
+}
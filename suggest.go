@@ -0,0 +1,119 @@
+package faststringmap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Suggest returns up to n keys of the map closest to s, for "did you mean"
+// messages about unknown keys. Keys are suggested if they are within an
+// edit distance of 1 of s for s of up to 4 bytes, 2 for up to 8 bytes and
+// 3 for longer s, counting insertions, deletions and substitutions of
+// bytes, and transpositions of adjacent bytes. Closer keys come first, and
+// keys at the same distance sharing a longer prefix with s, as typos are
+// rarer at the start of words; ties are in ascending key order. s itself
+// comes first if it is present. Like IndicesUnderPrefix, keys are as
+// stored, and s is transformed and folded like a probe.
+func (m *Map[T]) Suggest(s string, n int) []string {
+	if m == nil || len(m.store) == 0 || n <= 0 {
+		return nil
+	}
+
+	probe := []byte(s)
+	if m.keyTransform != nil {
+		probe = m.keyTransform(probe)
+	}
+	if m.fold {
+		probe = []byte(foldASCII(string(probe)))
+	}
+
+	sg := suggester[T]{m: m, probe: probe, maxDist: suggestMaxDist(len(probe))}
+	row := make([]int, len(probe)+1)
+	for j := range row {
+		row[j] = j
+	}
+	sg.rows = append(sg.rows, row)
+	sg.visit(&m.store[0])
+
+	slices.SortFunc(sg.found, func(a, b suggestion) int {
+		if c := cmp.Compare(a.dist, b.dist); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.prefix, a.prefix); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.key, b.key)
+	})
+	keys := make([]string, 0, min(n, len(sg.found)))
+	for _, f := range sg.found[:min(n, len(sg.found))] {
+		keys = append(keys, f.key)
+	}
+	return keys
+}
+
+// suggestMaxDist returns the largest edit distance of keys suggested for a
+// probe of n bytes.
+func suggestMaxDist(n int) int {
+	return min(max((n+3)/4, 1), 3)
+}
+
+type suggestion struct {
+	key    string
+	dist   int // edit distance from the probe
+	prefix int // length of the prefix shared with the probe
+}
+
+// suggester walks the trie depth first, computing a row of the edit
+// distance matrix between the probe and the key of every node, and
+// skipping subtrees whose keys are all too far from the probe.
+type suggester[T any] struct {
+	m       *Map[T]
+	probe   []byte
+	maxDist int
+
+	key   []byte  // key of the current node
+	rows  [][]int // distances of the prefixes of the probe from key[:d], for each depth d
+	found []suggestion
+}
+
+func (sg *suggester[T]) visit(node *mapInternalNode) {
+	d := len(sg.key)
+	row := sg.rows[d]
+	if node.valueOffset != 0 && row[len(sg.probe)] <= sg.maxDist {
+		key := string(sg.key)
+		sg.found = append(sg.found, suggestion{key, row[len(sg.probe)], commonPrefixLen(key, string(sg.probe))})
+	}
+
+	if len(sg.rows) == d+1 {
+		sg.rows = append(sg.rows, make([]int, len(sg.probe)+1))
+	}
+	next := sg.rows[d+1]
+	for c := 0; c < int(node.nextLen); c++ {
+		child := &sg.m.store[node.nextLo+Uint(c)]
+		if child.valueOffset == 0 && child.nextLen == 0 {
+			continue // not a valid byte
+		}
+		b := node.nextOffset + byte(c)
+
+		next[0] = d + 1
+		closest := next[0]
+		for j := 1; j <= len(sg.probe); j++ {
+			cost := 1
+			if sg.probe[j-1] == b {
+				cost = 0
+			}
+			next[j] = min(row[j]+1, next[j-1]+1, row[j-1]+cost)
+			if d > 0 && j > 1 && b == sg.probe[j-2] && sg.key[d-1] == sg.probe[j-1] {
+				next[j] = min(next[j], sg.rows[d-1][j-2]+1) // transposition
+			}
+			closest = min(closest, next[j])
+		}
+		if closest > sg.maxDist {
+			continue
+		}
+
+		sg.key = append(sg.key, b)
+		sg.visit(child)
+		sg.key = sg.key[:d]
+	}
+}
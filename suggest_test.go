@@ -0,0 +1,50 @@
+package faststringmap_test
+
+import (
+	"slices"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestSuggest(t *testing.T) {
+	m := faststringmap.FromMap(map[string]int{
+		"max_connections": 1, "max_conns": 2, "min_connections": 3, "timeout": 4,
+		"timeouts": 5, "time": 6, "tim": 7, "host": 8, "port": 9, "post": 10, "ast": 11,
+	})
+
+	tests := []struct {
+		s    string
+		n    int
+		want []string
+	}{
+		{"max_connctions", 3, []string{"max_connections", "min_connections"}},
+		{"timeuot", 5, []string{"timeout", "timeouts"}},
+		{"timeout", 2, []string{"timeout", "timeouts"}},
+		{"prot", 5, []string{"port"}},
+		{"hots", 5, []string{"host"}},
+		{"pst", 5, []string{"post", "ast"}},
+		{"timx", 5, []string{"tim", "time"}},
+		{"ti", 5, []string{"tim"}},
+		{"xyzzy", 5, nil},
+		{"host", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := m.Suggest(tt.s, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("Suggest(%q, %d) = %q want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+
+	folded, err := faststringmap.New([]faststringmap.MapEntry[int]{{"Content-Type", 1}, {"Content-Length", 2}}, faststringmap.WithFold())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := folded.Suggest("CONTENT-TYEP", 2), []string{"content-type"}; !slices.Equal(got, want) {
+		t.Errorf("Suggest(CONTENT-TYEP) = %q want %q", got, want)
+	}
+
+	var empty *faststringmap.Map[int]
+	if got := empty.Suggest("x", 1); got != nil {
+		t.Errorf("nil Suggest(x) = %q", got)
+	}
+}
@@ -0,0 +1,41 @@
+package faststringmap
+
+import "fmt"
+
+// FuncMapLookup[T] returns a function looking up keys in m, returning the
+// zero value of T for keys that are not present, for use as a text/template
+// or html/template function. This allows templates to refer to large static
+// dictionaries without converting them to builtin maps:
+//
+//	tmpl := template.New("config").Funcs(template.FuncMap{
+//		"country": faststringmap.FuncMapLookup(&presets.CountryNames),
+//	})
+//	// {{ country "FR" }}
+func FuncMapLookup[T any](m *Map[T]) func(string) T {
+	return func(key string) T {
+		t, _ := m.LookupString(key)
+		return t
+	}
+}
+
+// FuncMapLookupStrict[T] returns a function looking up keys in m like
+// FuncMapLookup, but which returns an error for keys that are not present,
+// which makes template execution stop with that error.
+func FuncMapLookupStrict[T any](m *Map[T]) func(string) (T, error) {
+	return func(key string) (T, error) {
+		t, ok := m.LookupString(key)
+		if !ok {
+			return t, fmt.Errorf("faststringmap: key %q not present", key)
+		}
+		return t, nil
+	}
+}
+
+// AddToFuncMap[T] adds functions for looking up keys in m to funcs, which may
+// be a text/template or an html/template FuncMap. The function called name
+// is from FuncMapLookup, and the function called name followed by "Strict"
+// is from FuncMapLookupStrict.
+func AddToFuncMap[T any](funcs map[string]any, name string, m *Map[T]) {
+	funcs[name] = FuncMapLookup(m)
+	funcs[name+"Strict"] = FuncMapLookupStrict(m)
+}
@@ -0,0 +1,46 @@
+package faststringmap_test
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestFuncMapLookup(t *testing.T) {
+	m := faststringmap.NewMap(goldenEntries)
+	funcs := template.FuncMap{}
+	faststringmap.AddToFuncMap(funcs, "method", &m)
+	tmpl := template.Must(template.New("").Funcs(funcs).Parse(
+		`{{ method "GET" }} {{ method "PATCH" }} {{ method "BREW" }} {{ methodStrict "ß" }}`))
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := sb.String(), "1 5 0 300"; got != want {
+		t.Errorf("Execute() = %q want %q", got, want)
+	}
+
+	strict := template.Must(template.New("").Funcs(funcs).Parse(`{{ methodStrict "BREW" }}`))
+	if err := strict.Execute(&sb, nil); err == nil || !strings.Contains(err.Error(), "BREW") {
+		t.Errorf("Execute() of a missing key error = %v want an error naming the key", err)
+	}
+}
+
+func TestAddToHTMLFuncMap(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[string]{{"greeting", "<hello>"}})
+	funcs := htmltemplate.FuncMap{}
+	faststringmap.AddToFuncMap(funcs, "text", &m)
+	tmpl := htmltemplate.Must(htmltemplate.New("").Funcs(funcs).Parse(`<p>{{ text "greeting" }}</p>`))
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := sb.String(), "<p>&lt;hello&gt;</p>"; got != want {
+		t.Errorf("Execute() = %q want %q", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package faststringmap
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// TopKUnderPrefix returns the k entries with the highest scores among the
+// keys starting with prefix, including prefix itself, in descending order
+// of score, and in ascending key order among equal scores. It serves query
+// suggestion, where values carry frequencies and only the best few
+// completions matter: the subtree under prefix is visited once, keeping the
+// best k entries seen in a bounded heap.
+func (m *Map[T]) TopKUnderPrefix(prefix string, k int, score func(T) float64) []MapEntry[T] {
+	node, key := m.prefixStart(prefix)
+	if node == nil || k <= 0 {
+		return nil
+	}
+
+	h := make(topKHeap[T], 0, k)
+	m.walkNode(node, key, func(key []byte, index Uint) bool {
+		v, _ := m.AtIndex(index)
+		s := score(v)
+		if len(h) < k {
+			heap.Push(&h, topKEntry[T]{s, MapEntry[T]{string(key), v}})
+		} else if s > h[0].score {
+			h[0] = topKEntry[T]{s, MapEntry[T]{string(key), v}}
+			heap.Fix(&h, 0)
+		}
+		return true
+	})
+
+	sort.Sort(sort.Reverse(h))
+	entries := make([]MapEntry[T], len(h))
+	for i, e := range h {
+		entries[i] = e.entry
+	}
+	return entries
+}
+
+type topKEntry[T any] struct {
+	score float64
+	entry MapEntry[T]
+}
+
+// topKHeap is a min-heap of entries, with the worst entry kept at the top.
+// Of equal scores, the larger key is worse.
+type topKHeap[T any] []topKEntry[T]
+
+func (h topKHeap[T]) Len() int { return len(h) }
+
+func (h topKHeap[T]) Less(i, j int) bool {
+	if h[i].score != h[j].score {
+		return h[i].score < h[j].score
+	}
+	return h[i].entry.Key > h[j].entry.Key
+}
+
+func (h topKHeap[T]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *topKHeap[T]) Push(x any) { *h = append(*h, x.(topKEntry[T])) }
+
+func (h *topKHeap[T]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
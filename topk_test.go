@@ -0,0 +1,46 @@
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestTopKUnderPrefix(t *testing.T) {
+	m := faststringmap.NewMap([]faststringmap.MapEntry[int]{
+		{"go", 50},
+		{"golang", 90},
+		{"google", 100},
+		{"gopher", 20},
+		{"gob", 20},
+		{"goroutine", 70},
+		{"rust", 95},
+	})
+	score := func(v int) float64 { return float64(v) }
+
+	tests := []struct {
+		prefix string
+		k      int
+		want   []faststringmap.MapEntry[int]
+	}{
+		{"go", 3, []faststringmap.MapEntry[int]{{"google", 100}, {"golang", 90}, {"goroutine", 70}}},
+		{"gop", 5, []faststringmap.MapEntry[int]{{"gopher", 20}}},
+		{"go", 7, []faststringmap.MapEntry[int]{{"google", 100}, {"golang", 90}, {"goroutine", 70}, {"go", 50}, {"gob", 20}, {"gopher", 20}}},
+		{"", 1, []faststringmap.MapEntry[int]{{"google", 100}}},
+		{"java", 3, nil},
+		{"go", 0, nil},
+	}
+	for _, tt := range tests {
+		got := m.TopKUnderPrefix(tt.prefix, tt.k, score)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TopKUnderPrefix(%q, %d) = %v want %v", tt.prefix, tt.k, got, tt.want)
+		}
+	}
+
+	// ties keep the smallest keys
+	got := m.TopKUnderPrefix("go", 2, func(int) float64 { return 1 })
+	if want := []faststringmap.MapEntry[int]{{"go", 50}, {"gob", 20}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TopKUnderPrefix() of equal scores = %v want %v", got, want)
+	}
+}
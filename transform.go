@@ -0,0 +1,70 @@
+package faststringmap
+
+// WithKeyTransform canonicalizes keys with fn, such as by trimming spaces,
+// lower-casing or stripping punctuation. fn is applied to the keys of the
+// entries when the map is built, and to every probe passed to IndexString,
+// IndexBytes and the lookups built on them, so canonicalization lives in a
+// single place instead of at every call site. Keys that are equal after
+// the transform are duplicates (see WithDuplicates).
+//
+// fn must not modify its argument, which may be the memory of a string,
+// nor retain it. It may return a sub-slice of its argument, which does not
+// allocate, or a new slice. With a Builder, the transform applies to the
+// entries added after SetOptions. The transform is not serialized; probes
+// of a map loaded from its serialized form are not transformed.
+func WithKeyTransform(fn func([]byte) []byte) Option {
+	return func(o *buildOptions) { o.keyTransform = fn }
+}
+
+// WithFold makes the map case-insensitive for ASCII letters. Upper case
+// letters of keys are folded to lower case when the map is built, and
+// upper case letters of probes are folded during the traversal, so lookups
+// stay allocation-free. Keys that are equal after folding are duplicates
+// (see WithDuplicates). Folding applies after a key transform. Unlike a key
+// transform, folding is serialized, so maps loaded from the serialized form
+// fold their probes too.
+func WithFold() Option {
+	return func(o *buildOptions) { o.fold = true }
+}
+
+// canonicalizes reports whether keys are transformed or folded when built.
+func (o *buildOptions) canonicalizes() bool {
+	return o.keyTransform != nil || o.fold
+}
+
+// canonicalEntries returns a copy of entries with canonical keys.
+func canonicalEntries[T any](entries []MapEntry[T], o *buildOptions) []MapEntry[T] {
+	out := make([]MapEntry[T], len(entries))
+	for i, e := range entries {
+		out[i] = MapEntry[T]{o.canonicalKey(e.Key), e.Value}
+	}
+	return out
+}
+
+// canonicalKey returns key transformed by the key transform and folded, as
+// set by the options.
+func (o *buildOptions) canonicalKey(key string) string {
+	if o.keyTransform != nil {
+		key = string(o.keyTransform([]byte(key)))
+	}
+	if o.fold {
+		key = foldASCII(key)
+	}
+	return key
+}
+
+// foldASCII returns s with ASCII upper case letters folded to lower case.
+func foldASCII(s string) string {
+	for i := 0; i < len(s); i++ {
+		if 'A' <= s[i] && s[i] <= 'Z' {
+			b := []byte(s)
+			for j := i; j < len(b); j++ {
+				if 'A' <= b[j] && b[j] <= 'Z' {
+					b[j] += 'a' - 'A'
+				}
+			}
+			return string(b)
+		}
+	}
+	return s
+}
@@ -0,0 +1,140 @@
+package faststringmap_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestKeyTransform(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{{" red ", 1}, {"green", 2}, {"blue\t", 3}}
+	m, err := faststringmap.New(entries, faststringmap.WithKeyTransform(bytes.TrimSpace), faststringmap.WithRetainKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		probe string
+		want  int
+		ok    bool
+	}{
+		{"red", 1, true},
+		{"  red", 1, true},
+		{"green\n", 2, true},
+		{"blue", 3, true},
+		{" ", 0, false},
+		{"gre en", 0, false},
+	}
+	for _, tt := range tests {
+		if v, ok := m.LookupString(tt.probe); v != tt.want || ok != tt.ok {
+			t.Errorf("LookupString(%q) = %v, %v want %v, %v", tt.probe, v, ok, tt.want, tt.ok)
+		}
+		if v, ok := m.LookupBytes([]byte(tt.probe)); v != tt.want || ok != tt.ok {
+			t.Errorf("LookupBytes(%q) = %v, %v want %v, %v", tt.probe, v, ok, tt.want, tt.ok)
+		}
+		if v, ok := m.LookupStringBounded(tt.probe + "        "); v != tt.want || ok != tt.ok {
+			t.Errorf("LookupStringBounded(%q) = %v, %v want %v, %v", tt.probe, v, ok, tt.want, tt.ok)
+		}
+	}
+
+	if k, _, ok := m.LookupKey(" red"); !ok || k != "red" {
+		t.Errorf("LookupKey(\" red\") = %q, %v want \"red\", true", k, ok)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() { m.LookupString(" green ") })
+	if allocs != 0 && !minimalProfile {
+		t.Errorf("LookupString allocates %v times with a non-allocating transform", allocs)
+	}
+}
+
+func TestKeyTransformBuilder(t *testing.T) {
+	var b faststringmap.Builder[int]
+	b.SetOptions(faststringmap.WithKeyTransform(bytes.ToLower), faststringmap.WithDuplicates(faststringmap.DuplicatesKeepFirst))
+	b.Add("GET", 1)
+	b.Add("get", 2)
+	b.Add("Post", 3)
+	m := b.Build()
+
+	for probe, want := range map[string]int{"get": 1, "GET": 1, "post": 3, "pOST": 3} {
+		if v, ok := m.LookupString(probe); !ok || v != want {
+			t.Errorf("LookupString(%q) = %v, %v want %v, true", probe, v, ok, want)
+		}
+	}
+
+	if _, err := faststringmap.New([]faststringmap.MapEntry[int]{{"A", 1}, {"a", 2}}, faststringmap.WithKeyTransform(bytes.ToLower)); err == nil {
+		t.Error("New() accepted keys that are equal after the transform")
+	}
+}
+
+func TestWithFold(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{{"Content-Type", 1}, {"accept", 2}, {"X-Request-ID", 3}}
+	m, err := faststringmap.New(entries, faststringmap.WithFold(), faststringmap.WithFingerprints())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		probe string
+		want  int
+		ok    bool
+	}{
+		{"content-type", 1, true},
+		{"CONTENT-TYPE", 1, true},
+		{"Accept", 2, true},
+		{"x-request-id", 3, true},
+		{"x-request-i", 0, false},
+		{"accepts", 0, false},
+	}
+	for _, tt := range tests {
+		if v, ok := m.LookupString(tt.probe); v != tt.want || ok != tt.ok {
+			t.Errorf("LookupString(%q) = %v, %v want %v, %v", tt.probe, v, ok, tt.want, tt.ok)
+		}
+		if v, ok := m.LookupBytes([]byte(tt.probe)); v != tt.want || ok != tt.ok {
+			t.Errorf("LookupBytes(%q) = %v, %v want %v, %v", tt.probe, v, ok, tt.want, tt.ok)
+		}
+	}
+
+	probe := []byte("CONTENT-TYPE")
+	allocs := testing.AllocsPerRun(100, func() {
+		m.LookupString("Content-Type")
+		m.LookupBytes(probe)
+	})
+	if allocs != 0 {
+		t.Errorf("fold lookups allocate %v times", allocs)
+	}
+
+	if n, v, ok := m.LongestPrefixString("CONTENT-TYPE; charset=utf-8"); n != 12 || v != 1 || !ok {
+		t.Errorf("LongestPrefixString() = %d, %v, %v want 12, 1, true", n, v, ok)
+	}
+	if got := m.LookupHierarchy("ACCEPT/Encoding", '/'); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("LookupHierarchy() = %v want [2]", got)
+	}
+
+	data, err := m.AppendBinary(nil, faststringmap.IntCodec[int]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := faststringmap.UnmarshalMap(data, faststringmap.IntCodec[int]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lazy, err := faststringmap.UnmarshalMapLazy(data, faststringmap.IntCodec[int]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, err := faststringmap.OpenReaderAt(bytes.NewReader(data), int64(len(data)), faststringmap.IntCodec[int]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, lm := range map[string]faststringmap.Map[int]{"UnmarshalMap": loaded, "UnmarshalMapLazy": lazy, "OpenReaderAt": remote} {
+		if v, ok := lm.LookupString("X-REQUEST-ID"); v != 3 || !ok {
+			t.Errorf("%s: LookupString() = %v, %v want 3, true", name, v, ok)
+		}
+	}
+
+	if _, err := faststringmap.New([]faststringmap.MapEntry[int]{{"Get", 1}, {"GET", 2}}, faststringmap.WithFold()); err == nil {
+		t.Error("New() accepted keys that are equal after folding")
+	}
+}
@@ -0,0 +1,72 @@
+package faststringmap
+
+import "slices"
+
+// WithBlockSizes sets the sizes, in nodes, of the blocks the node store is
+// built in: the first block has first nodes, and every later block is twice
+// as large as the one before, up to max nodes, which also bounds the memory
+// wasted by the last block. The defaults suit maps of up to a few million
+// keys; builds of tens of millions of keys need fewer, larger blocks. A
+// non-positive size keeps its default. Blocks are always large enough for
+// the children of a node. See Builder.SetBlockSizes.
+func WithBlockSizes(first, max int) Option {
+	return func(o *buildOptions) {
+		o.firstBlockSize = first
+		o.maxBlockSize = max
+	}
+}
+
+// SetBlockSizes sets the sizes of the blocks the node store is built in,
+// like WithBlockSizes.
+func (b *Builder[T]) SetBlockSizes(first, max int) {
+	b.firstBlockSize = first
+	b.maxBlockSize = max
+}
+
+// blockSizes returns the size of the first block and the maximum size of a
+// block of the current build.
+func (o *buildOptions) blockSizes() (first, max int) {
+	first, max = firstBufSize, maxBuildBufSize
+	if o.nodesHint > 0 {
+		first = o.nodesHint
+	}
+	if o.firstBlockSize > 0 {
+		first = o.firstBlockSize
+	}
+	if o.maxBlockSize > 0 {
+		max = o.maxBlockSize
+	}
+	return first, max
+}
+
+// WithCapacity sets how many entries the builder will be given, and about
+// how many nodes the built node store will have, so that both can be
+// allocated once up front instead of grown while building. With an
+// accurate node count, the node store is built in a single block, which
+// the map then uses without copying it. Builder.NodeCount returns the exact
+// count for a set of entries. A non-positive hint is ignored, and
+// WithBlockSizes takes precedence over the node count.
+func WithCapacity(entries, nodes int) Option {
+	return func(o *buildOptions) {
+		o.entriesHint = entries
+		o.nodesHint = nodes
+	}
+}
+
+// Grow grows the capacity of the builder for entries, if necessary, to
+// guarantee room for another n entries without reallocating.
+func (b *Builder[T]) Grow(n int) {
+	b.entries = slices.Grow(b.entries, n)
+}
+
+// NodeCount returns the number of nodes in the node store of a map built
+// from the entries added to the builder, for sizing the block a later build
+// uses (see WithCapacity). It sorts the entries like a build does, and
+// returns an error under the same conditions.
+func (b *Builder[T]) NodeCount() (int, error) {
+	b.sortEntries()
+	if err := b.removeDuplicates(); err != nil {
+		return 0, err
+	}
+	return b.countNodes(), nil
+}
@@ -0,0 +1,102 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestBlockSizes(t *testing.T) {
+	entries := randomSmallStrings(2000, 8)
+
+	tests := []struct {
+		first, max int
+		blocks     func(int) bool
+	}{
+		{0, 0, func(n int) bool { return n > 1 }},
+		{16, 64, func(n int) bool { return n > 100 }},
+		{1 << 20, 0, func(n int) bool { return n == 1 }},
+	}
+	for _, tt := range tests {
+		var b faststringmap.Builder[uint32]
+		b.SetBlockSizes(tt.first, tt.max)
+		for _, e := range entries {
+			b.Add(e.Key, e.Value)
+		}
+		m := b.Build()
+		if r := b.Report(); !tt.blocks(r.Blocks) {
+			t.Errorf("SetBlockSizes(%d, %d): build used %d blocks", tt.first, tt.max, r.Blocks)
+		}
+		for _, e := range entries {
+			if v, ok := m.LookupString(e.Key); !ok || v != e.Value {
+				t.Fatalf("SetBlockSizes(%d, %d): LookupString(%q) = %v, %v want %v, true",
+					tt.first, tt.max, e.Key, v, ok, e.Value)
+			}
+		}
+	}
+
+	m, err := faststringmap.New(entries, faststringmap.WithBlockSizes(4, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := m.LookupString(entries[0].Key); !ok || v != entries[0].Value {
+		t.Errorf("LookupString(%q) = %v, %v want %v, true", entries[0].Key, v, ok, entries[0].Value)
+	}
+}
+
+func TestCapacityHints(t *testing.T) {
+	entries := randomSmallStrings(5000, 8)
+
+	var counter faststringmap.Builder[uint32]
+	for _, e := range entries {
+		counter.Add(e.Key, e.Value)
+	}
+	nodes, err := counter.NodeCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := counter.Build()
+	if r := counter.Report(); r.Nodes != nodes {
+		t.Errorf("NodeCount() = %d want %d", nodes, r.Nodes)
+	}
+
+	var b faststringmap.Builder[uint32]
+	b.SetOptions(faststringmap.WithCapacity(len(entries), nodes))
+	for _, e := range entries {
+		b.Add(e.Key, e.Value)
+	}
+	hinted := b.Build()
+	r := b.Report()
+	if r.Blocks != 1 {
+		t.Errorf("build with an exact node count used %d blocks want 1", r.Blocks)
+	}
+	if want := m.Stats().Bytes; r.BytesAllocated > 2*want {
+		t.Errorf("build with capacity hints allocated %d bytes for a map of %d bytes", r.BytesAllocated, want)
+	}
+	for _, e := range entries {
+		if v, ok := hinted.LookupString(e.Key); !ok || v != e.Value {
+			t.Fatalf("LookupString(%q) = %v, %v want %v, true", e.Key, v, ok, e.Value)
+		}
+	}
+
+	b.Reset()
+	b.Add("a", 1)
+	b.Add("a", 2)
+	if _, err := b.NodeCount(); err == nil {
+		t.Error("NodeCount() accepted duplicate keys")
+	}
+}
+
+func TestBuilderGrow(t *testing.T) {
+	var b faststringmap.Builder[int]
+	b.Grow(100)
+	allocs := testing.AllocsPerRun(1, func() {
+		b.Reset()
+		for i := 0; i < 100; i++ {
+			b.Add("k", i)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("adding 100 entries after Grow(100) allocates %v times want 0", allocs)
+	}
+}
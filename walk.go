@@ -0,0 +1,118 @@
+package faststringmap
+
+// walk calls fn for every key in the map, in ascending byte order, with the
+// index of the key's value. The key slice is reused between calls and must
+// not be retained by fn. Traversal stops early if fn returns false.
+func (m *Map[T]) walk(fn func(key []byte, index Uint) bool) {
+	if m == nil || len(m.store) == 0 {
+		return
+	}
+
+	m.walkNode(&m.store[0], m.keyBuffer(""), fn)
+}
+
+// walkFrame is a node whose children are being walked, and the position of
+// the next child to visit in its range of next nodes.
+type walkFrame struct {
+	node *mapInternalNode
+	i    Uint
+}
+
+// walkStackSize is the depth of keys walked without allocating a stack.
+const walkStackSize = 64
+
+// keyBuffer returns a buffer holding p, with room for the longest key of
+// the map, so walks append to it without allocating.
+func (m *Map[T]) keyBuffer(p string) []byte {
+	n := len(p)
+	if m.maxKeyLen != noKeyLenLimit {
+		n = max(n, m.maxKeyLen)
+	}
+	return append(make([]byte, 0, n), p...)
+}
+
+// walkNode calls fn for the key of node and for every key continuing it,
+// in ascending byte order, with key holding the key of node. It visits the
+// nodes with an explicit stack instead of recursing, so the goroutine stack
+// does not grow with the length of keys. It returns false if fn did.
+func (m *Map[T]) walkNode(node *mapInternalNode, key []byte, fn func([]byte, Uint) bool) bool {
+	if node.valueOffset != 0 && !fn(key, node.valueOffset) {
+		return false
+	}
+
+	var local [walkStackSize]walkFrame
+	stack := append(local[:0], walkFrame{node: node})
+	return m.walkStack(stack, key, len(key), fn)
+}
+
+// walkStack continues a walk in ascending byte order from the frames on
+// stack, the frame at depth d being the node of the key of length base+d,
+// whose bytes are in key. The keys of the nodes of the frames themselves
+// are not visited.
+func (m *Map[T]) walkStack(stack []walkFrame, key []byte, base int, fn func([]byte, Uint) bool) bool {
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if f.i == Uint(f.node.nextLen) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		i := f.i
+		f.i++
+		next := &m.store[f.node.nextLo+i]
+		if next.valueOffset == 0 && next.nextLen == 0 {
+			continue // not a valid next byte
+		}
+
+		key = append(key[:base+len(stack)-1], f.node.nextOffset+byte(i))
+		if next.valueOffset != 0 && !fn(key, next.valueOffset) {
+			return false
+		}
+		if next.nextLen != 0 {
+			stack = append(stack, walkFrame{node: next})
+		}
+	}
+	return true
+}
+
+// walkNodeDesc is like walkNode, in descending byte order: the keys
+// continuing the key of node come before the key of node itself.
+func (m *Map[T]) walkNodeDesc(node *mapInternalNode, key []byte, fn func([]byte, Uint) bool) bool {
+	base := len(key)
+	var local [walkStackSize]walkFrame
+	stack := append(local[:0], walkFrame{node, Uint(node.nextLen)})
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if f.i == 0 {
+			// all children are visited, so the key of the node is next
+			n := f.node
+			stack = stack[:len(stack)-1]
+			if n.valueOffset != 0 && !fn(key[:base+len(stack)], n.valueOffset) {
+				return false
+			}
+			continue
+		}
+
+		f.i--
+		next := &m.store[f.node.nextLo+f.i]
+		if next.valueOffset == 0 && next.nextLen == 0 {
+			continue // not a valid next byte
+		}
+		key = append(key[:base+len(stack)-1], f.node.nextOffset+byte(f.i))
+		stack = append(stack, walkFrame{next, Uint(next.nextLen)})
+	}
+	return true
+}
+
+// descend returns the node reached from node by the bytes of s, or nil if
+// no key continues with s.
+func descend[T any, S string | []byte](m *Map[T], node *mapInternalNode, s S) *mapInternalNode {
+	for i := 0; i < len(s); i++ {
+		ni := s[i] - node.nextOffset // bytes below nextOffset wrap around past nextLen
+		if ni >= node.nextLen {
+			return nil
+		}
+		node = &m.store[node.nextLo+uint32(ni)]
+	}
+	return node
+}
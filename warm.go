@@ -0,0 +1,104 @@
+package faststringmap
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	warmPageSize  = 4096    // stride between touched bytes, at most the OS page size
+	warmChunkSize = 1 << 20 // bytes touched between checks for cancellation
+)
+
+// warmSink receives the bytes read by Warm, so the reads are not optimized away.
+var warmSink uint32
+
+// Warm touches every page of memory backing the map, so that a map loaded
+// with OpenMapped is paged in before it is first used, avoiding page faults
+// on the first lookups. This covers the node store, and for maps with lazily
+// decoded values, the encoded values. In the minimal profile, where files
+// are never mapped, the node store is not touched. The memory is split into
+// chunks that are touched by the supplied number of workers in parallel. If
+// progress is not nil, it is called after every chunk with the number of
+// bytes touched so far and the total, from the goroutine that called Warm.
+// Warm stops early and returns ctx.Err() if ctx is done before all chunks
+// are touched.
+func (m *Map[T]) Warm(ctx context.Context, workers int, progress func(done, total int)) error {
+	if m == nil {
+		return nil
+	}
+
+	regions := [][]byte{storeBytes(m.store)}
+	if m.lazy != nil {
+		if layout, ok := m.lazy.source.(*serialLayout); ok {
+			regions = append(regions, layout.fingerprints, layout.offsets, layout.values)
+		}
+	}
+
+	var chunks [][]byte
+	total := 0
+	for _, r := range regions {
+		total += len(r)
+		for len(r) > 0 {
+			n := len(r)
+			if n > warmChunkSize {
+				n = warmChunkSize
+			}
+			chunks = append(chunks, r[:n])
+			r = r[n:]
+		}
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(chunks) {
+		workers = len(chunks)
+	}
+
+	var next int64 = -1 // index of the last chunk taken by a worker
+	touched := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(chunks) {
+					return
+				}
+				touch(chunks[i])
+				touched <- len(chunks[i])
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(touched)
+	}()
+
+	done := 0
+	for n := range touched {
+		done += n
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+
+	if done < total {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// touch reads one byte of every page of b.
+func touch(b []byte) {
+	var sum byte
+	for i := 0; i < len(b); i += warmPageSize {
+		sum += b[i]
+	}
+	sum += b[len(b)-1]
+	atomic.AddUint32(&warmSink, uint32(sum))
+}
@@ -0,0 +1,60 @@
+package faststringmap_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestWarm(t *testing.T) {
+	entries := randomSmallStrings(100000, 12)
+	path := filepath.Join(t.TempDir(), "random.fstm")
+	if err := os.WriteFile(path, serialize(t, faststringmap.NewMap(entries)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mm, err := faststringmap.OpenMapped[uint32](path, faststringmap.IntCodec[uint32]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mm.Close()
+
+	for _, workers := range []int{0, 1, 4} {
+		last, calls := 0, 0
+		err := mm.Map.Warm(context.Background(), workers, func(done, total int) {
+			if done <= last || done > total {
+				t.Errorf("progress(%d, %d) after %d", done, total, last)
+			}
+			last = done
+			calls++
+		})
+		if err != nil {
+			t.Errorf("Warm(%d workers) error = %v", workers, err)
+		}
+		if calls < 2 {
+			t.Errorf("Warm(%d workers) reported progress %d times want several", workers, calls)
+		}
+	}
+	checkEntries(t, &mm.Map, entries[:100])
+}
+
+func TestWarmCanceled(t *testing.T) {
+	if minimalProfile {
+		t.Skip("Warm does not touch the node store in the minimal profile")
+	}
+	m := faststringmap.NewMap(randomSmallStrings(100000, 12))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := m.Warm(ctx, 2, func(done, total int) { cancel() })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Warm() error = %v want context.Canceled", err)
+	}
+
+	var nilMap *faststringmap.Map[uint32]
+	if err := nilMap.Warm(context.Background(), 1, nil); err != nil {
+		t.Errorf("Warm() of nil map error = %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package faststringmap
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrByteRange is returned when building a map with keys that continue
+	// with all 256 byte values after the same prefix, which a node can not
+	// represent.
+	ErrByteRange = errors.New("faststringmap: keys continue with all 256 byte values")
+
+	// ErrWastefulNode is returned when building a map with WithStrictWaste,
+	// if a node wastes more slots than the waste threshold.
+	ErrWastefulNode = errors.New("faststringmap: node wastes too many slots")
+)
+
+// DefaultWasteThreshold is the number of wasted slots in a single node
+// above which a build reports a WasteWarning, unless WithWasteThreshold sets
+// another threshold.
+const DefaultWasteThreshold = 128
+
+// maxWasteWarnings bounds the number of warnings in a BuildReport.
+const maxWasteWarnings = 16
+
+// WasteWarning describes a node of a built map whose dense range of
+// children has many slots for bytes that continue no key, such as a node
+// where keys continue with both ASCII letters and binary bytes. Every
+// wasted slot costs a node of memory.
+type WasteWarning struct {
+	Prefix   string // bytes of the keys leading to the node
+	Children int    // number of distinct bytes continuing keys after Prefix
+	Wasted   int    // number of slots for bytes that continue no key
+}
+
+func (w WasteWarning) String() string {
+	return fmt.Sprintf("keys after %q continue with %d bytes spread over %d slots",
+		w.Prefix, w.Children, w.Children+w.Wasted)
+}
+
+// WithWasteThreshold sets the number of wasted slots in a single node above
+// which a build reports a WasteWarning in its BuildReport. A negative
+// threshold disables the warnings.
+func WithWasteThreshold(threshold int) Option {
+	return func(o *buildOptions) {
+		o.wasteThreshold = threshold
+		o.wasteThresholdSet = true
+	}
+}
+
+// WithStrictWaste makes builds fail with ErrWastefulNode instead of
+// reporting a WasteWarning, so that pathological keys are caught before
+// they reach production.
+func WithStrictWaste() Option {
+	return func(o *buildOptions) { o.strictWaste = true }
+}
+
+// wasteLimit returns the number of wasted slots a node may have without a
+// warning.
+func (o *buildOptions) wasteLimit() int {
+	switch {
+	case !o.wasteThresholdSet:
+		return DefaultWasteThreshold
+	case o.wasteThreshold < 0:
+		return 1 << 8 // more than a node can have
+	}
+	return o.wasteThreshold
+}
+
+// warnWaste records a node wasting more slots than the waste threshold.
+func (b *Builder[T]) warnWaste(prefix string, children, wasted int) {
+	w := WasteWarning{prefix, children, wasted}
+	if b.strictWaste {
+		if b.err == nil {
+			b.err = fmt.Errorf("%w: %v", ErrWastefulNode, w)
+		}
+		return
+	}
+	if len(b.report.Warnings) < maxWasteWarnings {
+		b.report.Warnings = append(b.report.Warnings, w)
+	}
+}
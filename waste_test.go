@@ -0,0 +1,53 @@
+package faststringmap_test
+
+import (
+	"errors"
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestWasteWarnings(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{{"id:\x01", 1}, {"id:z", 2}, {"id:\xf0", 3}, {"name", 4}}
+
+	var b faststringmap.Builder[int]
+	for _, e := range entries {
+		b.Add(e.Key, e.Value)
+	}
+	m := b.Build()
+	if v, ok := m.LookupString("id:z"); !ok || v != 2 {
+		t.Errorf("LookupString(id:z) = %v, %v want 2, true", v, ok)
+	}
+	want := []faststringmap.WasteWarning{{Prefix: "id:", Children: 3, Wasted: 0xf0 - 3}}
+	if got := b.Report().Warnings; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Report().Warnings = %v want %v", got, want)
+	}
+
+	b.SetOptions(faststringmap.WithWasteThreshold(-1))
+	b.Build()
+	if got := b.Report().Warnings; len(got) != 0 {
+		t.Errorf("Report().Warnings with warnings disabled = %v", got)
+	}
+
+	_, err := faststringmap.New(entries, faststringmap.WithStrictWaste(), faststringmap.WithWasteThreshold(16))
+	if !errors.Is(err, faststringmap.ErrWastefulNode) {
+		t.Errorf("New() with strict waste error = %v want ErrWastefulNode", err)
+	}
+	if _, err := faststringmap.New(entries[2:], faststringmap.WithStrictWaste()); err != nil {
+		t.Errorf("New() with strict waste of a compact map error = %v", err)
+	}
+}
+
+func TestByteRange(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{{"k\x00", 1}, {"k\xff", 2}}
+	if _, err := faststringmap.New(entries); !errors.Is(err, faststringmap.ErrByteRange) {
+		t.Errorf("New() of keys using all byte values error = %v want ErrByteRange", err)
+	}
+
+	var b faststringmap.Builder[int]
+	b.Add("\x00", 1)
+	b.Add("\xff", 2)
+	if _, _, err := b.BuildInto(make([]byte, 1<<16)); !errors.Is(err, faststringmap.ErrByteRange) {
+		t.Errorf("BuildInto() error = %v want ErrByteRange", err)
+	}
+}
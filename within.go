@@ -0,0 +1,73 @@
+package faststringmap
+
+// LookupWithin looks up the supplied string in the map, examining at most
+// maxBytes bytes of it, to bound the work spent on a probe, such as a token
+// of an untrusted message, or to probe input that is not complete yet.
+//
+// If s is longer than maxBytes, it is not found, and more reports whether
+// some key longer than maxBytes starts with s[:maxBytes], so that the
+// lookup might succeed with a larger bound. Otherwise, t and ok are as for LookupString, and more
+// reports whether some key is longer than s and starts with it, so that
+// input continuing s might match a key.
+func (m *Map[T]) LookupWithin(s string, maxBytes int) (t T, ok, more bool) {
+	if m != nil && m.keyTransform != nil {
+		return m.LookupBytesWithin(stringBytes(s), maxBytes)
+	}
+	index, more := indexWithin(m, s, maxBytes)
+	t, ok = m.AtIndex(index)
+	return t, ok, more
+}
+
+// LookupBytesWithin looks up the supplied byte slice in the map, like
+// LookupWithin. It does not retain s. Keys of maps with a key transform are
+// bounded after the transform.
+func (m *Map[T]) LookupBytesWithin(s []byte, maxBytes int) (t T, ok, more bool) {
+	if m != nil && m.keyTransform != nil {
+		s = m.keyTransform(s)
+	}
+	index, more := indexWithin(m, s, maxBytes)
+	t, ok = m.AtIndex(index)
+	return t, ok, more
+}
+
+func indexWithin[T any, S string | []byte](m *Map[T], s S, maxBytes int) (index Uint, more bool) {
+	if m == nil || len(m.store) == 0 {
+		return 0, false
+	}
+
+	n := len(s)
+	truncated := n > maxBytes
+	if truncated {
+		n = max(maxBytes, 0)
+	}
+
+	h := m.fingerprintSeed // fingerprint of the probe, computed on the way
+	bv := &m.store[0]
+	for i := 0; i < n; i++ {
+		b := s[i]
+		if m.fold && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		h = (h ^ uint32(b)) * 16777619
+
+		ni := b - bv.nextOffset // bytes below nextOffset wrap around past nextLen
+		if ni >= bv.nextLen {
+			return 0, false
+		}
+		bv = &m.store[bv.nextLo+uint32(ni)]
+	}
+
+	if bv.valueOffset == 0 && bv.nextLen == 0 {
+		return 0, false // not a valid byte
+	}
+	more = bv.nextLen != 0
+	if truncated {
+		return 0, more
+	}
+
+	index = bv.valueOffset
+	if index != 0 && m.fingerprints != nil {
+		index = m.verifyFingerprint(index, byte(h^h>>8^h>>16^h>>24))
+	}
+	return index, more
+}
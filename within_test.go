@@ -0,0 +1,49 @@
+package faststringmap_test
+
+import (
+	"testing"
+
+	"alon.kr/x/faststringmap"
+)
+
+func TestLookupWithin(t *testing.T) {
+	entries := []faststringmap.MapEntry[int]{{"GET", 1}, {"GETX", 2}, {"POST", 3}}
+	tests := []struct {
+		s        string
+		maxBytes int
+		want     int
+		ok, more bool
+	}{
+		{"GET", 8, 1, true, true},
+		{"GETX", 8, 2, true, false},
+		{"POST", 4, 3, true, false},
+		{"POST", 3, 0, false, true},
+		{"PO", 8, 0, false, true},
+		{"PUT", 8, 0, false, false},
+		{"PUTTING", 3, 0, false, false},
+		{"GETXY", 4, 0, false, false},
+		{"", 0, 0, false, true},
+		{"GET", -1, 0, false, true},
+	}
+	for name, opts := range map[string][]faststringmap.Option{
+		"default":      nil,
+		"fingerprints": {faststringmap.WithFingerprints()},
+	} {
+		m, err := faststringmap.New(entries, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range tests {
+			v, ok, more := m.LookupWithin(tt.s, tt.maxBytes)
+			if v != tt.want || ok != tt.ok || more != tt.more {
+				t.Errorf("%s: LookupWithin(%q, %d) = %v, %v, %v want %v, %v, %v",
+					name, tt.s, tt.maxBytes, v, ok, more, tt.want, tt.ok, tt.more)
+			}
+			v, ok, more = m.LookupBytesWithin([]byte(tt.s), tt.maxBytes)
+			if v != tt.want || ok != tt.ok || more != tt.more {
+				t.Errorf("%s: LookupBytesWithin(%q, %d) = %v, %v, %v want %v, %v, %v",
+					name, tt.s, tt.maxBytes, v, ok, more, tt.want, tt.ok, tt.more)
+			}
+		}
+	}
+}